@@ -0,0 +1,231 @@
+// Command content imports and exports course packages so lessons authored in
+// Markdown and spreadsheets can round-trip through git.
+//
+//	content validate <package>
+//	content import [-dry-run] [-prune [-force]] <package>
+//	content export -course <slug> -out <dir|file.zip>
+//	content reindex [-course <slug>]
+//
+// A package is either a directory or a .zip archive containing course.yaml.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"s29-be/internal/content/adapters/packagefs"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/internal/content/application"
+	"s29-be/internal/content/domain"
+	"s29-be/pkg/cache"
+	"s29-be/pkg/config"
+	"s29-be/pkg/database"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "validate":
+		err = runValidate(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "reindex":
+		err = runReindex(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("%s failed: %v", os.Args[1], err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  content validate <package>")
+	fmt.Fprintln(os.Stderr, "  content import [-dry-run] [-prune [-force]] <package>")
+	fmt.Fprintln(os.Stderr, "  content export -course <slug> -out <dir|file.zip>")
+	fmt.Fprintln(os.Stderr, "  content reindex [-course <slug>]")
+}
+
+func runValidate(args []string) error {
+	if len(args) != 1 {
+		return errors.New("expected exactly one package path")
+	}
+
+	if _, err := readPackage(args[0]); err != nil {
+		return err
+	}
+	fmt.Println("package is valid")
+	return nil
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only print the changes that would be applied")
+	prune := flags.Bool("prune", false, "delete units, lessons, exercises and vocabulary missing from the package")
+	force := flags.Bool("force", false, "with -prune, also delete content learners have progress on")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("expected exactly one package path")
+	}
+
+	pkg, err := readPackage(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	service, err := newPackageService()
+	if err != nil {
+		return err
+	}
+
+	plan, err := service.Import(context.Background(), pkg, domain.ImportOptions{
+		DryRun: *dryRun,
+		Prune:  *prune,
+		Force:  *force,
+	})
+	if err != nil {
+		return err
+	}
+
+	printPlan(plan)
+	return nil
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	courseSlug := flags.String("course", "", "slug of the course to export")
+	out := flags.String("out", "", "output directory, or a path ending in .zip")
+	_ = flags.Parse(args)
+	if *courseSlug == "" || *out == "" {
+		return errors.New("-course and -out are required")
+	}
+
+	service, err := newPackageService()
+	if err != nil {
+		return err
+	}
+
+	pkg, err := service.Export(context.Background(), *courseSlug)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(*out, ".zip") {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		err = packagefs.WriteZip(f, pkg)
+		if err != nil {
+			return err
+		}
+	} else if err := packagefs.WriteDir(*out, pkg); err != nil {
+		return err
+	}
+
+	fmt.Printf("exported %s to %s\n", *courseSlug, *out)
+	return nil
+}
+
+func runReindex(args []string) error {
+	flags := flag.NewFlagSet("reindex", flag.ExitOnError)
+	courseSlug := flags.String("course", "", "slug of the course to reindex; all courses when empty")
+	_ = flags.Parse(args)
+
+	appConfig, err := config.LoadDatabase()
+	if err != nil {
+		return err
+	}
+	repo, err := newContentRepository(appConfig)
+	if err != nil {
+		return err
+	}
+
+	count, err := application.NewSearchService(repo).Reindex(context.Background(), *courseSlug)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("reindexed %d course(s)\n", count)
+	return nil
+}
+
+func readPackage(path string) (*domain.CoursePackage, error) {
+	pkg, issues, err := packagefs.ReadPath(path)
+	for _, issue := range issues {
+		fmt.Fprintln(os.Stderr, issue)
+	}
+	return pkg, err
+}
+
+func newPackageService() (*application.PackageService, error) {
+	appConfig, err := config.LoadDatabase()
+	if err != nil {
+		return nil, err
+	}
+	repo, err := newContentRepository(appConfig)
+	if err != nil {
+		return nil, err
+	}
+	return application.NewPackageService(repo, newContentCache(appConfig)), nil
+}
+
+// newContentCache connects to the API's cache so imports invalidate what it
+// serves. Without Redis the import still runs and cached content expires on
+// its own.
+func newContentCache(appConfig *config.Config) *cache.Cache {
+	client, err := cache.NewClient(&appConfig.Redis)
+	if err != nil {
+		log.Printf("Cache unavailable, cached content will expire on its own: %v", err)
+		return nil
+	}
+	cacheConfig := cache.DefaultCacheConfig()
+	cacheConfig.L1Size = 0
+	return cache.NewCache(client, cacheConfig)
+}
+
+func newContentRepository(appConfig *config.Config) (*repository.ContentRepository, error) {
+	db, err := database.NewFromConfig(&appConfig.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.NewContentRepository(db.GetDB()), nil
+}
+
+func printPlan(plan *domain.ImportPlan) {
+	mode := "applied"
+	if plan.DryRun {
+		mode = "dry run"
+	}
+	fmt.Printf("%s (%s): %d created, %d updated, %d deleted, %d kept\n", plan.CourseSlug, mode, plan.Created, plan.Updated, plan.Deleted, plan.Kept)
+
+	for _, change := range plan.Changes {
+		line := fmt.Sprintf("  %-6s %-10s %s", change.Action, change.Entity, change.Key)
+		if len(change.Fields) > 0 {
+			fields, _ := json.Marshal(change.Fields)
+			line += " " + string(fields)
+		}
+		if change.Learners > 0 {
+			line += fmt.Sprintf(" (progress of %d learner(s))", change.Learners)
+		}
+		fmt.Println(line)
+	}
+}
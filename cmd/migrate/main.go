@@ -0,0 +1,166 @@
+// Command migrate applies the SQL migrations embedded from migrations/ to
+// the database configured by the DB_* variables.
+//
+//	migrate up       apply every pending migration
+//	migrate down     roll back the latest migration
+//	migrate redo     roll back the latest migration and apply it again
+//	migrate status   list migrations and when they were applied
+//	migrate drift    compare the GORM models with the migrated schema
+//
+// A Postgres advisory lock keeps concurrent runs, including servers started
+// with DB_AUTO_MIGRATE=true, from applying a migration twice.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"s29-be/pkg/config"
+	"s29-be/pkg/database"
+	"s29-be/pkg/migrate"
+	"s29-be/pkg/schemadrift"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	if os.Args[1] == "drift" {
+		if err := withDatabase(runDrift); err != nil {
+			log.Fatalf("drift failed: %v", err)
+		}
+		return
+	}
+
+	var run func(context.Context, *migrate.Migrator) error
+	switch os.Args[1] {
+	case "up":
+		run = runUp
+	case "down":
+		run = runDown
+	case "redo":
+		run = runRedo
+	case "status":
+		run = runStatus
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err := withMigrator(run); err != nil {
+		log.Fatalf("%s failed: %v", os.Args[1], err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  migrate up")
+	fmt.Fprintln(os.Stderr, "  migrate down")
+	fmt.Fprintln(os.Stderr, "  migrate redo")
+	fmt.Fprintln(os.Stderr, "  migrate status")
+	fmt.Fprintln(os.Stderr, "  migrate drift")
+}
+
+func withDatabase(run func(context.Context, *gorm.DB) error) error {
+	appConfig, err := config.LoadDatabase()
+	if err != nil {
+		return err
+	}
+	db, err := database.NewFromConfig(&appConfig.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return run(context.Background(), db.GetDB())
+}
+
+func withMigrator(run func(context.Context, *migrate.Migrator) error) error {
+	return withDatabase(func(ctx context.Context, db *gorm.DB) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		migrator, err := migrate.New(sqlDB)
+		if err != nil {
+			return err
+		}
+		return run(ctx, migrator)
+	})
+}
+
+func runUp(ctx context.Context, migrator *migrate.Migrator) error {
+	results, err := migrator.Up(ctx)
+	printResults(results)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Println("no pending migrations")
+	}
+	return nil
+}
+
+func runDown(ctx context.Context, migrator *migrate.Migrator) error {
+	result, err := migrator.Down(ctx)
+	if err != nil {
+		return err
+	}
+	printResults([]*goose.MigrationResult{result})
+	return nil
+}
+
+func runRedo(ctx context.Context, migrator *migrate.Migrator) error {
+	results, err := migrator.Redo(ctx)
+	printResults(results)
+	return err
+}
+
+func runStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.State == goose.StateApplied {
+			appliedAt = status.AppliedAt.Local().Format(time.RFC3339)
+		}
+		fmt.Printf("%-25s %s\n", appliedAt, filepath.Base(status.Source.Path))
+	}
+	return nil
+}
+
+// runDrift fails when the schema differs from what the models expect, so
+// CI can run it after `migrate up`.
+func runDrift(ctx context.Context, db *gorm.DB) error {
+	issues, err := schemadrift.Compare(ctx, db, schemadrift.Models()...)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("schema matches the models")
+		return nil
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	return fmt.Errorf("%d differences between the schema and the models", len(issues))
+}
+
+func printResults(results []*goose.MigrationResult) {
+	for _, result := range results {
+		if result != nil {
+			fmt.Println(result)
+		}
+	}
+}
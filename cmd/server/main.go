@@ -1,69 +1,165 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"os"
-	"os/signal"
-	"syscall"
+	"path/filepath"
 	"time"
 
 	authModule "s29-be/internal/auth"
+	contentModule "s29-be/internal/content"
+	jobsModule "s29-be/internal/jobs"
+	progressModule "s29-be/internal/progress"
 	userModule "s29-be/internal/user"
 	"s29-be/pkg/cache"
+	"s29-be/pkg/config"
 	svcContext "s29-be/pkg/context"
 	"s29-be/pkg/database"
+	"s29-be/pkg/events"
+	"s29-be/pkg/health"
+	"s29-be/pkg/kratos"
+	"s29-be/pkg/lifecycle"
+	"s29-be/pkg/logging"
+	"s29-be/pkg/metrics"
+	"s29-be/pkg/middleware"
+	"s29-be/pkg/migrate"
 	"s29-be/pkg/models"
+	"s29-be/pkg/outbox"
+	"s29-be/pkg/scheduler"
+	"s29-be/pkg/tracing"
+	"s29-be/pkg/worker"
 
 	_ "s29-be/docs" // Generated by swag init
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/skip"
 	"github.com/gofiber/swagger"
-	"github.com/joho/godotenv"
 )
 
+// Published outbox messages are kept this long for debugging before cleanup
+const outboxRetention = 24 * time.Hour
+
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Printf("No .env file found, using environment variables from container: %v", err)
-	}
-
-	db, err := database.NewWithConfig(
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		"disable",
-	)
+	appConfig, err := config.Load()
+	if err != nil {
+		fatal("Failed to load configuration", err)
+	}
+
+	appLogger, err := logging.New(os.Stdout, appConfig.Log)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		fatal("Failed to create logger", err)
 	}
+	slog.SetDefault(appLogger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), appConfig.Tracing)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+
+	// Hooks stop in reverse order: the HTTP server first, then background
+	// work, then Redis and the database, and trace export last
+	lifecycleManager := lifecycle.New()
+	lifecycleManager.Append(lifecycle.Hook{Name: "tracing", Stop: shutdownTracing})
 
-	cacheConfig := cache.NewConfig()
-	cacheClient, err := cache.NewClient(cacheConfig)
+	workerConfig, err := worker.ParseConfig(appConfig.Worker.Queues, appConfig.Worker.MaxAttempts)
 	if err != nil {
-		log.Fatalf("Failed to initialize cache client: %v", err)
+		fatal("Invalid worker configuration", err)
+	}
+	schedulerConfig, err := scheduler.ParseConfig(appConfig.Scheduler.Timezone, appConfig.Scheduler.Schedules)
+	if err != nil {
+		fatal("Invalid scheduler configuration", err)
+	}
+	rateLimits, err := middleware.ParseRateLimits(appConfig.App.RateLimits)
+	if err != nil {
+		fatal("Invalid rate limit configuration", err)
 	}
 
-	app := fiber.New()
+	db, err := database.NewFromConfig(&appConfig.Database)
+	if err != nil {
+		fatal("Failed to initialize database", err)
+	}
+	lifecycleManager.Append(lifecycle.Closer("postgres", db.Close))
+
+	sqlDB, err := db.GetDB().DB()
+	if err != nil {
+		fatal("Failed to get database pool", err)
+	}
+	if err := migrateDatabase(sqlDB, appConfig.Database.AutoMigrate); err != nil {
+		fatal("Database is not migrated", err)
+	}
+
+	cacheClient, err := cache.NewClient(&appConfig.Redis)
+	if err != nil {
+		fatal("Failed to initialize cache client", err)
+	}
+	lifecycleManager.Append(lifecycle.Closer("redis", cacheClient.Close))
+
+	workerRuntime := worker.New(cacheClient, workerConfig)
+
+	if err := metrics.RegisterDB(sqlDB); err != nil {
+		fatal("Failed to register database metrics", err)
+	}
+	if err := metrics.RegisterRedis(cacheClient, workerRuntime.QueueNames()); err != nil {
+		fatal("Failed to register Redis metrics", err)
+	}
+	jobScheduler := scheduler.New(cacheClient, schedulerConfig)
+
+	// In-memory delivery runs subscribers inline and only within this process
+	var eventTransport events.Transport
+	if appConfig.Events.Transport != config.EventsMemory {
+		eventTransport = events.NewRedisTransport(cacheClient, events.DefaultRedisConfig())
+	}
+	eventBus := events.NewBus(eventTransport)
+
+	typedCache := cache.NewCache(cacheClient, cache.DefaultCacheConfig())
+
+	rateLimiter := middleware.NewRateLimiter(cacheClient, rateLimits)
+
+	// Handlers return errors; the error handler renders them as problem
+	// documents, hiding the causes of server errors in production
+	fiberConfig := fiber.Config{
+		ErrorHandler: middleware.ErrorHandler(middleware.ErrorHandlerConfig{
+			ShowDetails: !appConfig.IsProduction(),
+		}),
+	}
+	// Behind a load balancer c.IP() would be the balancer's address, putting
+	// every client in one rate limit bucket
+	if proxies := appConfig.App.TrustedProxyList(); len(proxies) > 0 {
+		fiberConfig.ProxyHeader = appConfig.App.ProxyHeader
+		fiberConfig.EnableTrustedProxyCheck = true
+		fiberConfig.TrustedProxies = proxies
+		fiberConfig.EnableIPValidation = true
+	}
+	app := fiber.New(fiberConfig)
 
 	// Recover middleware - recovers from panics
 	app.Use(recover.New())
 
 	// CORS middleware
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization,X-Requested-With",
+		AllowOrigins:  "*",
+		AllowMethods:  "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
+		AllowHeaders:  "Origin,Content-Type,Accept,Authorization,X-Requested-With,X-Request-ID,traceparent,tracestate",
+		ExposeHeaders: "X-Request-ID",
 	}))
 
-	// // Custom logger middleware
-	// app.Use(middleware.Logger())
+	// Negotiates the response language from ?lang= or Accept-Language
+	app.Use(middleware.Locale())
+
+	// Continues the caller's trace and starts a span for each request
+	app.Use(middleware.Tracing())
 
-	app.Use(logger.New())
+	// Counts requests and their latency by route
+	app.Use(middleware.Metrics())
+
+	// Tags the request context with an ID and logs each request
+	app.Use(middleware.Logger())
 
 	// Swagger route
 	app.Get("/swagger/*", swagger.New(swagger.Config{
@@ -72,9 +168,20 @@ func main() {
 	}))
 
 	v1 := app.Group("/api/v1")
+	// Token validation is called by other services and has its own limit
+	apiLimit := rateLimiter.Limit("api", cache.RateLimit{Requests: 600, Window: time.Minute}, middleware.KeyByIP)
+	v1.Use(skip.New(apiLimit, func(c *fiber.Ctx) bool {
+		return c.Path() == "/api/v1/auth/validate"
+	}))
 	internalAPI := v1.Group("/internal")
 
-	serviceContext := svcContext.NewServiceContext(db.GetDB(), app, &v1, &internalAPI, cacheClient)
+	serviceContext := svcContext.NewServiceContext(appConfig, db.GetDB(), app, &v1, &internalAPI, cacheClient)
+	serviceContext.SetWorker(workerRuntime)
+	serviceContext.SetScheduler(jobScheduler)
+	serviceContext.SetEventBus(eventBus)
+	serviceContext.SetCache(typedCache)
+	serviceContext.SetRateLimiter(rateLimiter)
+	serviceContext.SetLifecycle(lifecycleManager)
 
 	authModule := authModule.NewAuthModule(serviceContext)
 	authModule.RegisterRoutes(v1)
@@ -82,48 +189,153 @@ func main() {
 	userModule := userModule.NewUserModule(serviceContext)
 	userModule.RegisterRoutes(v1)
 
-	app.Get("/health", HealthHandler)
+	contentModule := contentModule.NewContentModule(serviceContext)
+	contentModule.RegisterRoutes(v1)
 
-	app.Get("/ping", PingHandler)
+	progressModule := progressModule.NewProgressModule(serviceContext)
+	progressModule.RegisterRoutes(v1)
 
-	go func() {
-		if err := app.Listen(":8080"); err != nil {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
+	// Every replica runs a relay; an advisory lock keeps only one publishing
+	outboxRelay := outbox.NewRelay(db.GetDB(), eventBus, outbox.RelayConfig{Interval: time.Second})
+	jobScheduler.Register(scheduler.Job{
+		Name:     "outbox-cleanup",
+		Schedule: "15 * * * *",
+		Timeout:  10 * time.Minute,
+		Run: func(ctx context.Context, run *scheduler.Run) error {
+			removed, err := outboxRelay.Cleanup(ctx, time.Now().Add(-outboxRetention))
+			if err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "Removed published outbox messages", slog.Int64("count", removed))
+			return nil
+		},
+	})
+
+	// Registered last: it stores the runs of jobs registered by the modules above
+	jobsModule := jobsModule.NewJobsModule(serviceContext)
+	jobsModule.RegisterRoutes(v1)
+
+	// API-only replicas can leave job processing to others
+	if appConfig.Worker.Enabled {
+		lifecycleManager.Append(lifecycle.Hook{
+			Name: "worker",
+			Start: func(context.Context) error {
+				workerRuntime.Start()
+				return nil
+			},
+			// Lets running jobs finish; unfinished ones go back to their queue
+			Stop: workerRuntime.Shutdown,
+		})
+	}
+
+	// Subscribers were registered by the module constructors above; on stop
+	// the events being handled finish before Redis closes
+	lifecycleManager.Append(lifecycle.Cancellable("events", func(ctx context.Context) error {
+		eventBus.Start(ctx)
+		return nil
+	}, eventBus.Wait))
+	lifecycleManager.Append(lifecycle.Background("outbox-relay", outboxRelay.Run))
+	lifecycleManager.Append(lifecycle.Background("cache-invalidation", typedCache.Run))
+
+	// Every replica may run the scheduler; locks keep each tick to one replica
+	if appConfig.Scheduler.Enabled {
+		lifecycleManager.Append(lifecycle.Cancellable("scheduler", jobScheduler.Start, jobScheduler.Wait))
+	}
+
+	// Every replica runs a promoter; a leader lock keeps only one active
+	delayedPromoter := cache.NewPromoter(cacheClient, workerRuntime.QueueNames(), cache.PromoterConfig{Interval: time.Second})
+	if err := metrics.RegisterPromoter(delayedPromoter); err != nil {
+		fatal("Failed to register delayed job metrics", err)
+	}
+	lifecycleManager.Append(lifecycle.Background("delayed-promoter", delayedPromoter.Run))
+
+	// Probes are checked against Postgres and Redis, which every request
+	// needs, and Kratos, without which only sign-in fails
+	healthConfig := health.DefaultConfig()
+	healthConfig.ShowErrors = !appConfig.IsProduction()
+	healthChecker := health.New(healthConfig)
+	healthChecker.Register(health.Check{Name: "postgres", Check: sqlDB.PingContext, Critical: true})
+	healthChecker.Register(health.Check{Name: "redis", Check: cacheClient.Ping, Critical: true})
+	healthChecker.Register(health.Check{Name: "kratos", Check: kratos.NewClient(appConfig.Kratos.PublicURL, appConfig.Kratos.AdminURL).Ping})
 
-	c := make(chan os.Signal, 1)                    // Create channel to signify a signal being sent
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM) // When an interrupt or termination signal is sent, notify the channel
+	app.Get("/livez", healthChecker.Livez)
+	app.Get("/readyz", healthChecker.Readyz)
+	app.Get("/health", healthChecker.Readyz)
 
-	<-c // This blocks the main thread until an interrupt is received
-	fmt.Println("Gracefully shutting down...")
-	_ = app.Shutdown()
+	app.Get("/ping", PingHandler)
 
-	fmt.Println("Running cleanup tasks...")
+	// Served on its own port so /metrics stays off the public router
+	if appConfig.Metrics.Enabled {
+		metricsServer := metrics.NewServer(appConfig.Metrics)
+		lifecycleManager.Append(lifecycle.Hook{
+			Name: "metrics",
+			Start: func(context.Context) error {
+				return metricsServer.Start()
+			},
+			Stop: metricsServer.Shutdown,
+		})
+	}
 
-	// Your cleanup tasks go here
-	// db.DB.
-	cacheClient.Close()
-	fmt.Println("Fiber was successful shutdown.")
+	lifecycleManager.Append(lifecycle.Hook{
+		Name: "http",
+		Start: func(context.Context) error {
+			listener, err := net.Listen("tcp", fmt.Sprintf(":%d", appConfig.App.Port))
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := app.Listener(listener); err != nil {
+					fatal("Failed to start server", err)
+				}
+			}()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			// Fail readiness first so the load balancer stops sending new
+			// requests before the listener closes
+			healthChecker.Drain()
+			select {
+			case <-time.After(appConfig.App.DrainDelay):
+			case <-ctx.Done():
+			}
+			// Waits for in-flight requests to finish
+			return app.ShutdownWithContext(ctx)
+		},
+	})
 
+	// Blocks until SIGINT or SIGTERM, then stops everything within
+	// SHUTDOWN_TIMEOUT; a second signal exits at once
+	if err := lifecycleManager.Run(appConfig.App.ShutdownTimeout); err != nil {
+		fatal("Server did not shut down cleanly", err)
+	}
+	slog.Info("Fiber was successful shutdown.")
 }
 
-// HealthHandler godoc
-// @Summary      Health check endpoint
-// @Description  Returns health status of the service with timestamp
-// @Tags         health
-// @Accept       json
-// @Produce      json
-// @Success      200  {object}  models.HealthResponse
-// @Failure      500  {object}  models.ErrorResponse
-// @Router       /health [get]
-func HealthHandler(c *fiber.Ctx) error {
-	response := models.HealthResponse{
-		Status:    "ok",
-		Timestamp: time.Now().Format(time.RFC3339),
-		Version:   "1.0.0",
+// migrateDatabase applies pending migrations when autoMigrate is set and
+// then checks none are left, so the server never runs against an older
+// schema than its queries expect.
+func migrateDatabase(db *sql.DB, autoMigrate bool) error {
+	ctx := context.Background()
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return err
 	}
-	return c.JSON(response)
+	if autoMigrate {
+		results, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			slog.Info("Applied migration", slog.String("migration", filepath.Base(result.Source.Path)), slog.Duration("took", result.Duration))
+		}
+	}
+	return migrator.Check(ctx)
+}
+
+// fatal logs a failure that leaves the process unable to serve and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
 
 // PingHandler godoc
@@ -6,6 +6,7 @@ import (
 	appError "s29-be/pkg/error"
 	jsonResponse "s29-be/pkg/json"
 	"s29-be/pkg/jwt"
+	"s29-be/pkg/validation"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,11 +17,11 @@ type AuthHandler struct {
 }
 
 type LoginRequest struct {
-	SessionToken string `json:"session_token" binding:"required"`
+	SessionToken string `json:"session_token" validate:"required"`
 }
 
 type RefreshTokenRequest struct {
-	SessionToken string `json:"session_token" binding:"required"` // NEW: Required for security
+	SessionToken string `json:"session_token" validate:"required"`
 }
 
 func NewAuthHandler(authService *application.AuthService) *AuthHandler {
@@ -29,20 +30,6 @@ func NewAuthHandler(authService *application.AuthService) *AuthHandler {
 	}
 }
 
-func (h *AuthHandler) HandleError(c *fiber.Ctx, err error) bool {
-	if err == nil {
-		return false
-	}
-
-	if appErr, ok := appError.GetAppError(err); ok {
-		jsonResponse.ResponseJSON(c, appErr.StatusCode, appErr.Message, appErr.Data)
-		return true
-	}
-
-	jsonResponse.ResponseInternalError(c, err)
-	return true
-}
-
 // @Summary Login
 // @Description Login with Kratos session token to receive Audora JWT
 // @Tags Auth
@@ -50,18 +37,17 @@ func (h *AuthHandler) HandleError(c *fiber.Ctx, err error) bool {
 // @Produce json
 // @Param loginRequest body LoginRequest true "Login Request"
 // @Success 200 {object} domain.LoginResponse
+// @Failure default {object} models.ErrorResponse
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var request LoginRequest
-	if err := c.BodyParser(&request); err != nil {
-		jsonResponse.ResponseBadRequest(c, "Invalid request: "+err.Error())
-		return nil
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
 	}
 
-	response, err := h.authService.VerifySessionAndIssueJWT(request.SessionToken)
+	response, err := h.authService.VerifySessionAndIssueJWT(c.UserContext(), request.SessionToken)
 	if err != nil {
-		h.HandleError(c, err)
-		return nil
+		return err
 	}
 
 	jsonResponse.ResponseOK(c, response)
@@ -75,19 +61,18 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 // @Produce json
 // @Param refreshTokenRequest body RefreshTokenRequest true "Refresh Token Request"
 // @Success 200 {object} domain.LoginResponse
+// @Failure default {object} models.ErrorResponse
 // @Router /api/v1/auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	var request RefreshTokenRequest
-	if err := c.BodyParser(&request); err != nil {
-		jsonResponse.ResponseBadRequest(c, "Invalid request: "+err.Error())
-		return nil
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
 	}
 
 	// SECURITY FIX: Pass both the current JWT and session token for validation
-	response, err := h.authService.RefreshToken(request.SessionToken)
+	response, err := h.authService.RefreshToken(c.UserContext(), request.SessionToken)
 	if err != nil {
-		h.HandleError(c, err)
-		return nil
+		return err
 	}
 
 	jsonResponse.ResponseOK(c, response)
@@ -101,18 +86,17 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 // @Produce json
 // @Param Authorization header string true "Bearer {token}"
 // @Success 200 {object} domain.UserInfo
+// @Failure default {object} models.ErrorResponse
 // @Router /api/v1/auth/me [get]
 func (h *AuthHandler) Me(c *fiber.Ctx) error {
 	claims := c.Locals("user_claims")
 	if claims == nil {
-		jsonResponse.ResponseUnauthorized(c)
-		return nil
+		return appError.NewUnauthorizedError(nil, "")
 	}
 
 	userInfo, err := h.authService.GetCurrentUser(claims.(*jwt.Claims))
 	if err != nil {
-		h.HandleError(c, err)
-		return nil
+		return err
 	}
 
 	jsonResponse.ResponseOK(c, userInfo)
@@ -126,25 +110,23 @@ func (h *AuthHandler) Me(c *fiber.Ctx) error {
 // @Produce json
 // @Param Authorization header string true "Bearer {token}"
 // @Success 200 {object} domain.UserInfo
+// @Failure default {object} models.ErrorResponse
 // @Router /api/v1/auth/validate [post]
 func (h *AuthHandler) ValidateToken(c *fiber.Ctx) error {
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
-		jsonResponse.ResponseUnauthorized(c)
-		return nil
+		return appError.NewUnauthorizedError(nil, "")
 	}
 
 	// Extract Bearer token
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 	if tokenString == authHeader {
-		jsonResponse.ResponseBadRequest(c, "Invalid authorization header format")
-		return nil
+		return appError.NewBadRequestError(nil, "Invalid authorization header format")
 	}
 
-	claims, err := h.authService.ValidateJWT(tokenString)
+	claims, err := h.authService.ValidateJWT(c.UserContext(), tokenString)
 	if err != nil {
-		h.HandleError(c, err)
-		return nil
+		return err
 	}
 
 	userInfo := &domain.UserInfo{
@@ -1,50 +1,45 @@
 package http
 
 import (
-	"log"
+	"log/slog"
 	"s29-be/internal/auth/domain"
+	appError "s29-be/pkg/error"
 	jsonResponse "s29-be/pkg/json"
-	"time"
+	"s29-be/pkg/validation"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
 func (h *AuthHandler) AfterRecovery(c *fiber.Ctx) error {
+	ctx := c.UserContext()
 	var request domain.RecoveryWebhookRequest
-	if err := c.BodyParser(&request); err != nil {
-		log.Printf("Failed to parse recovery webhook request: %v", err)
-		jsonResponse.ResponseBadRequest(c, "Invalid request: "+err.Error())
-		return nil
+	if err := validation.BindBody(c, &request); err != nil {
+		slog.WarnContext(ctx, "Failed to parse recovery webhook request", slog.Any("error", err))
+		return err
 	}
 
 	kratosIdentityID, err := uuid.Parse(request.Identity.ID)
 	if err != nil {
-		log.Printf("Invalid Kratos identity ID in recovery webhook: %v", err)
-		jsonResponse.ResponseBadRequest(c, "Invalid identity ID")
-		return nil
+		slog.WarnContext(ctx, "Invalid Kratos identity ID in recovery webhook", slog.Any("error", err))
+		return appError.NewBadRequestError(nil, "Invalid identity ID")
 	}
 
-	user, err := h.authService.FindUserByKratosIdentityID(kratosIdentityID)
+	user, err := h.authService.FindUserByKratosIdentityID(ctx, kratosIdentityID)
 	if err != nil {
-		log.Printf("User not found during recovery webhook: %v", err)
+		slog.WarnContext(ctx, "User not found during recovery webhook", slog.String("kratos_identity_id", kratosIdentityID.String()), slog.Any("error", err))
 		jsonResponse.ResponseOK(c, fiber.Map{"message": "Recovery processed"})
 		return nil
 	}
 
-	log.Printf("Password recovery completed for user ID: %s, Email: %s", user.ID, user.Email)
+	slog.InfoContext(ctx, "Password recovery completed", slog.String("user_id", user.ID.String()))
 
-	// Optional: Update last login time or add recovery audit log
-	now := time.Now()
-	user.LastLoginAt = &now
-	if err := h.authService.UpdateUserLastLogin(user); err != nil {
-		log.Printf("Failed to update last login time after recovery: %v", err)
+	// Subscribers to UserRecovered can notify the user about the reset
+	if err := h.authService.CompleteRecovery(ctx, user, request.RecoveryInfo.RecoveryMethod, request.RecoveryInfo.RecoveredAt); err != nil {
+		slog.ErrorContext(ctx, "Failed to record recovery", slog.Any("error", err))
 		// Don't fail the webhook
 	}
 
-	// Optional: Send a notification email about successful password reset
-	// You could implement this with your email service
-
 	jsonResponse.ResponseOK(c, fiber.Map{
 		"message":      "Recovery webhook processed successfully",
 		"user_id":      user.ID,
@@ -1,7 +1,10 @@
 package repository
 
 import (
-	userModel "s29-be/internal/user/domain"
+	"context"
+	"s29-be/internal/auth/domain"
+	"s29-be/pkg/events"
+	"s29-be/pkg/outbox"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,33 +20,52 @@ func NewAuthRepository(db *gorm.DB) *AuthRepository {
 	}
 }
 
-func (r *AuthRepository) FindUserByKratosIdentityID(kratosIdentityID uuid.UUID) (*userModel.User, error) {
-	var user userModel.User
-	err := r.db.Where("kratos_identity_id = ?", kratosIdentityID).First(&user).Error
+// WithContext returns a repository whose queries run under ctx, so they are
+// cancelled with the request and logged with its attributes.
+func (r *AuthRepository) WithContext(ctx context.Context) *AuthRepository {
+	return &AuthRepository{db: r.db.WithContext(ctx)}
+}
+
+// Transaction runs fn against a repository bound to a single database transaction.
+func (r *AuthRepository) Transaction(fn func(txRepo *AuthRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&AuthRepository{db: tx})
+	})
+}
+
+func (r *AuthRepository) FindUserByKratosIdentityID(kratosIdentityID uuid.UUID) (*domain.Account, error) {
+	var account domain.Account
+	err := r.db.Where("kratos_identity_id = ?", kratosIdentityID).First(&account).Error
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return &account, nil
 }
 
-func (r *AuthRepository) FindUserByID(userID uint64) (*userModel.User, error) {
-	var user userModel.User
-	err := r.db.Where("id = ?", userID).First(&user).Error
+func (r *AuthRepository) FindUserByID(userID uint64) (*domain.Account, error) {
+	var account domain.Account
+	err := r.db.Where("id = ?", userID).First(&account).Error
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return &account, nil
 }
 
-func (r *AuthRepository) FindUserByEmail(email string) (*userModel.User, error) {
-	var user userModel.User
-	err := r.db.Where("email = ? AND is_active = ?", email, true).First(&user).Error
+func (r *AuthRepository) FindUserByEmail(email string) (*domain.Account, error) {
+	var account domain.Account
+	err := r.db.Where("email = ? AND is_active = ?", email, true).First(&account).Error
 	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	return &account, nil
+}
+
+func (r *AuthRepository) UpdateUserLastLogin(account *domain.Account) error {
+	return r.db.Model(account).Update("last_login_at", account.LastLoginAt).Error
 }
 
-func (r *AuthRepository) UpdateUserLastLogin(user *userModel.User) error {
-	return r.db.Model(user).Update("last_login_at", user.LastLoginAt).Error
+// AddOutboxEvent stores event for publication once the surrounding
+// transaction commits.
+func (r *AuthRepository) AddOutboxEvent(userID uuid.UUID, event events.Event) error {
+	return outbox.Add(r.db, "user", userID, event)
 }
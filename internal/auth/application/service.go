@@ -2,13 +2,15 @@
 package application
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
 	"s29-be/internal/auth/adapters/repository"
 	"s29-be/internal/auth/domain"
-	model "s29-be/internal/user/domain"
 	appError "s29-be/pkg/error"
+	"s29-be/pkg/events"
 	"s29-be/pkg/jwt"
 	"s29-be/pkg/kratos"
+	"s29-be/pkg/metrics"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,8 +33,8 @@ func NewAuthService(authRepo *repository.AuthRepository, kratosClient *kratos.Cl
 	}
 }
 
-func (s *AuthService) VerifySessionAndIssueJWT(sessionToken string) (*domain.LoginResponse, error) {
-	session, err := s.kratosClient.VerifySession(sessionToken)
+func (s *AuthService) VerifySessionAndIssueJWT(ctx context.Context, sessionToken string) (*domain.LoginResponse, error) {
+	session, err := s.kratosClient.VerifySession(ctx, sessionToken)
 	if err != nil {
 		if kratosErr, ok := err.(*kratos.KratosError); ok {
 			return nil, appError.NewUnauthorizedError(err, kratosErr.Message)
@@ -45,7 +47,7 @@ func (s *AuthService) VerifySessionAndIssueJWT(sessionToken string) (*domain.Log
 		return nil, appError.NewBadRequestError(err, "invalid kratos identity ID")
 	}
 
-	user, err := s.authRepo.FindUserByKratosIdentityID(kratosIdentityID)
+	user, err := s.authRepo.WithContext(ctx).FindUserByKratosIdentityID(kratosIdentityID)
 	if err != nil {
 		return nil, appError.NewNotFoundError(err, "user not found in Audora database")
 	}
@@ -56,8 +58,8 @@ func (s *AuthService) VerifySessionAndIssueJWT(sessionToken string) (*domain.Log
 
 	now := time.Now()
 	user.LastLoginAt = &now
-	if err := s.authRepo.UpdateUserLastLogin(user); err != nil {
-		fmt.Printf("Failed to update last login time: %v\n", err)
+	if err := s.authRepo.WithContext(ctx).UpdateUserLastLogin(user); err != nil {
+		slog.WarnContext(ctx, "Failed to update last login time", slog.Any("error", err))
 	}
 
 	tokenLifetime := 24 * time.Hour // 24 hours
@@ -65,11 +67,13 @@ func (s *AuthService) VerifySessionAndIssueJWT(sessionToken string) (*domain.Log
 		user.ID,
 		user.KratosIdentityID.String(),
 		user.Email,
+		user.Role,
 		user.IsActive,
 	)
 	if err != nil {
 		return nil, appError.NewInternalError(err, "failed to generate access token")
 	}
+	metrics.Logins.Inc()
 
 	return &domain.LoginResponse{
 		AccessToken: accessToken,
@@ -79,12 +83,14 @@ func (s *AuthService) VerifySessionAndIssueJWT(sessionToken string) (*domain.Log
 			ID:               user.ID,
 			KratosIdentityID: user.KratosIdentityID.String(),
 			Email:            user.Email,
+			UserType:         user.Role,
 			IsActive:         user.IsActive,
+			Language:         user.PreferredLanguage,
 		},
 	}, nil
 }
 
-func (s *AuthService) ValidateJWT(tokenString string) (*jwt.Claims, error) {
+func (s *AuthService) ValidateJWT(ctx context.Context, tokenString string) (*jwt.Claims, error) {
 	claims, err := s.jwtService.ValidateToken(tokenString)
 	if err != nil {
 		return nil, appError.NewUnauthorizedError(err, "invalid or expired token")
@@ -95,7 +101,7 @@ func (s *AuthService) ValidateJWT(tokenString string) (*jwt.Claims, error) {
 		return nil, appError.NewUnauthorizedError(err, "invalid identity ID in token")
 	}
 
-	user, err := s.authRepo.FindUserByKratosIdentityID(kratosIdentityID)
+	user, err := s.authRepo.WithContext(ctx).FindUserByKratosIdentityID(kratosIdentityID)
 	if err != nil {
 		return nil, appError.NewUnauthorizedError(err, "user not found")
 	}
@@ -104,16 +110,22 @@ func (s *AuthService) ValidateJWT(tokenString string) (*jwt.Claims, error) {
 		return nil, appError.NewForbiddenError(nil, "user account is deactivated")
 	}
 
+	// Roles can change while a token is still valid, so trust the database
+	claims.UserType = user.Role
+	if user.PreferredLanguage != nil {
+		claims.Language = *user.PreferredLanguage
+	}
+
 	return claims, nil
 }
 
 // FIXED: RefreshToken now validates Kratos session before issuing new JWT
-func (s *AuthService) RefreshToken(sessionToken string) (*domain.LoginResponse, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, sessionToken string) (*domain.LoginResponse, error) {
 	if sessionToken == "" {
 		return nil, appError.NewUnauthorizedError(nil, "session token required for refresh")
 	}
 
-	session, err := s.kratosClient.VerifySession(sessionToken)
+	session, err := s.kratosClient.VerifySession(ctx, sessionToken)
 	if err != nil {
 		if kratosErr, ok := err.(*kratos.KratosError); ok {
 			return nil, appError.NewUnauthorizedError(err, "kratos session invalid: "+kratosErr.Message)
@@ -126,7 +138,7 @@ func (s *AuthService) RefreshToken(sessionToken string) (*domain.LoginResponse,
 		return nil, appError.NewBadRequestError(err, "invalid kratos identity ID")
 	}
 
-	user, err := s.authRepo.FindUserByKratosIdentityID(kratosIdentityID)
+	user, err := s.authRepo.WithContext(ctx).FindUserByKratosIdentityID(kratosIdentityID)
 	if err != nil {
 		return nil, appError.NewNotFoundError(err, "user not found in Audora database")
 	}
@@ -140,6 +152,7 @@ func (s *AuthService) RefreshToken(sessionToken string) (*domain.LoginResponse,
 		user.ID,
 		user.KratosIdentityID.String(),
 		user.Email,
+		user.Role,
 		user.IsActive,
 	)
 	if err != nil {
@@ -154,7 +167,9 @@ func (s *AuthService) RefreshToken(sessionToken string) (*domain.LoginResponse,
 			ID:               user.ID,
 			KratosIdentityID: user.KratosIdentityID.String(),
 			Email:            user.Email,
+			UserType:         user.Role,
 			IsActive:         user.IsActive,
+			Language:         user.PreferredLanguage,
 		},
 	}, nil
 }
@@ -170,10 +185,27 @@ func (s *AuthService) GetCurrentUser(claims *jwt.Claims) (*domain.UserInfo, erro
 	}, nil
 }
 
-func (s *AuthService) FindUserByKratosIdentityID(kratosID uuid.UUID) (*model.User, error) {
-	return s.authRepo.FindUserByKratosIdentityID(kratosID)
+func (s *AuthService) FindUserByKratosIdentityID(ctx context.Context, kratosID uuid.UUID) (*domain.Account, error) {
+	return s.authRepo.WithContext(ctx).FindUserByKratosIdentityID(kratosID)
+}
+
+func (s *AuthService) UpdateUserLastLogin(ctx context.Context, user *domain.Account) error {
+	return s.authRepo.WithContext(ctx).UpdateUserLastLogin(user)
 }
 
-func (s *AuthService) UpdateUserLastLogin(user *model.User) error {
-	return s.authRepo.UpdateUserLastLogin(user)
+// CompleteRecovery records a finished password recovery and announces it
+// with a UserRecovered event.
+func (s *AuthService) CompleteRecovery(ctx context.Context, user *domain.Account, method string, recoveredAt time.Time) error {
+	now := time.Now()
+	user.LastLoginAt = &now
+	return s.authRepo.WithContext(ctx).Transaction(func(repo *repository.AuthRepository) error {
+		if err := repo.UpdateUserLastLogin(user); err != nil {
+			return err
+		}
+		return repo.AddOutboxEvent(user.ID, events.UserRecovered{
+			UserID:      user.ID,
+			Method:      method,
+			RecoveredAt: recoveredAt,
+		})
+	})
 }
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account is the part of a user that authentication reads and updates. It
+// maps the users table owned by the user module without importing it; the
+// modules otherwise talk through events.
+type Account struct {
+	ID                uuid.UUID `gorm:"primaryKey"`
+	KratosIdentityID  uuid.UUID `gorm:"type:uuid"`
+	Email             string
+	IsActive          bool
+	Role              string
+	PreferredLanguage *string
+	LastLoginAt       *time.Time
+	UpdatedAt         time.Time
+}
+
+func (Account) TableName() string {
+	return "users"
+}
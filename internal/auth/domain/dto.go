@@ -20,6 +20,7 @@ type UserInfo struct {
 	DisplayName      string    `json:"display_name"`
 	UserType         string    `json:"user_type"`
 	IsActive         bool      `json:"is_active"`
+	Language         *string   `json:"preferred_language"`
 }
 
 type RecoveryWebhookRequest struct {
@@ -28,7 +29,7 @@ type RecoveryWebhookRequest struct {
 }
 
 type RecoveryIdentityData struct {
-	ID       string                 `json:"id"`
+	ID       string                 `json:"id" validate:"required,uuid"`
 	Traits   map[string]interface{} `json:"traits"`
 	SchemaID string                 `json:"schema_id"`
 	State    string                 `json:"state"`
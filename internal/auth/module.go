@@ -2,10 +2,10 @@
 package auth
 
 import (
-	"os"
 	"s29-be/internal/auth/adapters/http"
 	"s29-be/internal/auth/adapters/repository"
 	"s29-be/internal/auth/application"
+	"s29-be/pkg/cache"
 	"s29-be/pkg/jwt"
 	"s29-be/pkg/kratos"
 	"s29-be/pkg/middleware"
@@ -16,43 +16,42 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// Token endpoints call Kratos, so they get a tighter limit than the API.
+// Validation only checks the JWT and the user row, and is called by other
+// services from a handful of addresses, so it gets a far higher limit of its
+// own and is exempt from the API-wide one.
+var (
+	authRateLimit     = cache.RateLimit{Requests: 10, Window: time.Minute}
+	validateRateLimit = cache.RateLimit{Requests: 6000, Window: time.Minute}
+)
+
 type AuthModule struct {
 	Repository   *repository.AuthRepository
 	Service      *application.AuthService
 	Handler      *http.AuthHandler
 	Middleware   *middleware.AuthMiddleware
+	RateLimiter  *middleware.RateLimiter
 	KratosClient *kratos.Client
 	JWTService   *jwt.JWTService
 }
 
 func NewAuthModule(ctx2 *svcContext.ServiceContext) *AuthModule {
-	// Initialize Kratos client
-	kratosPublicURL := os.Getenv("KRATOS_PUBLIC_URL")
-	kratosAdminURL := os.Getenv("KRATOS_ADMIN_URL")
-	if kratosPublicURL == "" {
-		kratosPublicURL = "http://localhost:4433"
-	}
-	if kratosAdminURL == "" {
-		kratosAdminURL = "http://localhost:4434"
-	}
-	kratosClient := kratos.NewClient(kratosPublicURL, kratosAdminURL)
-
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-jwt-secret-here" // Default for development
-	}
-	jwtService := jwt.NewJWTService(jwtSecret, "audora-api", 24*time.Hour)
+	appConfig := ctx2.GetConfig()
+	kratosClient := kratos.NewClient(appConfig.Kratos.PublicURL, appConfig.Kratos.AdminURL)
+	jwtService := jwt.NewJWTService(appConfig.JWT.Secret, appConfig.JWT.Issuer, appConfig.JWT.TTL)
 
 	authRepo := repository.NewAuthRepository(ctx2.GetDB())
 	authService := application.NewAuthService(authRepo, kratosClient, jwtService)
 	authHandler := http.NewAuthHandler(authService)
 	authMiddleware := middleware.NewAuthMiddleware(authService)
+	ctx2.SetAuthMiddleware(authMiddleware)
 
 	return &AuthModule{
 		Repository:   authRepo,
 		Service:      authService,
 		Handler:      authHandler,
 		Middleware:   authMiddleware,
+		RateLimiter:  ctx2.GetRateLimiter(),
 		KratosClient: kratosClient,
 		JWTService:   jwtService,
 	}
@@ -62,9 +61,11 @@ func (a *AuthModule) RegisterRoutes(router fiber.Router) {
 	auth := router.Group("auth")
 	{
 		// Public endpoints
-		auth.Post("/login", a.Handler.Login)            // Login with session token from body
-		auth.Post("/refresh", a.Handler.RefreshToken)   // Refresh JWT token
-		auth.Post("/validate", a.Handler.ValidateToken) // Validate token (for other services)
+		limit := a.RateLimiter.Limit("auth", authRateLimit, middleware.KeyByIP)
+		validateLimit := a.RateLimiter.Limit("auth-validate", validateRateLimit, middleware.KeyByIP)
+		auth.Post("/login", limit, a.Handler.Login)                    // Login with session token from body
+		auth.Post("/refresh", limit, a.Handler.RefreshToken)           // Refresh JWT token
+		auth.Post("/validate", validateLimit, a.Handler.ValidateToken) // Validate token (for other services)
 
 		// Protected endpoints
 		protected := auth.Group("")
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"s29-be/internal/content/application"
+	"s29-be/internal/content/domain"
+	appError "s29-be/pkg/error"
+	"s29-be/pkg/i18n"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ContentHandler struct {
+	contentService *application.ContentService
+}
+
+func NewContentHandler(contentService *application.ContentService) *ContentHandler {
+	return &ContentHandler{
+		contentService: contentService,
+	}
+}
+
+// @Summary List Courses
+// @Description List published courses with unit and lesson counts
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param level query string false "Filter by level"
+// @Param Accept-Language header string false "Preferred language for titles"
+// @Success 200 {array} domain.CourseSummary
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/courses [get]
+func (h *ContentHandler) ListCourses(c *fiber.Ctx) error {
+	var query domain.ListCoursesQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		return err
+	}
+
+	courses, err := h.contentService.ListCourses(c.UserContext(), &query, i18n.Language(c))
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, courses)
+	return nil
+}
+
+// @Summary Get Course
+// @Description Get a published course with its units and lessons
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param courseId path string true "Course ID"
+// @Param Accept-Language header string false "Preferred language for titles"
+// @Success 200 {object} domain.Course
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/courses/{courseId} [get]
+func (h *ContentHandler) GetCourse(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("courseId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid course ID")
+	}
+
+	course, err := h.contentService.GetCourse(c.UserContext(), courseID, i18n.Language(c))
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, course)
+	return nil
+}
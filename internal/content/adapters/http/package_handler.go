@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"s29-be/internal/content/adapters/packagefs"
+	"s29-be/internal/content/application"
+	"s29-be/internal/content/domain"
+	appError "s29-be/pkg/error"
+	jsonResponse "s29-be/pkg/json"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PackageHandler struct {
+	packageService *application.PackageService
+}
+
+func NewPackageHandler(packageService *application.PackageService) *PackageHandler {
+	return &PackageHandler{
+		packageService: packageService,
+	}
+}
+
+// @Summary Import Course Package
+// @Description Import a zipped course package. With dry_run=true only the diff against the database is returned. Content missing from the package is kept unless prune=true; pruning content learners have progress on also needs force=true and deletes that progress.
+// @Tags Content Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param package formData file true "Zipped course package"
+// @Param dry_run query bool false "Only report the changes"
+// @Param prune query bool false "Delete content missing from the package"
+// @Param force query bool false "With prune, also delete content learners have progress on"
+// @Success 200 {object} domain.ImportPlan
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/import [post]
+func (h *PackageHandler) Import(c *fiber.Ctx) error {
+	data, err := readPackageUpload(c)
+	if err != nil {
+		return appError.NewBadRequestError(err, "Invalid request: "+err.Error())
+	}
+
+	pkg, issues, err := packagefs.ReadZip(data)
+	if err != nil {
+		if errors.Is(err, packagefs.ErrInvalidPackage) {
+			return appError.NewBadRequestError(err, "invalid course package").WithData(issues)
+		}
+		return appError.NewBadRequestError(err, err.Error())
+	}
+
+	var plan *domain.ImportPlan
+	plan, err = h.packageService.Import(c.UserContext(), pkg, domain.ImportOptions{
+		DryRun: c.QueryBool("dry_run"),
+		Prune:  c.QueryBool("prune"),
+		Force:  c.QueryBool("force"),
+	})
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, plan)
+	return nil
+}
+
+// @Summary Export Course Package
+// @Description Export a course with its units, lessons, exercises and vocabulary as a zipped package
+// @Tags Content Admin
+// @Produce application/zip
+// @Param Authorization header string true "Bearer {token}"
+// @Param slug path string true "Course slug"
+// @Success 200 {file} file
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/courses/{slug}/export [get]
+func (h *PackageHandler) Export(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+
+	pkg, err := h.packageService.Export(c.UserContext(), slug)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := packagefs.WriteZip(&buf, pkg); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.zip"`, slug))
+	return c.Send(buf.Bytes())
+}
+
+// readPackageUpload accepts either a multipart "package" file or a raw zip body.
+func readPackageUpload(c *fiber.Ctx) ([]byte, error) {
+	if file, err := c.FormFile("package"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+
+	body := c.Body()
+	if len(body) == 0 {
+		return nil, errors.New("package file is required")
+	}
+	return body, nil
+}
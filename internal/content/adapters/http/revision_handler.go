@@ -0,0 +1,293 @@
+package http
+
+import (
+	"context"
+	"s29-be/internal/content/application"
+	"s29-be/internal/content/domain"
+	appError "s29-be/pkg/error"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RevisionHandler struct {
+	revisionService *application.RevisionService
+}
+
+func NewRevisionHandler(revisionService *application.RevisionService) *RevisionHandler {
+	return &RevisionHandler{
+		revisionService: revisionService,
+	}
+}
+
+func actorFromContext(c *fiber.Ctx) (domain.Actor, bool) {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return domain.Actor{}, false
+	}
+	role, _ := c.Locals("user_type").(string)
+	return domain.Actor{UserID: userID, Role: role}, true
+}
+
+// @Summary List Revisions
+// @Description List content revisions, newest first
+// @Tags Content Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param entity_type query string false "course, unit or lesson"
+// @Param entity_id query string false "Entity ID"
+// @Param state query string false "Revision state"
+// @Success 200 {array} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions [get]
+func (h *RevisionHandler) ListRevisions(c *fiber.Ctx) error {
+	var query domain.ListRevisionsQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		return err
+	}
+
+	revisions, err := h.revisionService.ListRevisions(c.UserContext(), &query)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, revisions)
+	return nil
+}
+
+// @Summary Get Revision
+// @Tags Content Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId} [get]
+func (h *RevisionHandler) GetRevision(c *fiber.Ctx) error {
+	revisionID, err := uuid.Parse(c.Params("revisionId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid revision ID")
+	}
+
+	revision, err := h.revisionService.GetRevision(c.UserContext(), revisionID)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, revision)
+	return nil
+}
+
+// @Summary Create Draft Revision
+// @Description Start a draft for a course, unit or lesson. Without a snapshot the draft copies the live content.
+// @Tags Content Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param createRevisionRequest body domain.CreateRevisionRequest true "Create Revision Request"
+// @Success 201 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions [post]
+func (h *RevisionHandler) CreateDraft(c *fiber.Ctx) error {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	var request domain.CreateRevisionRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	revision, err := h.revisionService.CreateDraft(c.UserContext(), actor, &request)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseCreated(c, revision)
+	return nil
+}
+
+// @Summary Update Draft Revision
+// @Tags Content Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Param updateRevisionRequest body domain.UpdateRevisionRequest true "Update Revision Request"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId} [put]
+func (h *RevisionHandler) UpdateDraft(c *fiber.Ctx) error {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	revisionID, err := uuid.Parse(c.Params("revisionId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid revision ID")
+	}
+
+	var request domain.UpdateRevisionRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	revision, err := h.revisionService.UpdateDraft(c.UserContext(), actor, revisionID, &request)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, revision)
+	return nil
+}
+
+// @Summary Submit Revision For Review
+// @Tags Content Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId}/submit [post]
+func (h *RevisionHandler) Submit(c *fiber.Ctx) error {
+	return h.simpleTransition(c, h.revisionService.Submit)
+}
+
+// @Summary Withdraw Revision
+// @Description Move a revision under review or approved back to draft
+// @Tags Content Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId}/withdraw [post]
+func (h *RevisionHandler) Withdraw(c *fiber.Ctx) error {
+	return h.simpleTransition(c, h.revisionService.Withdraw)
+}
+
+// @Summary Roll Back To Revision
+// @Description Republish a previously published revision (admins only)
+// @Tags Content Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId}/rollback [post]
+func (h *RevisionHandler) Rollback(c *fiber.Ctx) error {
+	return h.simpleTransition(c, h.revisionService.Rollback)
+}
+
+// @Summary Approve Revision
+// @Tags Content Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Param reviewRevisionRequest body domain.ReviewRevisionRequest false "Review comment"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId}/approve [post]
+func (h *RevisionHandler) Approve(c *fiber.Ctx) error {
+	return h.reviewTransition(c, h.revisionService.Approve)
+}
+
+// @Summary Reject Revision
+// @Tags Content Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Param reviewRevisionRequest body domain.ReviewRevisionRequest false "Review comment"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId}/reject [post]
+func (h *RevisionHandler) Reject(c *fiber.Ctx) error {
+	return h.reviewTransition(c, h.revisionService.Reject)
+}
+
+// @Summary Publish Revision
+// @Description Publish an approved revision now, or schedule it with publish_at
+// @Tags Content Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param revisionId path string true "Revision ID"
+// @Param publishRevisionRequest body domain.PublishRevisionRequest false "Publish Revision Request"
+// @Success 200 {object} domain.ContentRevision
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/revisions/{revisionId}/publish [post]
+func (h *RevisionHandler) Publish(c *fiber.Ctx) error {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	revisionID, err := uuid.Parse(c.Params("revisionId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid revision ID")
+	}
+
+	var request domain.PublishRevisionRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	revision, err := h.revisionService.Publish(c.UserContext(), actor, revisionID, &request)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, revision)
+	return nil
+}
+
+func (h *RevisionHandler) simpleTransition(c *fiber.Ctx, action func(context.Context, domain.Actor, uuid.UUID) (*domain.ContentRevision, error)) error {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	revisionID, err := uuid.Parse(c.Params("revisionId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid revision ID")
+	}
+
+	revision, err := action(c.UserContext(), actor, revisionID)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, revision)
+	return nil
+}
+
+func (h *RevisionHandler) reviewTransition(c *fiber.Ctx, action func(context.Context, domain.Actor, uuid.UUID, *domain.ReviewRevisionRequest) (*domain.ContentRevision, error)) error {
+	actor, ok := actorFromContext(c)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	revisionID, err := uuid.Parse(c.Params("revisionId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid revision ID")
+	}
+
+	var request domain.ReviewRevisionRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	revision, err := action(c.UserContext(), actor, revisionID, &request)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, revision)
+	return nil
+}
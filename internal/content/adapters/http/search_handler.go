@@ -0,0 +1,67 @@
+package http
+
+import (
+	"s29-be/internal/content/application"
+	"s29-be/internal/content/domain"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler struct {
+	searchService *application.SearchService
+}
+
+func NewSearchHandler(searchService *application.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// @Summary Search
+// @Description Search published courses, lessons and vocabulary. Diacritics are optional, matches are wrapped in <mark> and suggestions are returned when few results match
+// @Tags Content
+// @Produce json
+// @Param q query string true "Search text; supports \"phrases\", or and -exclusions"
+// @Param course_id query string false "Only search this course"
+// @Param level query string false "beginner, intermediate or advanced"
+// @Param type query string false "course, lesson or vocabulary"
+// @Param limit query int false "Page size (default 20, max 50)"
+// @Param offset query int false "Results to skip"
+// @Success 200 {object} domain.SearchResponse
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	var query domain.SearchQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		return err
+	}
+
+	response, err := h.searchService.Search(c.UserContext(), &query)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, response)
+	return nil
+}
+
+// @Summary Reindex Search
+// @Description Rebuild the search documents of one course, or of every course
+// @Tags Content Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param course query string false "Course slug"
+// @Success 200 {object} map[string]int
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/search/reindex [post]
+func (h *SearchHandler) Reindex(c *fiber.Ctx) error {
+	count, err := h.searchService.Reindex(c.UserContext(), c.Query("course"))
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, fiber.Map{"courses": count})
+	return nil
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"s29-be/internal/content/application"
+	"s29-be/internal/content/domain"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TranslationHandler struct {
+	translationService *application.TranslationService
+}
+
+func NewTranslationHandler(translationService *application.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+	}
+}
+
+// @Summary List Translations
+// @Description List every translation of a content entity
+// @Tags Content Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param entity_type query string true "course, unit, lesson, exercise or vocabulary"
+// @Param entity_id query string true "Entity ID"
+// @Success 200 {array} domain.ContentTranslation
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/translations [get]
+func (h *TranslationHandler) ListTranslations(c *fiber.Ctx) error {
+	var query domain.ListTranslationsQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		return err
+	}
+
+	translations, err := h.translationService.ListTranslations(c.UserContext(), &query)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, translations)
+	return nil
+}
+
+// @Summary Save Translations
+// @Description Set translated fields of a content entity in one language; empty values remove a translation
+// @Tags Content Admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param request body domain.SaveTranslationsRequest true "Translated fields"
+// @Success 200 {array} domain.ContentTranslation
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/content/translations [put]
+func (h *TranslationHandler) SaveTranslations(c *fiber.Ctx) error {
+	var request domain.SaveTranslationsRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	translations, err := h.translationService.SaveTranslations(c.UserContext(), &request)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, translations)
+	return nil
+}
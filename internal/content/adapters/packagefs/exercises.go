@@ -0,0 +1,236 @@
+package packagefs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"s29-be/internal/content/domain"
+	"strings"
+)
+
+const choiceSeparator = "|"
+
+var vocabularyColumns = []string{"word", "translation", "part_of_speech", "example", "audio"}
+
+// parseMarkdownExercises reads exercises written as
+//
+//	## greet-hello
+//	type: multiple_choice
+//	prompt: Xin chào
+//	answer: Hello
+//	audio: media/xin-chao.mp3
+//	- Hello
+//	- Goodbye
+//
+//	Anything after the fields and choices is the explanation.
+//
+// Every "## " heading starts an exercise keyed by the heading text.
+func parseMarkdownExercises(data []byte) ([]domain.PackageExercise, error) {
+	var exercises []domain.PackageExercise
+	var current *domain.PackageExercise
+	var explanation []string
+	inExplanation := false
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Explanation = strings.TrimSpace(strings.Join(explanation, "\n"))
+		exercises = append(exercises, *current)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "## ") {
+			flush()
+			current = &domain.PackageExercise{Key: strings.TrimSpace(trimmed[3:])}
+			explanation = nil
+			inExplanation = false
+			continue
+		}
+		if current == nil {
+			// Lesson title and notes before the first exercise are ignored
+			continue
+		}
+
+		if !inExplanation {
+			if trimmed == "" {
+				continue
+			}
+			if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+				current.Choices = append(current.Choices, strings.TrimSpace(trimmed[2:]))
+				continue
+			}
+			if field, value, ok := strings.Cut(trimmed, ":"); ok && setExerciseField(current, strings.ToLower(field), strings.TrimSpace(value)) {
+				continue
+			}
+			inExplanation = true
+		}
+		explanation = append(explanation, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return exercises, nil
+}
+
+func setExerciseField(exercise *domain.PackageExercise, field, value string) bool {
+	switch field {
+	case "type":
+		exercise.Type = value
+	case "prompt":
+		exercise.Prompt = value
+	case "answer":
+		exercise.Answer = value
+	case "audio":
+		exercise.Audio = value
+	case "image":
+		exercise.Image = value
+	default:
+		return false
+	}
+	return true
+}
+
+func formatMarkdownExercises(lesson domain.PackageLesson) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", lesson.Title)
+
+	for _, exercise := range lesson.Exercises {
+		fmt.Fprintf(&buf, "\n## %s\n", exercise.Key)
+		fmt.Fprintf(&buf, "type: %s\n", exercise.Type)
+		fmt.Fprintf(&buf, "prompt: %s\n", exercise.Prompt)
+		fmt.Fprintf(&buf, "answer: %s\n", exercise.Answer)
+		if exercise.Audio != "" {
+			fmt.Fprintf(&buf, "audio: %s\n", exercise.Audio)
+		}
+		if exercise.Image != "" {
+			fmt.Fprintf(&buf, "image: %s\n", exercise.Image)
+		}
+		for _, choice := range exercise.Choices {
+			fmt.Fprintf(&buf, "- %s\n", choice)
+		}
+		if exercise.Explanation != "" {
+			fmt.Fprintf(&buf, "\n%s\n", exercise.Explanation)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func parseCSVExercises(data []byte) ([]domain.PackageExercise, error) {
+	rows, err := readCSV(data, []string{"key", "type", "prompt", "answer"})
+	if err != nil {
+		return nil, err
+	}
+
+	exercises := make([]domain.PackageExercise, 0, len(rows))
+	for _, row := range rows {
+		exercise := domain.PackageExercise{
+			Key:         row["key"],
+			Type:        row["type"],
+			Prompt:      row["prompt"],
+			Answer:      row["answer"],
+			Explanation: row["explanation"],
+			Audio:       row["audio"],
+			Image:       row["image"],
+		}
+		if row["choices"] != "" {
+			for _, choice := range strings.Split(row["choices"], choiceSeparator) {
+				exercise.Choices = append(exercise.Choices, strings.TrimSpace(choice))
+			}
+		}
+		exercises = append(exercises, exercise)
+	}
+	return exercises, nil
+}
+
+func parseCSVVocabulary(data []byte) ([]domain.PackageVocabulary, error) {
+	rows, err := readCSV(data, []string{"word", "translation"})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.PackageVocabulary, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, domain.PackageVocabulary{
+			Word:         row["word"],
+			Translation:  row["translation"],
+			PartOfSpeech: row["part_of_speech"],
+			Example:      row["example"],
+			Audio:        row["audio"],
+		})
+	}
+	return entries, nil
+}
+
+func formatCSVVocabulary(entries []domain.PackageVocabulary) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(vocabularyColumns); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if err := w.Write([]string{entry.Word, entry.Translation, entry.PartOfSpeech, entry.Example, entry.Audio}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// readCSV returns the rows keyed by the lower-cased header names and fails
+// when one of the required columns is missing.
+func readCSV(data []byte, required []string) ([]map[string]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+	for _, column := range required {
+		found := false
+		for _, name := range header {
+			if name == column {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("missing required column %q", column)
+		}
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
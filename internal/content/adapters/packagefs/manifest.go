@@ -0,0 +1,105 @@
+// Package packagefs reads and writes course packages: a course.yaml (or
+// course.json) manifest next to Markdown/CSV exercise files and a vocabulary
+// CSV, as maintained by the content team in git.
+package packagefs
+
+import (
+	"encoding/json"
+	"path"
+	"s29-be/internal/content/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+var manifestNames = []string{"course.yaml", "course.yml", "course.json"}
+
+const defaultXPReward = 10
+
+type manifest struct {
+	FormatVersion int            `yaml:"format_version" json:"format_version"`
+	Course        manifestCourse `yaml:"course" json:"course"`
+	Vocabulary    string         `yaml:"vocabulary,omitempty" json:"vocabulary,omitempty"`
+	Media         []string       `yaml:"media,omitempty" json:"media,omitempty"`
+	Units         []manifestUnit `yaml:"units" json:"units"`
+}
+
+type manifestCourse struct {
+	Slug         string `yaml:"slug" json:"slug"`
+	Title        string `yaml:"title" json:"title"`
+	Description  string `yaml:"description,omitempty" json:"description,omitempty"`
+	LanguageCode string `yaml:"language_code,omitempty" json:"language_code,omitempty"`
+	Level        string `yaml:"level,omitempty" json:"level,omitempty"`
+	Position     int    `yaml:"position,omitempty" json:"position,omitempty"`
+	Published    bool   `yaml:"published" json:"published"`
+}
+
+type manifestUnit struct {
+	Slug        string           `yaml:"slug" json:"slug"`
+	Title       string           `yaml:"title" json:"title"`
+	Description string           `yaml:"description,omitempty" json:"description,omitempty"`
+	Lessons     []manifestLesson `yaml:"lessons" json:"lessons"`
+}
+
+type manifestLesson struct {
+	Slug        string `yaml:"slug" json:"slug"`
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	XPReward    *int   `yaml:"xp_reward,omitempty" json:"xp_reward,omitempty"`
+	// Exercises is the path of a .md or .csv file relative to the manifest
+	Exercises string `yaml:"exercises" json:"exercises"`
+}
+
+func decodeManifest(name string, data []byte) (*manifest, error) {
+	var m manifest
+	var err error
+	if path.Ext(name) == ".json" {
+		err = json.Unmarshal(data, &m)
+	} else {
+		err = yaml.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func newManifest(pkg *domain.CoursePackage) *manifest {
+	m := &manifest{
+		FormatVersion: domain.PackageFormatVersion,
+		Course: manifestCourse{
+			Slug:         pkg.Course.Slug,
+			Title:        pkg.Course.Title,
+			Description:  pkg.Course.Description,
+			LanguageCode: pkg.Course.LanguageCode,
+			Level:        pkg.Course.Level,
+			Position:     pkg.Course.Position,
+			Published:    pkg.Course.Published,
+		},
+		Media: pkg.Media,
+		Units: make([]manifestUnit, 0, len(pkg.Units)),
+	}
+	if len(pkg.Vocabulary) > 0 {
+		m.Vocabulary = vocabularyFile
+	}
+
+	for _, unit := range pkg.Units {
+		mu := manifestUnit{
+			Slug:        unit.Slug,
+			Title:       unit.Title,
+			Description: unit.Description,
+			Lessons:     make([]manifestLesson, 0, len(unit.Lessons)),
+		}
+		for _, lesson := range unit.Lessons {
+			xpReward := lesson.XPReward
+			mu.Lessons = append(mu.Lessons, manifestLesson{
+				Slug:        lesson.Slug,
+				Title:       lesson.Title,
+				Description: lesson.Description,
+				XPReward:    &xpReward,
+				Exercises:   lessonFile(unit.Slug, lesson.Slug),
+			})
+		}
+		m.Units = append(m.Units, mu)
+	}
+	return m
+}
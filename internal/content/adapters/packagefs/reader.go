@@ -0,0 +1,183 @@
+package packagefs
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"s29-be/internal/content/domain"
+	"strings"
+)
+
+// ErrInvalidPackage is returned together with the validation issues when a
+// package cannot be imported.
+var ErrInvalidPackage = errors.New("invalid course package")
+
+// ReadPath reads a package from a directory or a .zip archive on disk.
+func ReadPath(name string) (*domain.CoursePackage, []domain.ValidationIssue, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return Read(os.DirFS(name))
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ReadZip(data)
+}
+
+// ReadZip reads a package from an in-memory .zip archive, as uploaded to the
+// admin import endpoint.
+func ReadZip(data []byte) (*domain.CoursePackage, []domain.ValidationIssue, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+	return Read(archive)
+}
+
+// Read parses the manifest and every file it references, then validates the
+// result. File and schema problems are reported as issues; the returned
+// error is only set for I/O failures or when issues were found.
+func Read(fsys fs.FS) (*domain.CoursePackage, []domain.ValidationIssue, error) {
+	root, manifestName, err := findManifest(fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := fs.ReadFile(root, manifestName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m, err := decodeManifest(manifestName, data)
+	if err != nil {
+		issues := []domain.ValidationIssue{{Path: manifestName, Message: err.Error()}}
+		return nil, issues, ErrInvalidPackage
+	}
+
+	pkg := &domain.CoursePackage{
+		FormatVersion: m.FormatVersion,
+		Course: domain.PackageCourse{
+			Slug:         m.Course.Slug,
+			Title:        m.Course.Title,
+			Description:  m.Course.Description,
+			LanguageCode: m.Course.LanguageCode,
+			Level:        m.Course.Level,
+			Position:     m.Course.Position,
+			Published:    m.Course.Published,
+		},
+		Media: m.Media,
+		Units: make([]domain.PackageUnit, 0, len(m.Units)),
+	}
+	if pkg.Course.LanguageCode == "" {
+		pkg.Course.LanguageCode = "vi"
+	}
+	if pkg.Course.Level == "" {
+		pkg.Course.Level = "beginner"
+	}
+
+	var issues []domain.ValidationIssue
+	for i, mu := range m.Units {
+		unit := domain.PackageUnit{
+			Slug:        mu.Slug,
+			Title:       mu.Title,
+			Description: mu.Description,
+			Lessons:     make([]domain.PackageLesson, 0, len(mu.Lessons)),
+		}
+
+		for j, ml := range mu.Lessons {
+			lesson := domain.PackageLesson{
+				Slug:        ml.Slug,
+				Title:       ml.Title,
+				Description: ml.Description,
+				XPReward:    defaultXPReward,
+			}
+			if ml.XPReward != nil {
+				lesson.XPReward = *ml.XPReward
+			}
+
+			exercises, err := readExercises(root, ml.Exercises)
+			if err != nil {
+				issues = append(issues, domain.ValidationIssue{
+					Path:    fmt.Sprintf("units[%d].lessons[%d].exercises (%s)", i, j, ml.Exercises),
+					Message: err.Error(),
+				})
+			}
+			lesson.Exercises = exercises
+			unit.Lessons = append(unit.Lessons, lesson)
+		}
+		pkg.Units = append(pkg.Units, unit)
+	}
+
+	if m.Vocabulary != "" {
+		data, err := fs.ReadFile(root, m.Vocabulary)
+		if err == nil {
+			pkg.Vocabulary, err = parseCSVVocabulary(data)
+		}
+		if err != nil {
+			issues = append(issues, domain.ValidationIssue{Path: m.Vocabulary, Message: err.Error()})
+		}
+	}
+
+	issues = append(issues, pkg.Validate()...)
+	if len(issues) > 0 {
+		return pkg, issues, ErrInvalidPackage
+	}
+	return pkg, nil, nil
+}
+
+func readExercises(fsys fs.FS, name string) ([]domain.PackageExercise, error) {
+	if name == "" {
+		return nil, fmt.Errorf("exercises file is required")
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		return parseMarkdownExercises(data)
+	case ".csv":
+		return parseCSVExercises(data)
+	default:
+		return nil, fmt.Errorf("unsupported exercises file type %q", path.Ext(name))
+	}
+}
+
+// findManifest looks for the manifest at the root, or inside a single
+// top-level directory as produced when zipping a package folder.
+func findManifest(fsys fs.FS) (fs.FS, string, error) {
+	for _, name := range manifestNames {
+		if _, err := fs.Stat(fsys, name); err == nil {
+			return fsys, name, nil
+		}
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		sub, err := fs.Sub(fsys, entries[0].Name())
+		if err != nil {
+			return nil, "", err
+		}
+		for _, name := range manifestNames {
+			if _, err := fs.Stat(sub, name); err == nil {
+				return sub, name, nil
+			}
+		}
+	}
+
+	return nil, "", fmt.Errorf("%w: no %s found", ErrInvalidPackage, strings.Join(manifestNames, ", "))
+}
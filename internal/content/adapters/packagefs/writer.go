@@ -0,0 +1,91 @@
+package packagefs
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"s29-be/internal/content/domain"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	manifestFile   = "course.yaml"
+	vocabularyFile = "vocabulary.csv"
+)
+
+func lessonFile(unitSlug, lessonSlug string) string {
+	return path.Join("lessons", unitSlug, lessonSlug+".md")
+}
+
+// Files renders a package into its file layout, keyed by slash-separated path.
+func Files(pkg *domain.CoursePackage) (map[string][]byte, error) {
+	manifestData, err := yaml.Marshal(newManifest(pkg))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{
+		manifestFile: manifestData,
+	}
+	for _, unit := range pkg.Units {
+		for _, lesson := range unit.Lessons {
+			files[lessonFile(unit.Slug, lesson.Slug)] = formatMarkdownExercises(lesson)
+		}
+	}
+	if len(pkg.Vocabulary) > 0 {
+		files[vocabularyFile], err = formatCSVVocabulary(pkg.Vocabulary)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// WriteDir writes the package below dir, creating directories as needed.
+func WriteDir(dir string, pkg *domain.CoursePackage) error {
+	files, err := Files(pkg)
+	if err != nil {
+		return err
+	}
+
+	for name, data := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteZip writes the package as a zip archive with a stable file order.
+func WriteZip(w io.Writer, pkg *domain.CoursePackage) error {
+	files, err := Files(pkg)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
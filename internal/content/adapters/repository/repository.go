@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"s29-be/internal/content/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContentRepository struct {
+	db *gorm.DB
+}
+
+func NewContentRepository(db *gorm.DB) *ContentRepository {
+	return &ContentRepository{
+		db: db,
+	}
+}
+
+func (r *ContentRepository) ListPublishedCourses(level string) ([]domain.Course, error) {
+	var courses []domain.Course
+	query := r.db.Where("is_published = ?", true)
+	if level != "" {
+		query = query.Where("level = ?", level)
+	}
+
+	err := query.
+		Preload("Units", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Units.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Order("position ASC").
+		Find(&courses).Error
+	if err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+// FindCourseTree loads a course with its units and lessons ordered by position.
+func (r *ContentRepository) FindCourseTree(courseID uuid.UUID) (*domain.Course, error) {
+	var course domain.Course
+	err := r.db.
+		Preload("Units", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Units.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ?", courseID).
+		First(&course).Error
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *ContentRepository) FindLessonByID(lessonID uuid.UUID) (*domain.Lesson, error) {
+	var lesson domain.Lesson
+	err := r.db.Where("id = ?", lessonID).First(&lesson).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (r *ContentRepository) FindUnitByID(unitID uuid.UUID) (*domain.Unit, error) {
+	var unit domain.Unit
+	err := r.db.Where("id = ?", unitID).First(&unit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// WithContext returns a repository whose queries run under ctx, so they are
+// cancelled with the request and logged with its attributes.
+func (r *ContentRepository) WithContext(ctx context.Context) *ContentRepository {
+	return &ContentRepository{db: r.db.WithContext(ctx)}
+}
+
+// Transaction runs fn against a repository bound to a single database transaction.
+func (r *ContentRepository) Transaction(fn func(txRepo *ContentRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&ContentRepository{db: tx})
+	})
+}
+
+// FindCourseBySlug loads a course with units, lessons and exercises ordered by position.
+func (r *ContentRepository) FindCourseBySlug(slug string) (*domain.Course, error) {
+	var course domain.Course
+	err := r.db.
+		Preload("Units", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Units.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Units.Lessons.Exercises", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("slug = ?", slug).
+		First(&course).Error
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *ContentRepository) ListVocabulary(courseID uuid.UUID) ([]domain.Vocabulary, error) {
+	var vocabulary []domain.Vocabulary
+	err := r.db.Where("course_id = ?", courseID).Order("word ASC").Find(&vocabulary).Error
+	if err != nil {
+		return nil, err
+	}
+	return vocabulary, nil
+}
+
+func (r *ContentRepository) Create(entity interface{}) error {
+	return r.db.Create(entity).Error
+}
+
+// Update saves all fields of the entity, leaving associations untouched.
+func (r *ContentRepository) Update(entity interface{}) error {
+	return r.db.Omit(clause.Associations).Save(entity).Error
+}
+
+func (r *ContentRepository) Delete(entity interface{}) error {
+	return r.db.Delete(entity).Error
+}
+
+// CountUnitLearners returns how many users have progress on a unit.
+func (r *ContentRepository) CountUnitLearners(unitID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Table("user_unit_progress").Where("unit_id = ?", unitID).Count(&count).Error
+	return count, err
+}
+
+// CountLessonLearners returns how many users have progress on a lesson.
+func (r *ContentRepository) CountLessonLearners(lessonID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Table("user_lesson_progress").Where("lesson_id = ?", lessonID).Count(&count).Error
+	return count, err
+}
+
+// RecountLessonsCompleted recomputes every learner's completed lesson count
+// for a course from their lesson progress, after lessons were deleted.
+func (r *ContentRepository) RecountLessonsCompleted(courseID uuid.UUID) error {
+	return r.db.Exec(`
+		UPDATE user_course_progress p
+		SET lessons_completed = (
+			SELECT COUNT(*)
+			FROM user_lesson_progress l
+			JOIN units u ON u.id = l.unit_id
+			WHERE l.user_id = p.user_id AND u.course_id = p.course_id AND l.status = 'completed'
+		), updated_at = NOW()
+		WHERE p.course_id = ?`, courseID).Error
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"s29-be/internal/content/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *ContentRepository) FindRevisionByID(revisionID uuid.UUID) (*domain.ContentRevision, error) {
+	var revision domain.ContentRevision
+	err := r.db.Where("id = ?", revisionID).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// FindRevisionForUpdate locks the revision row until the surrounding transaction ends.
+func (r *ContentRepository) FindRevisionForUpdate(revisionID uuid.UUID) (*domain.ContentRevision, error) {
+	var revision domain.ContentRevision
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", revisionID).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *ContentRepository) ListRevisions(entityType string, entityID *uuid.UUID, state string) ([]domain.ContentRevision, error) {
+	var revisions []domain.ContentRevision
+	db := r.db.Order("created_at DESC")
+	if entityType != "" {
+		db = db.Where("entity_type = ?", entityType)
+	}
+	if entityID != nil {
+		db = db.Where("entity_id = ?", *entityID)
+	}
+	if state != "" {
+		db = db.Where("state = ?", state)
+	}
+
+	err := db.Limit(100).Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *ContentRepository) NextRevisionNumber(entityType string, entityID uuid.UUID) (int, error) {
+	var current int
+	err := r.db.Model(&domain.ContentRevision{}).
+		Select("COALESCE(MAX(revision_number), 0)").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Scan(&current).Error
+	if err != nil {
+		return 0, err
+	}
+	return current + 1, nil
+}
+
+func (r *ContentRepository) FindPublishedRevision(entityType string, entityID uuid.UUID) (*domain.ContentRevision, error) {
+	var revision domain.ContentRevision
+	err := r.db.Where("entity_type = ? AND entity_id = ? AND state = ?", entityType, entityID, domain.RevisionPublished).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// SupersedePublishedRevision retires the live revision of an entity, if any.
+func (r *ContentRepository) SupersedePublishedRevision(entityType string, entityID uuid.UUID) error {
+	return r.db.Model(&domain.ContentRevision{}).
+		Where("entity_type = ? AND entity_id = ? AND state = ?", entityType, entityID, domain.RevisionPublished).
+		Update("state", domain.RevisionSuperseded).Error
+}
+
+// ListDueRevisionIDs returns approved revisions whose scheduled publish
+// time has passed, oldest schedule first.
+func (r *ContentRepository) ListDueRevisionIDs(now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&domain.ContentRevision{}).
+		Where("state = ? AND scheduled_publish_at IS NOT NULL AND scheduled_publish_at <= ?", domain.RevisionApproved, now).
+		Order("scheduled_publish_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindDueRevisionForUpdate locks a revision that is still due, skipping it
+// if another replica is already publishing it. Either case, like a revision
+// that has been published since, returns gorm.ErrRecordNotFound.
+func (r *ContentRepository) FindDueRevisionForUpdate(revisionID uuid.UUID, now time.Time) (*domain.ContentRevision, error) {
+	var revision domain.ContentRevision
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ? AND state = ? AND scheduled_publish_at IS NOT NULL AND scheduled_publish_at <= ?", revisionID, domain.RevisionApproved, now).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// UnscheduleRevision clears the publish schedule of an approved revision,
+// leaving it approved.
+func (r *ContentRepository) UnscheduleRevision(revisionID uuid.UUID) error {
+	return r.db.Model(&domain.ContentRevision{}).
+		Where("id = ? AND state = ?", revisionID, domain.RevisionApproved).
+		Update("scheduled_publish_at", nil).Error
+}
+
+func (r *ContentRepository) FindCourseByID(courseID uuid.UUID) (*domain.Course, error) {
+	var course domain.Course
+	err := r.db.Where("id = ?", courseID).First(&course).Error
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *ContentRepository) FindLessonWithExercises(lessonID uuid.UUID) (*domain.Lesson, error) {
+	var lesson domain.Lesson
+	err := r.db.
+		Preload("Exercises", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ?", lessonID).
+		First(&lesson).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
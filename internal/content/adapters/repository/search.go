@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"s29-be/internal/content/domain"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchConfig is the text search configuration created by the search
+// migration; it folds diacritics so Vietnamese matches with or without them.
+const searchConfig = "vi_unaccent"
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=\" … \""
+
+// FindCourseForIndex locks a course and loads its full tree so concurrent
+// reindexes of the same course run one after another.
+func (r *ContentRepository) FindCourseForIndex(courseID uuid.UUID) (*domain.Course, error) {
+	var course domain.Course
+	err := r.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Units", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Units.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Units.Lessons.Exercises", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ?", courseID).
+		First(&course).Error
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *ContentRepository) ListCourseIDs() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.Model(&domain.Course{}).Order("position ASC").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindCourseIDForEntity resolves the course a content entity belongs to.
+func (r *ContentRepository) FindCourseIDForEntity(entityType string, entityID uuid.UUID) (uuid.UUID, error) {
+	var query string
+	switch entityType {
+	case domain.EntityCourse:
+		return entityID, nil
+	case domain.EntityUnit:
+		query = "SELECT course_id FROM units WHERE id = ?"
+	case domain.EntityLesson:
+		query = "SELECT u.course_id FROM lessons l JOIN units u ON u.id = l.unit_id WHERE l.id = ?"
+	case domain.EntityExercise:
+		query = "SELECT u.course_id FROM exercises e JOIN lessons l ON l.id = e.lesson_id JOIN units u ON u.id = l.unit_id WHERE e.id = ?"
+	case domain.EntityVocabulary:
+		query = "SELECT course_id FROM vocabulary WHERE id = ?"
+	default:
+		return uuid.Nil, gorm.ErrRecordNotFound
+	}
+
+	var courseIDs []uuid.UUID
+	if err := r.db.Raw(query, entityID).Scan(&courseIDs).Error; err != nil {
+		return uuid.Nil, err
+	}
+	if len(courseIDs) == 0 {
+		return uuid.Nil, gorm.ErrRecordNotFound
+	}
+	return courseIDs[0], nil
+}
+
+// ListAllTranslations loads the translations of the given entities in every language.
+func (r *ContentRepository) ListAllTranslations(entityIDs []uuid.UUID) ([]domain.ContentTranslation, error) {
+	var translations []domain.ContentTranslation
+	if len(entityIDs) == 0 {
+		return translations, nil
+	}
+
+	if err := r.db.Where("entity_id IN ?", entityIDs).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *ContentRepository) DeleteSearchDocuments(courseID uuid.UUID) error {
+	return r.db.Where("course_id = ?", courseID).Delete(&domain.SearchDocument{}).Error
+}
+
+func (r *ContentRepository) InsertSearchEntry(entry *domain.SearchEntry) error {
+	doc := entry.Document
+	return r.db.Exec(`
+		INSERT INTO search_documents (id, entity_type, entity_id, course_id, level, title, body, title_normalized, document, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, lower(immutable_unaccent(?)),
+			setweight(to_tsvector(?::regconfig, ?), 'A') ||
+			setweight(to_tsvector(?::regconfig, ?), 'B') ||
+			setweight(to_tsvector(?::regconfig, ?), 'C'),
+			?, ?)`,
+		doc.ID, doc.EntityType, doc.EntityID, doc.CourseID, doc.Level, doc.Title, doc.Body, doc.Title,
+		searchConfig, entry.Primary,
+		searchConfig, entry.Secondary,
+		searchConfig, entry.Detail,
+		doc.CreatedAt, doc.UpdatedAt,
+	).Error
+}
+
+// Search ranks published documents matching filter.Text, a web-search
+// style query ("quoted phrases", or, -excluded).
+func (r *ContentRepository) Search(filter *domain.SearchFilter) ([]domain.SearchResult, int64, error) {
+	conditions, args := searchConditions(filter)
+
+	query := `
+		WITH q AS (SELECT websearch_to_tsquery(?::regconfig, ?) AS query)
+		SELECT d.entity_type, d.entity_id, d.course_id, c.slug AS course_slug, c.title AS course_title, d.level, d.title,
+			ts_headline(?::regconfig, d.title, q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight,
+			ts_headline(?::regconfig, d.body, q.query, ?) AS snippet,
+			ts_rank_cd(d.document, q.query) AS rank,
+			count(*) OVER () AS total
+		FROM search_documents d
+		JOIN courses c ON c.id = d.course_id
+		CROSS JOIN q
+		WHERE d.document @@ q.query` + conditions + `
+		ORDER BY rank DESC, d.title ASC
+		LIMIT ? OFFSET ?`
+
+	queryArgs := []interface{}{searchConfig, filter.Text, searchConfig, searchConfig, headlineOptions}
+	queryArgs = append(queryArgs, args...)
+	queryArgs = append(queryArgs, filter.Limit, filter.Offset)
+
+	var rows []struct {
+		domain.SearchResult
+		Total int64
+	}
+	if err := r.db.Raw(query, queryArgs...).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]domain.SearchResult, 0, len(rows))
+	var total int64
+	for _, row := range rows {
+		results = append(results, row.SearchResult)
+		total = row.Total
+	}
+	return results, total, nil
+}
+
+// Suggest returns document titles similar to filter.Text, tolerating typos
+// and missing diacritics through trigram word similarity.
+func (r *ContentRepository) Suggest(filter *domain.SearchFilter, limit int) ([]string, error) {
+	conditions, args := searchConditions(filter)
+
+	query := `
+		SELECT d.title
+		FROM search_documents d
+		WHERE lower(immutable_unaccent(?)) <% d.title_normalized` + conditions + `
+		GROUP BY d.title
+		ORDER BY max(word_similarity(lower(immutable_unaccent(?)), d.title_normalized)) DESC, d.title ASC
+		LIMIT ?`
+
+	queryArgs := []interface{}{filter.Text}
+	queryArgs = append(queryArgs, args...)
+	queryArgs = append(queryArgs, filter.Text, limit)
+
+	var titles []string
+	if err := r.db.Raw(query, queryArgs...).Scan(&titles).Error; err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+func searchConditions(filter *domain.SearchFilter) (string, []interface{}) {
+	var conditions strings.Builder
+	var args []interface{}
+	if filter.CourseID != nil {
+		conditions.WriteString(" AND d.course_id = ?")
+		args = append(args, *filter.CourseID)
+	}
+	if filter.Level != "" {
+		conditions.WriteString(" AND d.level = ?")
+		args = append(args, filter.Level)
+	}
+	if filter.Type != "" {
+		conditions.WriteString(" AND d.entity_type = ?")
+		args = append(args, filter.Type)
+	}
+	return conditions.String(), args
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"s29-be/internal/content/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// ListTranslations loads the translations of the given entities in any of languages.
+func (r *ContentRepository) ListTranslations(entityIDs []uuid.UUID, languages []string) ([]domain.ContentTranslation, error) {
+	var translations []domain.ContentTranslation
+	if len(entityIDs) == 0 || len(languages) == 0 {
+		return translations, nil
+	}
+
+	err := r.db.
+		Where("entity_id IN ? AND language_code IN ?", entityIDs, languages).
+		Find(&translations).Error
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *ContentRepository) ListEntityTranslations(entityType string, entityID uuid.UUID) ([]domain.ContentTranslation, error) {
+	var translations []domain.ContentTranslation
+	err := r.db.
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("language_code ASC, field ASC").
+		Find(&translations).Error
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (r *ContentRepository) UpsertTranslation(translation *domain.ContentTranslation) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "language_code"}, {Name: "field"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *ContentRepository) DeleteTranslation(entityType string, entityID uuid.UUID, languageCode, field string) error {
+	return r.db.
+		Where("entity_type = ? AND entity_id = ? AND language_code = ? AND field = ?", entityType, entityID, languageCode, field).
+		Delete(&domain.ContentTranslation{}).Error
+}
+
+// CountEntity reports whether a translatable entity exists.
+func (r *ContentRepository) CountEntity(entityType string, entityID uuid.UUID) (int64, error) {
+	var model interface{}
+	switch entityType {
+	case domain.EntityCourse:
+		model = &domain.Course{}
+	case domain.EntityUnit:
+		model = &domain.Unit{}
+	case domain.EntityLesson:
+		model = &domain.Lesson{}
+	case domain.EntityExercise:
+		model = &domain.Exercise{}
+	case domain.EntityVocabulary:
+		model = &domain.Vocabulary{}
+	default:
+		return 0, nil
+	}
+
+	var count int64
+	err := r.db.Model(model).Where("id = ?", entityID).Count(&count).Error
+	return count, err
+}
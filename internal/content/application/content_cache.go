@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/pkg/cache"
+
+	"github.com/google/uuid"
+)
+
+// Published content is cached per language. Everything cached for a course
+// carries its tag, and course lists carry courseListTag, so publishing,
+// importing or translating a course drops exactly what it changed.
+const courseListTag = "courses"
+
+func courseTag(courseID uuid.UUID) string {
+	return "course:" + courseID.String()
+}
+
+func courseCacheKey(courseID uuid.UUID, language string) string {
+	return fmt.Sprintf("content:course:%s:%s", courseID, language)
+}
+
+func courseListCacheKey(level, language string) string {
+	return fmt.Sprintf("content:courses:%s:%s", level, language)
+}
+
+// invalidateCourse drops cached content of the course once a change to it
+// has committed. Failures are only logged; the entries expire anyway.
+func invalidateCourse(ctx context.Context, contentCache *cache.Cache, courseID uuid.UUID) {
+	if err := contentCache.InvalidateTags(ctx, courseTag(courseID), courseListTag); err != nil {
+		slog.WarnContext(ctx, "Failed to invalidate cached course",
+			slog.String("course_id", courseID.String()), slog.Any("error", err))
+	}
+}
+
+// invalidateEntityCourse drops cached content of the course entity belongs to.
+func invalidateEntityCourse(ctx context.Context, repo *repository.ContentRepository, contentCache *cache.Cache, entityType string, entityID uuid.UUID) {
+	courseID, err := repo.WithContext(ctx).FindCourseIDForEntity(entityType, entityID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to find course to invalidate",
+			slog.String("entity_type", entityType), slog.String("entity_id", entityID.String()), slog.Any("error", err))
+		return
+	}
+	invalidateCourse(ctx, contentCache, courseID)
+}
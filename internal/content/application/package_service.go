@@ -0,0 +1,541 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/internal/content/domain"
+	"s29-be/pkg/cache"
+	appError "s29-be/pkg/error"
+	baseModel "s29-be/pkg/model"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PackageService imports and exports course packages. Imports are diffed
+// against the database first; with dryRun unset the same diff is applied
+// inside a single transaction, so a failing package never leaves a course
+// half updated.
+type PackageService struct {
+	contentRepo  *repository.ContentRepository
+	contentCache *cache.Cache
+}
+
+func NewPackageService(contentRepo *repository.ContentRepository, contentCache *cache.Cache) *PackageService {
+	return &PackageService{
+		contentRepo:  contentRepo,
+		contentCache: contentCache,
+	}
+}
+
+func (s *PackageService) Import(ctx context.Context, pkg *domain.CoursePackage, opts domain.ImportOptions) (*domain.ImportPlan, error) {
+	if issues := pkg.Validate(); len(issues) > 0 {
+		return nil, appError.NewBadRequestError(nil, "invalid course package").WithData(issues)
+	}
+
+	var plan *domain.ImportPlan
+	var courseID uuid.UUID
+	err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		var err error
+		plan, err = reconcile(repo, pkg, opts)
+		if err != nil {
+			return err
+		}
+		if opts.DryRun {
+			// Nothing was written, but roll back anyway to release the snapshot
+			return errDryRun
+		}
+
+		learnerDeletes := plan.LearnerDeletes()
+		if len(learnerDeletes) > 0 && !opts.Force {
+			return appError.NewConflictError(nil, "pruning would delete learner progress, import with force to delete it").WithData(learnerDeletes)
+		}
+
+		course, err := repo.FindCourseBySlug(pkg.Course.Slug)
+		if err != nil {
+			return err
+		}
+		courseID = course.ID
+		if len(learnerDeletes) > 0 {
+			if err := repo.RecountLessonsCompleted(course.ID); err != nil {
+				return err
+			}
+		}
+		return reindexCourse(repo, course.ID)
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		if appError.IsAppError(err) {
+			return nil, err
+		}
+		return nil, appError.NewInternalError(err, "failed to import course package")
+	}
+	if !opts.DryRun {
+		invalidateCourse(ctx, s.contentCache, courseID)
+	}
+
+	plan.DryRun = opts.DryRun
+	return plan, nil
+}
+
+func (s *PackageService) Export(ctx context.Context, courseSlug string) (*domain.CoursePackage, error) {
+	repo := s.contentRepo.WithContext(ctx)
+
+	course, err := repo.FindCourseBySlug(courseSlug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appError.NewNotFoundError(err, "course not found")
+		}
+		return nil, appError.NewInternalError(err, "failed to load course")
+	}
+
+	vocabulary, err := repo.ListVocabulary(course.ID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load vocabulary")
+	}
+
+	pkg := &domain.CoursePackage{
+		FormatVersion: domain.PackageFormatVersion,
+		Course: domain.PackageCourse{
+			Slug:         course.Slug,
+			Title:        course.Title,
+			Description:  course.Description,
+			LanguageCode: course.LanguageCode,
+			Level:        course.Level,
+			Position:     course.Position,
+			Published:    course.IsPublished,
+		},
+		Units:      make([]domain.PackageUnit, 0, len(course.Units)),
+		Vocabulary: make([]domain.PackageVocabulary, 0, len(vocabulary)),
+	}
+
+	media := make(map[string]bool)
+	addMedia := func(ref string) {
+		if ref != "" && !isRemoteMedia(ref) && !media[ref] {
+			media[ref] = true
+			pkg.Media = append(pkg.Media, ref)
+		}
+	}
+
+	for _, unit := range course.Units {
+		packageUnit := domain.PackageUnit{
+			Slug:        unit.Slug,
+			Title:       unit.Title,
+			Description: unit.Description,
+			Lessons:     make([]domain.PackageLesson, 0, len(unit.Lessons)),
+		}
+		for _, lesson := range unit.Lessons {
+			packageLesson := domain.PackageLesson{
+				Slug:        lesson.Slug,
+				Title:       lesson.Title,
+				Description: lesson.Description,
+				XPReward:    lesson.XPReward,
+				Exercises:   make([]domain.PackageExercise, 0, len(lesson.Exercises)),
+			}
+			for _, exercise := range lesson.Exercises {
+				packageLesson.Exercises = append(packageLesson.Exercises, domain.PackageExercise{
+					Key:         exercise.Key,
+					Type:        exercise.Type,
+					Prompt:      exercise.Prompt,
+					Answer:      exercise.Answer,
+					Choices:     exercise.Choices,
+					Explanation: exercise.Explanation,
+					Audio:       exercise.AudioURL,
+					Image:       exercise.ImageURL,
+				})
+				addMedia(exercise.AudioURL)
+				addMedia(exercise.ImageURL)
+			}
+			packageUnit.Lessons = append(packageUnit.Lessons, packageLesson)
+		}
+		pkg.Units = append(pkg.Units, packageUnit)
+	}
+
+	for _, entry := range vocabulary {
+		pkg.Vocabulary = append(pkg.Vocabulary, domain.PackageVocabulary{
+			Word:         entry.Word,
+			Translation:  entry.Translation,
+			PartOfSpeech: entry.PartOfSpeech,
+			Example:      entry.Example,
+			Audio:        entry.AudioURL,
+		})
+		addMedia(entry.AudioURL)
+	}
+
+	return pkg, nil
+}
+
+var errDryRun = errors.New("dry run")
+
+// updateLive saves an imported change to live content. Imports bypass the
+// revision workflow, so the entity's published revision no longer matches
+// what learners see; it is superseded and the next lesson session records a
+// fresh baseline revision.
+func updateLive(repo *repository.ContentRepository, entityType string, entityID uuid.UUID, entity interface{}) error {
+	if err := repo.Update(entity); err != nil {
+		return err
+	}
+	return repo.SupersedePublishedRevision(entityType, entityID)
+}
+
+// reconcile walks the package and the stored course side by side, recording
+// every difference in the plan and, unless opts.DryRun is set, writing it.
+func reconcile(repo *repository.ContentRepository, pkg *domain.CoursePackage, opts domain.ImportOptions) (*domain.ImportPlan, error) {
+	apply := !opts.DryRun
+	plan := &domain.ImportPlan{
+		CourseSlug: pkg.Course.Slug,
+		Changes:    []domain.PackageChange{},
+	}
+
+	course, err := repo.FindCourseBySlug(pkg.Course.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if course == nil {
+		base, err := baseModel.NewBaseModel()
+		if err != nil {
+			return nil, err
+		}
+		course = &domain.Course{BaseModel: *base}
+		applyCourseFields(course, pkg.Course)
+		plan.Add(domain.ChangeCreate, "course", pkg.Course.Slug)
+		if apply {
+			if err := repo.Create(course); err != nil {
+				return nil, err
+			}
+		}
+	} else if fields := applyCourseFields(course, pkg.Course); len(fields) > 0 {
+		plan.Add(domain.ChangeUpdate, "course", pkg.Course.Slug, fields...)
+		if apply {
+			if err := updateLive(repo, domain.EntityCourse, course.ID, course); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	existingUnits := make(map[string]domain.Unit, len(course.Units))
+	for _, unit := range course.Units {
+		existingUnits[unit.Slug] = unit
+	}
+
+	for position, packageUnit := range pkg.Units {
+		unit, exists := existingUnits[packageUnit.Slug]
+		delete(existingUnits, packageUnit.Slug)
+
+		if !exists {
+			base, err := baseModel.NewBaseModel()
+			if err != nil {
+				return nil, err
+			}
+			unit = domain.Unit{BaseModel: *base, CourseID: course.ID}
+			applyUnitFields(&unit, packageUnit, position)
+			plan.Add(domain.ChangeCreate, "unit", packageUnit.Slug)
+			if apply {
+				if err := repo.Create(&unit); err != nil {
+					return nil, err
+				}
+			}
+		} else if fields := applyUnitFields(&unit, packageUnit, position); len(fields) > 0 {
+			plan.Add(domain.ChangeUpdate, "unit", packageUnit.Slug, fields...)
+			if apply {
+				if err := updateLive(repo, domain.EntityUnit, unit.ID, &unit); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := reconcileLessons(repo, plan, unit, packageUnit, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, unit := range course.Units {
+		if _, removed := existingUnits[unit.Slug]; !removed {
+			continue
+		}
+		if err := remove(repo, plan, opts, "unit", unit.Slug, &unit, repo.CountUnitLearners, unit.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := reconcileVocabulary(repo, plan, course, pkg.Vocabulary, opts); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func reconcileLessons(repo *repository.ContentRepository, plan *domain.ImportPlan, unit domain.Unit, packageUnit domain.PackageUnit, opts domain.ImportOptions) error {
+	apply := !opts.DryRun
+	existing := make(map[string]domain.Lesson, len(unit.Lessons))
+	for _, lesson := range unit.Lessons {
+		existing[lesson.Slug] = lesson
+	}
+
+	for position, packageLesson := range packageUnit.Lessons {
+		key := packageUnit.Slug + "/" + packageLesson.Slug
+		lesson, exists := existing[packageLesson.Slug]
+		delete(existing, packageLesson.Slug)
+		changesBefore := len(plan.Changes)
+
+		if !exists {
+			base, err := baseModel.NewBaseModel()
+			if err != nil {
+				return err
+			}
+			lesson = domain.Lesson{BaseModel: *base, UnitID: unit.ID}
+			applyLessonFields(&lesson, packageLesson, position)
+			plan.Add(domain.ChangeCreate, "lesson", key)
+			if apply {
+				if err := repo.Create(&lesson); err != nil {
+					return err
+				}
+			}
+		} else if fields := applyLessonFields(&lesson, packageLesson, position); len(fields) > 0 {
+			plan.Add(domain.ChangeUpdate, "lesson", key, fields...)
+			if apply {
+				if err := repo.Update(&lesson); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := reconcileExercises(repo, plan, lesson, key, packageLesson.Exercises, opts); err != nil {
+			return err
+		}
+
+		if apply && exists && len(plan.Changes) > changesBefore {
+			if err := repo.SupersedePublishedRevision(domain.EntityLesson, lesson.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, lesson := range unit.Lessons {
+		if _, removed := existing[lesson.Slug]; !removed {
+			continue
+		}
+		key := packageUnit.Slug + "/" + lesson.Slug
+		if err := remove(repo, plan, opts, "lesson", key, &lesson, repo.CountLessonLearners, lesson.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reconcileExercises(repo *repository.ContentRepository, plan *domain.ImportPlan, lesson domain.Lesson, lessonKey string, exercises []domain.PackageExercise, opts domain.ImportOptions) error {
+	apply := !opts.DryRun
+	existing := make(map[string]domain.Exercise, len(lesson.Exercises))
+	for _, exercise := range lesson.Exercises {
+		existing[exercise.Key] = exercise
+	}
+
+	for position, packageExercise := range exercises {
+		key := lessonKey + "#" + packageExercise.Key
+		exercise, exists := existing[packageExercise.Key]
+		delete(existing, packageExercise.Key)
+
+		if !exists {
+			base, err := baseModel.NewBaseModel()
+			if err != nil {
+				return err
+			}
+			exercise = domain.Exercise{BaseModel: *base, LessonID: lesson.ID}
+			applyExerciseFields(&exercise, packageExercise, position)
+			plan.Add(domain.ChangeCreate, "exercise", key)
+			if apply {
+				if err := repo.Create(&exercise); err != nil {
+					return err
+				}
+			}
+		} else if fields := applyExerciseFields(&exercise, packageExercise, position); len(fields) > 0 {
+			plan.Add(domain.ChangeUpdate, "exercise", key, fields...)
+			if apply {
+				if err := repo.Update(&exercise); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, exercise := range lesson.Exercises {
+		if _, removed := existing[exercise.Key]; !removed {
+			continue
+		}
+		if err := remove(repo, plan, opts, "exercise", lessonKey+"#"+exercise.Key, &exercise, nil, exercise.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reconcileVocabulary(repo *repository.ContentRepository, plan *domain.ImportPlan, course *domain.Course, entries []domain.PackageVocabulary, opts domain.ImportOptions) error {
+	apply := !opts.DryRun
+	stored, err := repo.ListVocabulary(course.ID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]domain.Vocabulary, len(stored))
+	for _, entry := range stored {
+		existing[entry.Word] = entry
+	}
+
+	for _, packageEntry := range entries {
+		entry, exists := existing[packageEntry.Word]
+		delete(existing, packageEntry.Word)
+
+		if !exists {
+			base, err := baseModel.NewBaseModel()
+			if err != nil {
+				return err
+			}
+			entry = domain.Vocabulary{BaseModel: *base, CourseID: course.ID}
+			applyVocabularyFields(&entry, packageEntry)
+			plan.Add(domain.ChangeCreate, "vocabulary", packageEntry.Word)
+			if apply {
+				if err := repo.Create(&entry); err != nil {
+					return err
+				}
+			}
+		} else if fields := applyVocabularyFields(&entry, packageEntry); len(fields) > 0 {
+			plan.Add(domain.ChangeUpdate, "vocabulary", packageEntry.Word, fields...)
+			if apply {
+				if err := repo.Update(&entry); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, entry := range stored {
+		if _, removed := existing[entry.Word]; !removed {
+			continue
+		}
+		if err := remove(repo, plan, opts, "vocabulary", entry.Word, &entry, nil, entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove handles stored content missing from the package. Without
+// opts.Prune it is kept. Otherwise it is deleted, except that entities whose
+// learners would lose their progress with it, as counted by countLearners,
+// are left for Import to refuse unless opts.Force is set.
+func remove(repo *repository.ContentRepository, plan *domain.ImportPlan, opts domain.ImportOptions, entity, key string, value interface{}, countLearners func(uuid.UUID) (int64, error), id uuid.UUID) error {
+	if !opts.Prune {
+		plan.Add(domain.ChangeKeep, entity, key)
+		return nil
+	}
+
+	var learners int64
+	if countLearners != nil {
+		var err error
+		if learners, err = countLearners(id); err != nil {
+			return err
+		}
+	}
+	plan.AddDelete(entity, key, learners)
+
+	if opts.DryRun || (learners > 0 && !opts.Force) {
+		return nil
+	}
+	return repo.Delete(value)
+}
+
+// fieldSetter collects the names of fields whose value actually changed.
+type fieldSetter struct {
+	changed []string
+}
+
+func (f *fieldSetter) string(name string, target *string, value string) {
+	if *target != value {
+		*target = value
+		f.changed = append(f.changed, name)
+	}
+}
+
+func (f *fieldSetter) int(name string, target *int, value int) {
+	if *target != value {
+		*target = value
+		f.changed = append(f.changed, name)
+	}
+}
+
+func (f *fieldSetter) bool(name string, target *bool, value bool) {
+	if *target != value {
+		*target = value
+		f.changed = append(f.changed, name)
+	}
+}
+
+func (f *fieldSetter) strings(name string, target *[]string, value []string) {
+	if !slices.Equal(*target, value) {
+		*target = value
+		f.changed = append(f.changed, name)
+	}
+}
+
+func applyCourseFields(course *domain.Course, source domain.PackageCourse) []string {
+	var f fieldSetter
+	f.string("title", &course.Title, source.Title)
+	f.string("description", &course.Description, source.Description)
+	f.string("language_code", &course.LanguageCode, source.LanguageCode)
+	f.string("level", &course.Level, source.Level)
+	f.int("position", &course.Position, source.Position)
+	f.bool("is_published", &course.IsPublished, source.Published)
+	course.Slug = source.Slug
+	return f.changed
+}
+
+func applyUnitFields(unit *domain.Unit, source domain.PackageUnit, position int) []string {
+	var f fieldSetter
+	f.string("title", &unit.Title, source.Title)
+	f.string("description", &unit.Description, source.Description)
+	f.int("position", &unit.Position, position)
+	unit.Slug = source.Slug
+	return f.changed
+}
+
+func applyLessonFields(lesson *domain.Lesson, source domain.PackageLesson, position int) []string {
+	var f fieldSetter
+	f.string("title", &lesson.Title, source.Title)
+	f.string("description", &lesson.Description, source.Description)
+	f.int("position", &lesson.Position, position)
+	f.int("xp_reward", &lesson.XPReward, source.XPReward)
+	lesson.Slug = source.Slug
+	return f.changed
+}
+
+func applyExerciseFields(exercise *domain.Exercise, source domain.PackageExercise, position int) []string {
+	var f fieldSetter
+	choices := []string(exercise.Choices)
+	f.string("type", &exercise.Type, source.Type)
+	f.string("prompt", &exercise.Prompt, source.Prompt)
+	f.string("answer", &exercise.Answer, source.Answer)
+	f.strings("choices", &choices, source.Choices)
+	f.string("explanation", &exercise.Explanation, source.Explanation)
+	f.string("audio_url", &exercise.AudioURL, source.Audio)
+	f.string("image_url", &exercise.ImageURL, source.Image)
+	f.int("position", &exercise.Position, position)
+	exercise.Choices = choices
+	exercise.Key = source.Key
+	return f.changed
+}
+
+func applyVocabularyFields(entry *domain.Vocabulary, source domain.PackageVocabulary) []string {
+	var f fieldSetter
+	f.string("translation", &entry.Translation, source.Translation)
+	f.string("part_of_speech", &entry.PartOfSpeech, source.PartOfSpeech)
+	f.string("example", &entry.Example, source.Example)
+	f.string("audio_url", &entry.AudioURL, source.Audio)
+	entry.Word = source.Word
+	return f.changed
+}
+
+func isRemoteMedia(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
@@ -0,0 +1,484 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/internal/content/domain"
+	userDomain "s29-be/internal/user/domain"
+	"s29-be/pkg/cache"
+	appError "s29-be/pkg/error"
+	baseModel "s29-be/pkg/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const scheduledPublishBatchSize = 50
+
+// RevisionService runs the draft → review → publish workflow for courses,
+// units and lessons. Live content rows are only written when a revision is
+// published, so editors can work on a lesson while learners keep using the
+// published version.
+type RevisionService struct {
+	contentRepo  *repository.ContentRepository
+	contentCache *cache.Cache
+}
+
+func NewRevisionService(contentRepo *repository.ContentRepository, contentCache *cache.Cache) *RevisionService {
+	return &RevisionService{
+		contentRepo:  contentRepo,
+		contentCache: contentCache,
+	}
+}
+
+func (s *RevisionService) ListRevisions(ctx context.Context, query *domain.ListRevisionsQuery) ([]domain.ContentRevision, error) {
+	var entityID *uuid.UUID
+	if query.EntityID != "" {
+		id, err := uuid.Parse(query.EntityID)
+		if err != nil {
+			return nil, appError.NewBadRequestError(err, "invalid entity_id")
+		}
+		entityID = &id
+	}
+
+	revisions, err := s.contentRepo.WithContext(ctx).ListRevisions(query.EntityType, entityID, query.State)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to list revisions")
+	}
+	return revisions, nil
+}
+
+func (s *RevisionService) GetRevision(ctx context.Context, revisionID uuid.UUID) (*domain.ContentRevision, error) {
+	revision, err := s.contentRepo.WithContext(ctx).FindRevisionByID(revisionID)
+	if err != nil {
+		return nil, revisionError(err, "failed to load revision")
+	}
+	return revision, nil
+}
+
+// CreateDraft starts a new revision. Without a snapshot the draft is seeded
+// from the entity's current live content.
+func (s *RevisionService) CreateDraft(ctx context.Context, actor domain.Actor, request *domain.CreateRevisionRequest) (*domain.ContentRevision, error) {
+	var revision *domain.ContentRevision
+	err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		live, err := liveSnapshot(repo, request.EntityType, request.EntityID)
+		if err != nil {
+			return err
+		}
+
+		snapshot := request.Snapshot
+		if len(snapshot) == 0 {
+			if snapshot, err = json.Marshal(live); err != nil {
+				return err
+			}
+		}
+		if err := validateSnapshot(request.EntityType, snapshot); err != nil {
+			return err
+		}
+
+		number, err := repo.NextRevisionNumber(request.EntityType, request.EntityID)
+		if err != nil {
+			return err
+		}
+
+		base, err := baseModel.NewBaseModel()
+		if err != nil {
+			return err
+		}
+		revision = &domain.ContentRevision{
+			BaseModel:      *base,
+			EntityType:     request.EntityType,
+			EntityID:       request.EntityID,
+			RevisionNumber: number,
+			State:          domain.RevisionDraft,
+			Snapshot:       snapshot,
+			AuthorID:       &actor.UserID,
+		}
+		return repo.Create(revision)
+	})
+	if err != nil {
+		return nil, revisionError(err, "failed to create revision")
+	}
+	return revision, nil
+}
+
+// UpdateDraft replaces the snapshot of a draft. Editing a rejected revision
+// moves it back to draft.
+func (s *RevisionService) UpdateDraft(ctx context.Context, actor domain.Actor, revisionID uuid.UUID, request *domain.UpdateRevisionRequest) (*domain.ContentRevision, error) {
+	var revision *domain.ContentRevision
+	err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		var err error
+		revision, err = repo.FindRevisionForUpdate(revisionID)
+		if err != nil {
+			return err
+		}
+		if !revision.State.IsEditable() {
+			return appError.NewBadRequestError(nil, "only draft or rejected revisions can be edited")
+		}
+		if err := validateSnapshot(revision.EntityType, request.Snapshot); err != nil {
+			return err
+		}
+
+		revision.Snapshot = request.Snapshot
+		revision.State = domain.RevisionDraft
+		return repo.Update(revision)
+	})
+	if err != nil {
+		return nil, revisionError(err, "failed to update revision")
+	}
+	return revision, nil
+}
+
+func (s *RevisionService) Submit(ctx context.Context, actor domain.Actor, revisionID uuid.UUID) (*domain.ContentRevision, error) {
+	return s.transition(ctx, actor, revisionID, domain.RevisionInReview, "")
+}
+
+// Withdraw moves a revision under review, or approved but not yet
+// published, back to draft.
+func (s *RevisionService) Withdraw(ctx context.Context, actor domain.Actor, revisionID uuid.UUID) (*domain.ContentRevision, error) {
+	return s.transition(ctx, actor, revisionID, domain.RevisionDraft, "")
+}
+
+func (s *RevisionService) Approve(ctx context.Context, actor domain.Actor, revisionID uuid.UUID, request *domain.ReviewRevisionRequest) (*domain.ContentRevision, error) {
+	return s.transition(ctx, actor, revisionID, domain.RevisionApproved, request.Comment)
+}
+
+func (s *RevisionService) Reject(ctx context.Context, actor domain.Actor, revisionID uuid.UUID, request *domain.ReviewRevisionRequest) (*domain.ContentRevision, error) {
+	return s.transition(ctx, actor, revisionID, domain.RevisionRejected, request.Comment)
+}
+
+func (s *RevisionService) transition(ctx context.Context, actor domain.Actor, revisionID uuid.UUID, to domain.RevisionState, comment string) (*domain.ContentRevision, error) {
+	var revision *domain.ContentRevision
+	err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		var err error
+		revision, err = repo.FindRevisionForUpdate(revisionID)
+		if err != nil {
+			return err
+		}
+		if err := checkTransition(actor, revision, to); err != nil {
+			return err
+		}
+
+		if to == domain.RevisionApproved || to == domain.RevisionRejected {
+			revision.ReviewerID = &actor.UserID
+			revision.ReviewComment = comment
+		}
+		if to == domain.RevisionDraft {
+			revision.ScheduledPublishAt = nil
+		}
+		revision.State = to
+		return repo.Update(revision)
+	})
+	if err != nil {
+		return nil, revisionError(err, "failed to update revision state")
+	}
+	return revision, nil
+}
+
+// Publish makes an approved revision live, or schedules it when PublishAt
+// lies in the future.
+func (s *RevisionService) Publish(ctx context.Context, actor domain.Actor, revisionID uuid.UUID, request *domain.PublishRevisionRequest) (*domain.ContentRevision, error) {
+	var revision *domain.ContentRevision
+	err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		var err error
+		revision, err = repo.FindRevisionForUpdate(revisionID)
+		if err != nil {
+			return err
+		}
+		if err := checkTransition(actor, revision, domain.RevisionPublished); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if request.PublishAt != nil && request.PublishAt.After(now) {
+			publishAt := request.PublishAt.UTC()
+			revision.ScheduledPublishAt = &publishAt
+			revision.PublishedBy = &actor.UserID
+			return repo.Update(revision)
+		}
+
+		return publishRevision(repo, revision, &actor.UserID, now)
+	})
+	if err != nil {
+		return nil, revisionError(err, "failed to publish revision")
+	}
+	if revision.State == domain.RevisionPublished {
+		invalidateEntityCourse(ctx, s.contentRepo, s.contentCache, revision.EntityType, revision.EntityID)
+	}
+	return revision, nil
+}
+
+// Rollback republishes the snapshot of a previously published revision as a
+// new revision, keeping the history linear.
+func (s *RevisionService) Rollback(ctx context.Context, actor domain.Actor, revisionID uuid.UUID) (*domain.ContentRevision, error) {
+	if actor.Role != userDomain.RoleAdmin {
+		return nil, appError.NewForbiddenError(nil, "only admins can roll back content")
+	}
+
+	var revision *domain.ContentRevision
+	err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		target, err := repo.FindRevisionForUpdate(revisionID)
+		if err != nil {
+			return err
+		}
+		if target.State != domain.RevisionSuperseded || target.PublishedAt == nil {
+			return appError.NewBadRequestError(nil, "only previously published revisions can be restored")
+		}
+
+		number, err := repo.NextRevisionNumber(target.EntityType, target.EntityID)
+		if err != nil {
+			return err
+		}
+
+		base, err := baseModel.NewBaseModel()
+		if err != nil {
+			return err
+		}
+		revision = &domain.ContentRevision{
+			BaseModel:      *base,
+			EntityType:     target.EntityType,
+			EntityID:       target.EntityID,
+			RevisionNumber: number,
+			State:          domain.RevisionApproved,
+			Snapshot:       target.Snapshot,
+			AuthorID:       &actor.UserID,
+			ReviewerID:     &actor.UserID,
+			ReviewComment:  "rollback",
+			RolledBackFrom: &target.ID,
+		}
+		if err := repo.Create(revision); err != nil {
+			return err
+		}
+
+		return publishRevision(repo, revision, &actor.UserID, time.Now().UTC())
+	})
+	if err != nil {
+		return nil, revisionError(err, "failed to roll back revision")
+	}
+	invalidateEntityCourse(ctx, s.contentRepo, s.contentCache, revision.EntityType, revision.EntityID)
+	return revision, nil
+}
+
+// PublishDue publishes approved revisions whose schedule has passed and
+// returns how many went live. Each revision is published in its own
+// transaction with its row locked with SKIP LOCKED, so several replicas can
+// run it concurrently and one failing revision does not hold back the rest.
+func (s *RevisionService) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.contentRepo.WithContext(ctx).ListDueRevisionIDs(now, scheduledPublishBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, revisionID := range due {
+		revision, err := s.publishDueRevision(ctx, revisionID, now)
+		if err != nil {
+			s.handlePublishFailure(ctx, revisionID, err)
+			continue
+		}
+		if revision == nil {
+			continue
+		}
+		invalidateEntityCourse(ctx, s.contentRepo, s.contentCache, revision.EntityType, revision.EntityID)
+		published++
+	}
+	return published, nil
+}
+
+// publishDueRevision returns nil without error when the revision was
+// published or locked by another replica in the meantime.
+func (s *RevisionService) publishDueRevision(ctx context.Context, revisionID uuid.UUID, now time.Time) (*domain.ContentRevision, error) {
+	var revision *domain.ContentRevision
+	err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		var err error
+		revision, err = repo.FindDueRevisionForUpdate(revisionID, now)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				revision = nil
+				return nil
+			}
+			return err
+		}
+		return publishRevision(repo, revision, revision.PublishedBy, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// handlePublishFailure unschedules a revision that can never be published,
+// because its entity is gone or its snapshot is invalid, so it is not
+// retried every tick; it stays approved for an editor to fix. Other errors
+// are retried on the next tick.
+func (s *RevisionService) handlePublishFailure(ctx context.Context, revisionID uuid.UUID, err error) {
+	appErr, isAppErr := appError.GetAppError(err)
+	permanent := errors.Is(err, gorm.ErrRecordNotFound) || (isAppErr && appErr.StatusCode < 500)
+	if !permanent {
+		slog.WarnContext(ctx, "Failed to publish scheduled revision, retrying on the next tick",
+			slog.String("revision_id", revisionID.String()), slog.Any("error", err))
+		return
+	}
+
+	slog.ErrorContext(ctx, "Scheduled revision cannot be published, unscheduling it",
+		slog.String("revision_id", revisionID.String()), slog.Any("error", err))
+	if err := s.contentRepo.WithContext(ctx).UnscheduleRevision(revisionID); err != nil {
+		slog.ErrorContext(ctx, "Failed to unschedule revision",
+			slog.String("revision_id", revisionID.String()), slog.Any("error", err))
+	}
+}
+
+// RunScheduledPublisher calls PublishDue every interval until ctx is cancelled.
+func (s *RevisionService) RunScheduledPublisher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := s.PublishDue(ctx, time.Now().UTC())
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to publish scheduled revisions", slog.Any("error", err))
+				continue
+			}
+			if published > 0 {
+				slog.InfoContext(ctx, "Published scheduled revisions", slog.Int("count", published))
+			}
+		}
+	}
+}
+
+func checkTransition(actor domain.Actor, revision *domain.ContentRevision, to domain.RevisionState) error {
+	if !domain.CanTransition(revision.State, to, actor.Role) {
+		if domain.CanTransition(revision.State, to, userDomain.RoleAdmin) {
+			return appError.NewForbiddenError(nil, "your role cannot move a revision from "+string(revision.State)+" to "+string(to))
+		}
+		return appError.NewBadRequestError(nil, "cannot move a revision from "+string(revision.State)+" to "+string(to))
+	}
+
+	// Four-eyes principle: reviewers cannot approve their own work
+	reviewing := to == domain.RevisionApproved || to == domain.RevisionRejected
+	if reviewing && actor.Role != userDomain.RoleAdmin && revision.AuthorID != nil && *revision.AuthorID == actor.UserID {
+		return appError.NewForbiddenError(nil, "authors cannot review their own revisions")
+	}
+	return nil
+}
+
+// publishRevision supersedes the current live revision, writes the snapshot
+// to the live content tables and marks the revision as published.
+func publishRevision(repo *repository.ContentRepository, revision *domain.ContentRevision, publishedBy *uuid.UUID, now time.Time) error {
+	if err := repo.SupersedePublishedRevision(revision.EntityType, revision.EntityID); err != nil {
+		return err
+	}
+	if err := applySnapshot(repo, revision); err != nil {
+		return err
+	}
+	if err := reindexEntityCourse(repo, revision.EntityType, revision.EntityID); err != nil {
+		return err
+	}
+
+	revision.State = domain.RevisionPublished
+	revision.PublishedAt = &now
+	revision.PublishedBy = publishedBy
+	revision.ScheduledPublishAt = nil
+	return repo.Update(revision)
+}
+
+func applySnapshot(repo *repository.ContentRepository, revision *domain.ContentRevision) error {
+	decoded, issues, err := domain.DecodeSnapshot(revision.EntityType, revision.Snapshot)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return appError.NewBadRequestError(nil, "invalid revision snapshot").WithData(issues)
+	}
+
+	switch snapshot := decoded.(type) {
+	case *domain.CourseSnapshot:
+		course, err := repo.FindCourseByID(revision.EntityID)
+		if err != nil {
+			return err
+		}
+		course.Title = snapshot.Title
+		course.Description = snapshot.Description
+		course.LanguageCode = snapshot.LanguageCode
+		course.Level = snapshot.Level
+		course.IsPublished = snapshot.IsPublished
+		return repo.Update(course)
+
+	case *domain.UnitSnapshot:
+		unit, err := repo.FindUnitByID(revision.EntityID)
+		if err != nil {
+			return err
+		}
+		unit.Title = snapshot.Title
+		unit.Description = snapshot.Description
+		return repo.Update(unit)
+
+	case *domain.LessonSnapshot:
+		lesson, err := repo.FindLessonWithExercises(revision.EntityID)
+		if err != nil {
+			return err
+		}
+		lesson.Title = snapshot.Title
+		lesson.Description = snapshot.Description
+		lesson.XPReward = snapshot.XPReward
+		if err := repo.Update(lesson); err != nil {
+			return err
+		}
+		// A snapshot holds the whole lesson, so exercises it drops are deleted
+		return reconcileExercises(repo, &domain.ImportPlan{}, *lesson, lesson.Slug, snapshot.Exercises, domain.ImportOptions{Prune: true})
+	}
+	return nil
+}
+
+func liveSnapshot(repo *repository.ContentRepository, entityType string, entityID uuid.UUID) (interface{}, error) {
+	switch entityType {
+	case domain.EntityCourse:
+		course, err := repo.FindCourseByID(entityID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewCourseSnapshot(course), nil
+	case domain.EntityUnit:
+		unit, err := repo.FindUnitByID(entityID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewUnitSnapshot(unit), nil
+	case domain.EntityLesson:
+		lesson, err := repo.FindLessonWithExercises(entityID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewLessonSnapshot(lesson), nil
+	}
+	return nil, appError.NewBadRequestError(nil, "entity_type must be course, unit or lesson")
+}
+
+func validateSnapshot(entityType string, snapshot json.RawMessage) error {
+	_, issues, err := domain.DecodeSnapshot(entityType, snapshot)
+	if err != nil {
+		return appError.NewBadRequestError(err, "invalid snapshot: "+err.Error())
+	}
+	if len(issues) > 0 {
+		return appError.NewBadRequestError(nil, "invalid snapshot").WithData(issues)
+	}
+	return nil
+}
+
+func revisionError(err error, message string) error {
+	if appError.IsAppError(err) {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return appError.NewNotFoundError(err, "content not found")
+	}
+	return appError.NewInternalError(err, message)
+}
@@ -0,0 +1,192 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/internal/content/domain"
+	appError "s29-be/pkg/error"
+	baseModel "s29-be/pkg/model"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchLength    = 200
+
+	// Suggestions are only worth computing when the query found little
+	suggestionThreshold = 3
+	suggestionLimit     = 5
+)
+
+type SearchService struct {
+	contentRepo *repository.ContentRepository
+}
+
+func NewSearchService(contentRepo *repository.ContentRepository) *SearchService {
+	return &SearchService{
+		contentRepo: contentRepo,
+	}
+}
+
+func (s *SearchService) Search(ctx context.Context, query *domain.SearchQuery) (*domain.SearchResponse, error) {
+	filter, err := newSearchFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := s.contentRepo.WithContext(ctx)
+
+	results, total, err := repo.Search(filter)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to search")
+	}
+
+	response := &domain.SearchResponse{
+		Query:       filter.Text,
+		Total:       total,
+		Results:     results,
+		Suggestions: []string{},
+	}
+
+	if total < suggestionThreshold {
+		suggestions, err := repo.Suggest(filter, suggestionLimit)
+		if err != nil {
+			return nil, appError.NewInternalError(err, "failed to search")
+		}
+		for _, suggestion := range suggestions {
+			if !strings.EqualFold(suggestion, filter.Text) {
+				response.Suggestions = append(response.Suggestions, suggestion)
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// Reindex rebuilds the search documents of one course, or of every course
+// when slug is empty. Publishing keeps the index current; this is for
+// content that predates the index.
+func (s *SearchService) Reindex(ctx context.Context, slug string) (int, error) {
+	var courseIDs []uuid.UUID
+	if slug != "" {
+		course, err := s.contentRepo.WithContext(ctx).FindCourseBySlug(slug)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return 0, appError.NewNotFoundError(err, "course not found")
+			}
+			return 0, appError.NewInternalError(err, "failed to load course")
+		}
+		courseIDs = []uuid.UUID{course.ID}
+	} else {
+		ids, err := s.contentRepo.WithContext(ctx).ListCourseIDs()
+		if err != nil {
+			return 0, appError.NewInternalError(err, "failed to load courses")
+		}
+		courseIDs = ids
+	}
+
+	for _, courseID := range courseIDs {
+		err := s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+			return reindexCourse(repo, courseID)
+		})
+		if err != nil {
+			return 0, appError.NewInternalError(err, "failed to reindex course")
+		}
+	}
+	return len(courseIDs), nil
+}
+
+func newSearchFilter(query *domain.SearchQuery) (*domain.SearchFilter, error) {
+	filter := &domain.SearchFilter{
+		Text:   strings.TrimSpace(query.Q),
+		Level:  query.Level,
+		Type:   query.Type,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	if filter.Text == "" {
+		return nil, appError.NewBadRequestError(nil, "q is required")
+	}
+	if utf8.RuneCountInString(filter.Text) > maxSearchLength {
+		return nil, appError.NewBadRequestError(nil, "q is too long")
+	}
+	if query.CourseID != "" {
+		courseID, err := uuid.Parse(query.CourseID)
+		if err != nil {
+			return nil, appError.NewBadRequestError(err, "Invalid course ID")
+		}
+		filter.CourseID = &courseID
+	}
+	if filter.Level != "" && !slices.Contains(domain.CourseLevels, filter.Level) {
+		return nil, appError.NewBadRequestError(nil, "level must be one of "+strings.Join(domain.CourseLevels, ", "))
+	}
+	if filter.Type != "" && !slices.Contains(domain.SearchEntityTypes, filter.Type) {
+		return nil, appError.NewBadRequestError(nil, "type must be one of "+strings.Join(domain.SearchEntityTypes, ", "))
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchLimit
+	}
+	filter.Limit = min(filter.Limit, maxSearchLimit)
+	filter.Offset = max(filter.Offset, 0)
+
+	return filter, nil
+}
+
+// reindexCourse replaces the search documents of a course with its live
+// content. Unpublished courses end up with no documents. Callers run it in
+// the transaction that changed the content so the index never lags behind.
+func reindexCourse(repo *repository.ContentRepository, courseID uuid.UUID) error {
+	course, err := repo.FindCourseForIndex(courseID)
+	if err != nil {
+		return err
+	}
+	if err := repo.DeleteSearchDocuments(courseID); err != nil {
+		return err
+	}
+	if !course.IsPublished {
+		return nil
+	}
+
+	vocabulary, err := repo.ListVocabulary(courseID)
+	if err != nil {
+		return err
+	}
+
+	entityIDs := domain.CourseEntityIDs(course)
+	for _, entry := range vocabulary {
+		entityIDs = append(entityIDs, entry.ID)
+	}
+	rows, err := repo.ListAllTranslations(entityIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range domain.BuildSearchEntries(course, vocabulary, domain.NewTranslations(rows)) {
+		base, err := baseModel.NewBaseModel()
+		if err != nil {
+			return err
+		}
+		entry.Document.BaseModel = *base
+		if err := repo.InsertSearchEntry(&entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexEntityCourse reindexes the course that owns a content entity.
+func reindexEntityCourse(repo *repository.ContentRepository, entityType string, entityID uuid.UUID) error {
+	courseID, err := repo.FindCourseIDForEntity(entityType, entityID)
+	if err != nil {
+		return err
+	}
+	return reindexCourse(repo, courseID)
+}
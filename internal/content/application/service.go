@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/internal/content/domain"
+	"s29-be/pkg/cache"
+	appError "s29-be/pkg/error"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ContentService struct {
+	contentRepo  *repository.ContentRepository
+	contentCache *cache.Cache
+}
+
+func NewContentService(contentRepo *repository.ContentRepository, contentCache *cache.Cache) *ContentService {
+	return &ContentService{
+		contentRepo:  contentRepo,
+		contentCache: contentCache,
+	}
+}
+
+// ListCourses returns published courses with titles in language, falling
+// back along its chain to the authored text.
+func (s *ContentService) ListCourses(ctx context.Context, query *domain.ListCoursesQuery, language string) ([]domain.CourseSummary, error) {
+	summaries, err := cache.GetOrLoad(ctx, s.contentCache, courseListCacheKey(query.Level, language),
+		cache.LoadOptions{Tags: []string{courseListTag}},
+		func(ctx context.Context) ([]domain.CourseSummary, error) {
+			return s.loadCourseSummaries(ctx, query.Level, language)
+		})
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to list courses")
+	}
+	return summaries, nil
+}
+
+func (s *ContentService) loadCourseSummaries(ctx context.Context, level, language string) ([]domain.CourseSummary, error) {
+	repo := s.contentRepo.WithContext(ctx)
+
+	courses, err := repo.ListPublishedCourses(level)
+	if err != nil {
+		return nil, err
+	}
+
+	courseIDs := make([]uuid.UUID, 0, len(courses))
+	for _, course := range courses {
+		courseIDs = append(courseIDs, course.ID)
+	}
+	translations, chain, err := loadTranslations(repo, courseIDs, language)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domain.CourseSummary, 0, len(courses))
+	for _, course := range courses {
+		lessonCount := 0
+		for _, unit := range course.Units {
+			lessonCount += len(unit.Lessons)
+		}
+		summaries = append(summaries, domain.CourseSummary{
+			ID:           course.ID,
+			Slug:         course.Slug,
+			Title:        translations.Resolve(course.ID, "title", chain, course.Title),
+			Description:  translations.Resolve(course.ID, "description", chain, course.Description),
+			LanguageCode: course.LanguageCode,
+			Level:        course.Level,
+			UnitCount:    len(course.Units),
+			LessonCount:  lessonCount,
+		})
+	}
+
+	return summaries, nil
+}
+
+func (s *ContentService) GetCourse(ctx context.Context, courseID uuid.UUID, language string) (*domain.Course, error) {
+	course, err := cache.GetOrLoad(ctx, s.contentCache, courseCacheKey(courseID, language),
+		cache.LoadOptions{Tags: []string{courseTag(courseID)}},
+		func(ctx context.Context) (*domain.Course, error) {
+			return s.loadCourse(ctx, courseID, language)
+		})
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, appError.NewNotFoundError(err, "course not found")
+		}
+		return nil, appError.NewInternalError(err, "failed to load course")
+	}
+	return course, nil
+}
+
+// loadCourse returns the localized tree of a published course, or
+// cache.ErrNotFound so that misses are cached too.
+func (s *ContentService) loadCourse(ctx context.Context, courseID uuid.UUID, language string) (*domain.Course, error) {
+	repo := s.contentRepo.WithContext(ctx)
+
+	course, err := repo.FindCourseTree(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cache.ErrNotFound
+		}
+		return nil, err
+	}
+
+	if !course.IsPublished {
+		return nil, cache.ErrNotFound
+	}
+
+	translations, chain, err := loadTranslations(repo, domain.CourseEntityIDs(course), language)
+	if err != nil {
+		return nil, err
+	}
+	translations.LocalizeCourse(course, chain)
+
+	return course, nil
+}
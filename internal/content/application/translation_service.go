@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/internal/content/domain"
+	"s29-be/pkg/cache"
+	appError "s29-be/pkg/error"
+	"s29-be/pkg/i18n"
+	baseModel "s29-be/pkg/model"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// TranslationService manages translated values of content text fields.
+// Translations are applied on read and do not go through the revision
+// workflow, so translators can work on live content.
+type TranslationService struct {
+	contentRepo  *repository.ContentRepository
+	contentCache *cache.Cache
+}
+
+func NewTranslationService(contentRepo *repository.ContentRepository, contentCache *cache.Cache) *TranslationService {
+	return &TranslationService{
+		contentRepo:  contentRepo,
+		contentCache: contentCache,
+	}
+}
+
+func (s *TranslationService) ListTranslations(ctx context.Context, query *domain.ListTranslationsQuery) ([]domain.ContentTranslation, error) {
+	if _, ok := domain.TranslatableFields[query.EntityType]; !ok {
+		return nil, appError.NewBadRequestError(nil, "invalid translation")
+	}
+	entityID, err := uuid.Parse(query.EntityID)
+	if err != nil {
+		return nil, appError.NewBadRequestError(err, "invalid entity_id")
+	}
+
+	translations, err := s.contentRepo.WithContext(ctx).ListEntityTranslations(query.EntityType, entityID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to list translations")
+	}
+	return translations, nil
+}
+
+func (s *TranslationService) SaveTranslations(ctx context.Context, request *domain.SaveTranslationsRequest) ([]domain.ContentTranslation, error) {
+	fields, ok := domain.TranslatableFields[request.EntityType]
+	if !ok {
+		return nil, appError.NewBadRequestError(nil, "invalid translation").
+			WithData("entity_type must be one of course, unit, lesson, exercise or vocabulary")
+	}
+	language := i18n.Normalize(request.LanguageCode)
+	if language == "" {
+		return nil, appError.NewBadRequestError(nil, "invalid translation").
+			WithData("language_code must be one of " + strings.Join(i18n.Supported(), ", "))
+	}
+	for field := range request.Fields {
+		if !slices.Contains(fields, field) {
+			return nil, appError.NewBadRequestError(nil, "invalid translation").
+				WithData(request.EntityType + " fields are " + strings.Join(fields, ", "))
+		}
+	}
+
+	count, err := s.contentRepo.WithContext(ctx).CountEntity(request.EntityType, request.EntityID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load content")
+	}
+	if count == 0 {
+		return nil, appError.NewNotFoundError(nil, "content not found")
+	}
+
+	err = s.contentRepo.WithContext(ctx).Transaction(func(repo *repository.ContentRepository) error {
+		for field, value := range request.Fields {
+			if strings.TrimSpace(value) == "" {
+				if err := repo.DeleteTranslation(request.EntityType, request.EntityID, language, field); err != nil {
+					return err
+				}
+				continue
+			}
+
+			base, err := baseModel.NewBaseModel()
+			if err != nil {
+				return err
+			}
+			err = repo.UpsertTranslation(&domain.ContentTranslation{
+				BaseModel:    *base,
+				EntityType:   request.EntityType,
+				EntityID:     request.EntityID,
+				LanguageCode: language,
+				Field:        field,
+				Value:        value,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return reindexEntityCourse(repo, request.EntityType, request.EntityID)
+	})
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to save translations")
+	}
+	invalidateEntityCourse(ctx, s.contentRepo, s.contentCache, request.EntityType, request.EntityID)
+
+	return s.ListTranslations(ctx, &domain.ListTranslationsQuery{
+		EntityType: request.EntityType,
+		EntityID:   request.EntityID.String(),
+	})
+}
+
+// loadTranslations fetches the translations needed to localize entityIDs
+// along the fallback chain of language.
+func loadTranslations(repo *repository.ContentRepository, entityIDs []uuid.UUID, language string) (domain.Translations, []string, error) {
+	chain := i18n.FallbackChain(language)
+	rows, err := repo.ListTranslations(entityIDs, chain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return domain.NewTranslations(rows), chain, nil
+}
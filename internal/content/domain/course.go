@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"s29-be/pkg/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	ExerciseMultipleChoice = "multiple_choice"
+	ExerciseTranslate      = "translate"
+	ExerciseListen         = "listen"
+	ExerciseSpeak          = "speak"
+	ExerciseFillBlank      = "fill_blank"
+)
+
+var ExerciseTypes = []string{
+	ExerciseMultipleChoice,
+	ExerciseTranslate,
+	ExerciseListen,
+	ExerciseSpeak,
+	ExerciseFillBlank,
+}
+
+var CourseLevels = []string{"beginner", "intermediate", "advanced"}
+
+type Course struct {
+	model.BaseModel
+	Slug         string `json:"slug" gorm:"not null;unique;size:100"`
+	Title        string `json:"title" gorm:"not null;size:255"`
+	Description  string `json:"description"`
+	LanguageCode string `json:"language_code" gorm:"not null;size:10;default:vi"`
+	Level        string `json:"level" gorm:"not null;size:20;default:beginner"`
+	Position     int    `json:"position" gorm:"not null;default:0"`
+	IsPublished  bool   `json:"is_published" gorm:"default:false"`
+	Units        []Unit `json:"units,omitempty" gorm:"foreignKey:CourseID"`
+}
+
+type Unit struct {
+	model.BaseModel
+	CourseID    uuid.UUID `json:"course_id" gorm:"not null;type:uuid"`
+	Slug        string    `json:"slug" gorm:"not null;size:100"`
+	Title       string    `json:"title" gorm:"not null;size:255"`
+	Description string    `json:"description"`
+	Position    int       `json:"position" gorm:"not null;default:0"`
+	Lessons     []Lesson  `json:"lessons,omitempty" gorm:"foreignKey:UnitID"`
+}
+
+type Lesson struct {
+	model.BaseModel
+	UnitID      uuid.UUID  `json:"unit_id" gorm:"not null;type:uuid"`
+	Slug        string     `json:"slug" gorm:"not null;size:100"`
+	Title       string     `json:"title" gorm:"not null;size:255"`
+	Description string     `json:"description"`
+	Position    int        `json:"position" gorm:"not null;default:0"`
+	XPReward    int        `json:"xp_reward" gorm:"column:xp_reward;not null;default:10"`
+	Exercises   []Exercise `json:"exercises,omitempty" gorm:"foreignKey:LessonID"`
+}
+
+type Exercise struct {
+	model.BaseModel
+	LessonID    uuid.UUID      `json:"lesson_id" gorm:"not null;type:uuid"`
+	Key         string         `json:"key" gorm:"not null;size:100"`
+	Type        string         `json:"type" gorm:"not null;size:30"`
+	Prompt      string         `json:"prompt" gorm:"not null"`
+	Answer      string         `json:"answer" gorm:"not null"`
+	Choices     pq.StringArray `json:"choices" gorm:"type:text[]"`
+	Explanation string         `json:"explanation"`
+	AudioURL    string         `json:"audio_url" gorm:"size:500"`
+	ImageURL    string         `json:"image_url" gorm:"size:500"`
+	Position    int            `json:"position" gorm:"not null;default:0"`
+}
+
+type Vocabulary struct {
+	model.BaseModel
+	CourseID     uuid.UUID `json:"course_id" gorm:"not null;type:uuid"`
+	Word         string    `json:"word" gorm:"not null;size:255"`
+	Translation  string    `json:"translation" gorm:"not null;size:255"`
+	PartOfSpeech string    `json:"part_of_speech" gorm:"size:30"`
+	Example      string    `json:"example"`
+	AudioURL     string    `json:"audio_url" gorm:"size:500"`
+}
+
+func (Vocabulary) TableName() string {
+	return "vocabulary"
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CourseSummary struct {
+	ID           uuid.UUID `json:"id"`
+	Slug         string    `json:"slug"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	LanguageCode string    `json:"language_code"`
+	Level        string    `json:"level"`
+	UnitCount    int       `json:"unit_count"`
+	LessonCount  int       `json:"lesson_count"`
+}
+
+type ListCoursesQuery struct {
+	Level string `query:"level" validate:"omitempty,oneof=beginner intermediate advanced"`
+}
+
+type ListRevisionsQuery struct {
+	EntityType string `query:"entity_type" validate:"omitempty,oneof=course unit lesson"`
+	EntityID   string `query:"entity_id" validate:"omitempty,uuid"`
+	State      string `query:"state" validate:"omitempty,oneof=draft in_review approved rejected published superseded"`
+}
+
+type CreateRevisionRequest struct {
+	EntityType string          `json:"entity_type" validate:"required,oneof=course unit lesson"`
+	EntityID   uuid.UUID       `json:"entity_id" validate:"required"`
+	Snapshot   json.RawMessage `json:"snapshot" swaggertype:"object"`
+}
+
+type UpdateRevisionRequest struct {
+	Snapshot json.RawMessage `json:"snapshot" swaggertype:"object"`
+}
+
+type ReviewRevisionRequest struct {
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+// PublishRevisionRequest publishes immediately unless PublishAt is in the future.
+type PublishRevisionRequest struct {
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// Actor is the authenticated user performing a workflow action.
+type Actor struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+type ListTranslationsQuery struct {
+	EntityType string `query:"entity_type" validate:"required,oneof=course unit lesson exercise vocabulary"`
+	EntityID   string `query:"entity_id" validate:"required,uuid"`
+}
+
+// SaveTranslationsRequest sets translated fields of one entity in one
+// language. An empty value removes the translation for that field.
+type SaveTranslationsRequest struct {
+	EntityType   string            `json:"entity_type" validate:"required,oneof=course unit lesson exercise vocabulary"`
+	EntityID     uuid.UUID         `json:"entity_id" validate:"required"`
+	LanguageCode string            `json:"language_code" validate:"required,locale"`
+	Fields       map[string]string `json:"fields" validate:"required,min=1"`
+}
+
+type SearchQuery struct {
+	Q        string `query:"q" validate:"required,max=200"`
+	CourseID string `query:"course_id" validate:"omitempty,uuid"`
+	Level    string `query:"level" validate:"omitempty,oneof=beginner intermediate advanced"`
+	Type     string `query:"type" validate:"omitempty,oneof=course lesson vocabulary"`
+	Limit    int    `query:"limit" validate:"min=0"`
+	Offset   int    `query:"offset" validate:"min=0"`
+}
+
+// SearchFilter is a validated SearchQuery.
+type SearchFilter struct {
+	Text     string
+	CourseID *uuid.UUID
+	Level    string
+	Type     string
+	Limit    int
+	Offset   int
+}
+
+type SearchResult struct {
+	EntityType     string    `json:"entity_type"`
+	EntityID       uuid.UUID `json:"entity_id"`
+	CourseID       uuid.UUID `json:"course_id"`
+	CourseSlug     string    `json:"course_slug"`
+	CourseTitle    string    `json:"course_title"`
+	Level          string    `json:"level"`
+	Title          string    `json:"title"`
+	TitleHighlight string    `json:"title_highlight"`
+	Snippet        string    `json:"snippet"`
+	Rank           float64   `json:"rank"`
+}
+
+// SearchResponse carries suggestions when the query found little, so
+// clients can offer a corrected spelling.
+type SearchResponse struct {
+	Query       string         `json:"query"`
+	Total       int64          `json:"total"`
+	Results     []SearchResult `json:"results"`
+	Suggestions []string       `json:"suggestions"`
+}
@@ -0,0 +1,153 @@
+package domain
+
+import "fmt"
+
+// PackageFormatVersion is the course package format this build reads and
+// writes. Packages declaring a newer version are rejected instead of being
+// imported partially.
+const PackageFormatVersion = 1
+
+// CoursePackage is the file-format independent representation of a course
+// as authored by the content team. Units, lessons and exercises are matched
+// against the database by slug/key, so a package can be imported repeatedly.
+type CoursePackage struct {
+	FormatVersion int                 `json:"format_version"`
+	Course        PackageCourse       `json:"course"`
+	Units         []PackageUnit       `json:"units"`
+	Vocabulary    []PackageVocabulary `json:"vocabulary"`
+	Media         []string            `json:"media"`
+}
+
+type PackageCourse struct {
+	Slug         string `json:"slug"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	LanguageCode string `json:"language_code"`
+	Level        string `json:"level"`
+	Position     int    `json:"position"`
+	Published    bool   `json:"published"`
+}
+
+type PackageUnit struct {
+	Slug        string          `json:"slug"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Lessons     []PackageLesson `json:"lessons"`
+}
+
+type PackageLesson struct {
+	Slug        string            `json:"slug"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	XPReward    int               `json:"xp_reward"`
+	Exercises   []PackageExercise `json:"exercises"`
+}
+
+type PackageExercise struct {
+	Key         string   `json:"key"`
+	Type        string   `json:"type"`
+	Prompt      string   `json:"prompt"`
+	Answer      string   `json:"answer"`
+	Choices     []string `json:"choices"`
+	Explanation string   `json:"explanation"`
+	Audio       string   `json:"audio"`
+	Image       string   `json:"image"`
+}
+
+type PackageVocabulary struct {
+	Word         string `json:"word"`
+	Translation  string `json:"translation"`
+	PartOfSpeech string `json:"part_of_speech"`
+	Example      string `json:"example"`
+	Audio        string `json:"audio"`
+}
+
+// ValidationIssue points at the offending file or manifest path.
+type ValidationIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+	// ChangeKeep marks stored content missing from the package that is left
+	// in place because the import does not prune.
+	ChangeKeep = "keep"
+)
+
+// ImportOptions controls how a package is applied.
+type ImportOptions struct {
+	// DryRun only computes the plan.
+	DryRun bool
+	// Prune deletes stored content missing from the package. Without it such
+	// content is kept, so a renamed slug or a partial package loses nothing.
+	Prune bool
+	// Force prunes units and lessons learners have progress on. Deleting
+	// them deletes that progress too.
+	Force bool
+}
+
+type PackageChange struct {
+	Action string   `json:"action"`
+	Entity string   `json:"entity"`
+	Key    string   `json:"key"`
+	Fields []string `json:"fields,omitempty"`
+	// Learners counts the users whose progress a delete would remove.
+	Learners int64 `json:"learners,omitempty"`
+}
+
+// ImportPlan is the diff between a package and the database. With DryRun
+// set nothing has been written.
+type ImportPlan struct {
+	CourseSlug string          `json:"course_slug"`
+	DryRun     bool            `json:"dry_run"`
+	Changes    []PackageChange `json:"changes"`
+	Created    int             `json:"created"`
+	Updated    int             `json:"updated"`
+	Deleted    int             `json:"deleted"`
+	Kept       int             `json:"kept"`
+}
+
+func (p *ImportPlan) Add(action, entity, key string, fields ...string) {
+	p.Changes = append(p.Changes, PackageChange{
+		Action: action,
+		Entity: entity,
+		Key:    key,
+		Fields: fields,
+	})
+
+	switch action {
+	case ChangeCreate:
+		p.Created++
+	case ChangeUpdate:
+		p.Updated++
+	case ChangeDelete:
+		p.Deleted++
+	case ChangeKeep:
+		p.Kept++
+	}
+}
+
+// AddDelete records a deletion along with the number of learners who have
+// progress on the deleted entity.
+func (p *ImportPlan) AddDelete(entity, key string, learners int64) {
+	p.Add(ChangeDelete, entity, key)
+	p.Changes[len(p.Changes)-1].Learners = learners
+}
+
+// LearnerDeletes returns the deletions that would remove learner progress.
+func (p *ImportPlan) LearnerDeletes() []PackageChange {
+	var changes []PackageChange
+	for _, change := range p.Changes {
+		if change.Action == ChangeDelete && change.Learners > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
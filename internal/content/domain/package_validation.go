@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Validate checks the package against the schema of format version
+// PackageFormatVersion and returns every problem found rather than stopping
+// at the first one, so authors can fix a spreadsheet in one pass.
+func (p *CoursePackage) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(path, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if p.FormatVersion != PackageFormatVersion {
+		add("format_version", "unsupported format version %d, expected %d", p.FormatVersion, PackageFormatVersion)
+	}
+
+	if !slugPattern.MatchString(p.Course.Slug) {
+		add("course.slug", "must be lowercase letters, digits and dashes")
+	}
+	if strings.TrimSpace(p.Course.Title) == "" {
+		add("course.title", "is required")
+	}
+	if p.Course.Level != "" && !slices.Contains(CourseLevels, p.Course.Level) {
+		add("course.level", "must be one of %s", strings.Join(CourseLevels, ", "))
+	}
+
+	media := make(map[string]bool, len(p.Media))
+	for _, ref := range p.Media {
+		media[ref] = true
+	}
+	checkMedia := func(path, ref string) {
+		if ref == "" || strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://") {
+			return
+		}
+		if !media[ref] {
+			add(path, "media reference %q is not listed in the manifest media section", ref)
+		}
+	}
+
+	if len(p.Units) == 0 {
+		add("units", "at least one unit is required")
+	}
+
+	unitSlugs := make(map[string]bool, len(p.Units))
+	for i, unit := range p.Units {
+		unitPath := fmt.Sprintf("units[%d]", i)
+		if !slugPattern.MatchString(unit.Slug) {
+			add(unitPath+".slug", "must be lowercase letters, digits and dashes")
+		} else if unitSlugs[unit.Slug] {
+			add(unitPath+".slug", "duplicate unit slug %q", unit.Slug)
+		}
+		unitSlugs[unit.Slug] = true
+
+		if strings.TrimSpace(unit.Title) == "" {
+			add(unitPath+".title", "is required")
+		}
+		if len(unit.Lessons) == 0 {
+			add(unitPath+".lessons", "at least one lesson is required")
+		}
+
+		lessonSlugs := make(map[string]bool, len(unit.Lessons))
+		for j, lesson := range unit.Lessons {
+			lessonPath := fmt.Sprintf("%s.lessons[%d]", unitPath, j)
+			if !slugPattern.MatchString(lesson.Slug) {
+				add(lessonPath+".slug", "must be lowercase letters, digits and dashes")
+			} else if lessonSlugs[lesson.Slug] {
+				add(lessonPath+".slug", "duplicate lesson slug %q", lesson.Slug)
+			}
+			lessonSlugs[lesson.Slug] = true
+
+			if strings.TrimSpace(lesson.Title) == "" {
+				add(lessonPath+".title", "is required")
+			}
+			if lesson.XPReward < 0 {
+				add(lessonPath+".xp_reward", "must not be negative")
+			}
+			if len(lesson.Exercises) == 0 {
+				add(lessonPath+".exercises", "at least one exercise is required")
+			}
+
+			validateExercises(lessonPath+".exercises", lesson.Exercises, add)
+			for k, exercise := range lesson.Exercises {
+				exercisePath := fmt.Sprintf("%s.exercises[%d]", lessonPath, k)
+				checkMedia(exercisePath+".audio", exercise.Audio)
+				checkMedia(exercisePath+".image", exercise.Image)
+			}
+		}
+	}
+
+	words := make(map[string]bool, len(p.Vocabulary))
+	for i, entry := range p.Vocabulary {
+		entryPath := fmt.Sprintf("vocabulary[%d]", i)
+		if strings.TrimSpace(entry.Word) == "" {
+			add(entryPath+".word", "is required")
+		} else if words[entry.Word] {
+			add(entryPath+".word", "duplicate word %q", entry.Word)
+		}
+		words[entry.Word] = true
+
+		if strings.TrimSpace(entry.Translation) == "" {
+			add(entryPath+".translation", "is required")
+		}
+		checkMedia(entryPath+".audio", entry.Audio)
+	}
+
+	return issues
+}
+
+func validateExercises(path string, exercises []PackageExercise, add func(path, format string, args ...interface{})) {
+	keys := make(map[string]bool, len(exercises))
+	for i, exercise := range exercises {
+		exercisePath := fmt.Sprintf("%s[%d]", path, i)
+		if !slugPattern.MatchString(exercise.Key) {
+			add(exercisePath+".key", "must be lowercase letters, digits and dashes")
+		} else if keys[exercise.Key] {
+			add(exercisePath+".key", "duplicate exercise key %q", exercise.Key)
+		}
+		keys[exercise.Key] = true
+
+		if !slices.Contains(ExerciseTypes, exercise.Type) {
+			add(exercisePath+".type", "must be one of %s", strings.Join(ExerciseTypes, ", "))
+		}
+		if strings.TrimSpace(exercise.Prompt) == "" {
+			add(exercisePath+".prompt", "is required")
+		}
+		if strings.TrimSpace(exercise.Answer) == "" {
+			add(exercisePath+".answer", "is required")
+		}
+		if exercise.Type == ExerciseMultipleChoice {
+			if len(exercise.Choices) < 2 {
+				add(exercisePath+".choices", "multiple choice exercises need at least two choices")
+			} else if !slices.Contains(exercise.Choices, exercise.Answer) {
+				add(exercisePath+".choices", "must contain the answer %q", exercise.Answer)
+			}
+		}
+		if exercise.Type == ExerciseListen && exercise.Audio == "" {
+			add(exercisePath+".audio", "listen exercises need an audio reference")
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	userDomain "s29-be/internal/user/domain"
+	"s29-be/pkg/model"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EntityCourse = "course"
+	EntityUnit   = "unit"
+	EntityLesson = "lesson"
+)
+
+type RevisionState string
+
+const (
+	RevisionDraft      RevisionState = "draft"
+	RevisionInReview   RevisionState = "in_review"
+	RevisionApproved   RevisionState = "approved"
+	RevisionRejected   RevisionState = "rejected"
+	RevisionPublished  RevisionState = "published"
+	RevisionSuperseded RevisionState = "superseded"
+)
+
+// revisionTransitions lists, per state, the states a revision may move to
+// and the roles allowed to move it there. Superseding is done by the system
+// when another revision of the same entity is published.
+var revisionTransitions = map[RevisionState]map[RevisionState][]string{
+	RevisionDraft: {
+		RevisionInReview: {userDomain.RoleEditor, userDomain.RoleAdmin},
+	},
+	RevisionInReview: {
+		RevisionApproved: {userDomain.RoleReviewer, userDomain.RoleAdmin},
+		RevisionRejected: {userDomain.RoleReviewer, userDomain.RoleAdmin},
+		RevisionDraft:    {userDomain.RoleEditor, userDomain.RoleAdmin},
+	},
+	RevisionRejected: {
+		RevisionDraft: {userDomain.RoleEditor, userDomain.RoleAdmin},
+	},
+	RevisionApproved: {
+		RevisionPublished: {userDomain.RoleReviewer, userDomain.RoleAdmin},
+		RevisionDraft:     {userDomain.RoleEditor, userDomain.RoleAdmin},
+	},
+}
+
+// CanTransition reports whether role may move a revision from one state to another.
+func CanTransition(from, to RevisionState, role string) bool {
+	roles, ok := revisionTransitions[from][to]
+	return ok && slices.Contains(roles, role)
+}
+
+// IsEditable reports whether the snapshot of a revision in this state may still change.
+func (s RevisionState) IsEditable() bool {
+	return s == RevisionDraft || s == RevisionRejected
+}
+
+type ContentRevision struct {
+	model.BaseModel
+	EntityType         string          `json:"entity_type" gorm:"not null;size:20"`
+	EntityID           uuid.UUID       `json:"entity_id" gorm:"not null;type:uuid"`
+	RevisionNumber     int             `json:"revision_number" gorm:"not null"`
+	State              RevisionState   `json:"state" gorm:"not null;size:20;default:draft"`
+	Snapshot           json.RawMessage `json:"snapshot" gorm:"type:jsonb;not null"`
+	AuthorID           *uuid.UUID      `json:"author_id" gorm:"type:uuid"`
+	ReviewerID         *uuid.UUID      `json:"reviewer_id" gorm:"type:uuid"`
+	ReviewComment      string          `json:"review_comment"`
+	ScheduledPublishAt *time.Time      `json:"scheduled_publish_at"`
+	PublishedAt        *time.Time      `json:"published_at"`
+	PublishedBy        *uuid.UUID      `json:"published_by" gorm:"type:uuid"`
+	RolledBackFrom     *uuid.UUID      `json:"rolled_back_from" gorm:"type:uuid"`
+}
+
+type CourseSnapshot struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	LanguageCode string `json:"language_code"`
+	Level        string `json:"level"`
+	IsPublished  bool   `json:"is_published"`
+}
+
+type UnitSnapshot struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// LessonSnapshot reuses the package exercise shape so revisions, packages
+// and lesson sessions all describe exercises the same way.
+type LessonSnapshot struct {
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	XPReward    int               `json:"xp_reward"`
+	Exercises   []PackageExercise `json:"exercises"`
+}
+
+func NewCourseSnapshot(course *Course) CourseSnapshot {
+	return CourseSnapshot{
+		Title:        course.Title,
+		Description:  course.Description,
+		LanguageCode: course.LanguageCode,
+		Level:        course.Level,
+		IsPublished:  course.IsPublished,
+	}
+}
+
+func NewUnitSnapshot(unit *Unit) UnitSnapshot {
+	return UnitSnapshot{
+		Title:       unit.Title,
+		Description: unit.Description,
+	}
+}
+
+func NewLessonSnapshot(lesson *Lesson) LessonSnapshot {
+	snapshot := LessonSnapshot{
+		Title:       lesson.Title,
+		Description: lesson.Description,
+		XPReward:    lesson.XPReward,
+		Exercises:   make([]PackageExercise, 0, len(lesson.Exercises)),
+	}
+	for _, exercise := range lesson.Exercises {
+		snapshot.Exercises = append(snapshot.Exercises, PackageExercise{
+			Key:         exercise.Key,
+			Type:        exercise.Type,
+			Prompt:      exercise.Prompt,
+			Answer:      exercise.Answer,
+			Choices:     exercise.Choices,
+			Explanation: exercise.Explanation,
+			Audio:       exercise.AudioURL,
+			Image:       exercise.ImageURL,
+		})
+	}
+	return snapshot
+}
+
+func (s *CourseSnapshot) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	if strings.TrimSpace(s.Title) == "" {
+		issues = append(issues, ValidationIssue{Path: "title", Message: "is required"})
+	}
+	if !slices.Contains(CourseLevels, s.Level) {
+		issues = append(issues, ValidationIssue{Path: "level", Message: "must be one of " + strings.Join(CourseLevels, ", ")})
+	}
+	return issues
+}
+
+func (s *UnitSnapshot) Validate() []ValidationIssue {
+	if strings.TrimSpace(s.Title) == "" {
+		return []ValidationIssue{{Path: "title", Message: "is required"}}
+	}
+	return nil
+}
+
+func (s *LessonSnapshot) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(path, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(s.Title) == "" {
+		add("title", "is required")
+	}
+	if s.XPReward < 0 {
+		add("xp_reward", "must not be negative")
+	}
+	if len(s.Exercises) == 0 {
+		add("exercises", "at least one exercise is required")
+	}
+	validateExercises("exercises", s.Exercises, add)
+	return issues
+}
+
+// DecodeSnapshot unmarshals and validates a snapshot for the given entity type.
+func DecodeSnapshot(entityType string, raw json.RawMessage) (interface{}, []ValidationIssue, error) {
+	var snapshot interface {
+		Validate() []ValidationIssue
+	}
+	switch entityType {
+	case EntityCourse:
+		snapshot = &CourseSnapshot{}
+	case EntityUnit:
+		snapshot = &UnitSnapshot{}
+	case EntityLesson:
+		snapshot = &LessonSnapshot{}
+	default:
+		return nil, nil, fmt.Errorf("unknown entity type %q", entityType)
+	}
+
+	if err := json.Unmarshal(raw, snapshot); err != nil {
+		return nil, nil, err
+	}
+	return snapshot, snapshot.Validate(), nil
+}
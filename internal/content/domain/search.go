@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"s29-be/pkg/model"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// SearchEntityTypes lists the kinds of documents the search index holds.
+var SearchEntityTypes = []string{EntityCourse, EntityLesson, EntityVocabulary}
+
+type SearchDocument struct {
+	model.BaseModel
+	EntityType string    `json:"entity_type" gorm:"not null;size:20"`
+	EntityID   uuid.UUID `json:"entity_id" gorm:"not null;type:uuid"`
+	CourseID   uuid.UUID `json:"course_id" gorm:"not null;type:uuid"`
+	Level      string    `json:"level" gorm:"not null;size:20"`
+	Title      string    `json:"title" gorm:"not null;size:255"`
+	Body       string    `json:"body" gorm:"not null"`
+}
+
+// SearchEntry is a document to index with its text split by ranking
+// weight: Primary is weighted highest, Detail lowest.
+type SearchEntry struct {
+	Document  SearchDocument
+	Primary   string
+	Secondary string
+	Detail    string
+}
+
+// BuildSearchEntries turns a published course tree and its vocabulary into
+// search entries. Translations of every language are indexed as secondary
+// text so learners can search in their own language.
+func BuildSearchEntries(course *Course, vocabulary []Vocabulary, translations Translations) []SearchEntry {
+	document := func(entityType string, entityID uuid.UUID, title, body string) SearchDocument {
+		return SearchDocument{
+			EntityType: entityType,
+			EntityID:   entityID,
+			CourseID:   course.ID,
+			Level:      course.Level,
+			Title:      title,
+			Body:       body,
+		}
+	}
+
+	entries := []SearchEntry{{
+		Document:  document(EntityCourse, course.ID, course.Title, course.Description),
+		Primary:   course.Title,
+		Secondary: translations.values(course.ID, "title"),
+		Detail:    joinText(course.Description, translations.values(course.ID, "description")),
+	}}
+
+	for _, unit := range course.Units {
+		for _, lesson := range unit.Lessons {
+			exerciseText := make([]string, 0, len(lesson.Exercises)*3)
+			for _, exercise := range lesson.Exercises {
+				exerciseText = append(exerciseText,
+					exercise.Prompt,
+					exercise.Answer,
+					exercise.Explanation,
+					translations.values(exercise.ID, "prompt", "explanation"),
+				)
+			}
+			entries = append(entries, SearchEntry{
+				Document:  document(EntityLesson, lesson.ID, lesson.Title, joinText(lesson.Description, joinText(exerciseText...))),
+				Primary:   lesson.Title,
+				Secondary: joinText(unit.Title, translations.values(lesson.ID, "title")),
+				Detail:    joinText(lesson.Description, translations.values(lesson.ID, "description"), joinText(exerciseText...)),
+			})
+		}
+	}
+
+	for _, entry := range vocabulary {
+		entries = append(entries, SearchEntry{
+			Document:  document(EntityVocabulary, entry.ID, entry.Word, joinText(entry.Translation, entry.Example)),
+			Primary:   entry.Word,
+			Secondary: joinText(entry.Translation, translations.values(entry.ID, "translation")),
+			Detail:    joinText(entry.Example, translations.values(entry.ID, "example")),
+		})
+	}
+
+	return entries
+}
+
+// values joins the translations of the given fields across all languages.
+func (t Translations) values(entityID uuid.UUID, fields ...string) string {
+	var parts []string
+	for _, byField := range t[entityID] {
+		for _, field := range fields {
+			parts = append(parts, byField[field])
+		}
+	}
+	return joinText(parts...)
+}
+
+func joinText(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, "\n")
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"s29-be/pkg/model"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EntityExercise   = "exercise"
+	EntityVocabulary = "vocabulary"
+)
+
+// TranslatableFields lists, per entity type, the fields that accept translations.
+var TranslatableFields = map[string][]string{
+	EntityCourse:     {"title", "description"},
+	EntityUnit:       {"title", "description"},
+	EntityLesson:     {"title", "description"},
+	EntityExercise:   {"prompt", "explanation"},
+	EntityVocabulary: {"translation", "example"},
+}
+
+type ContentTranslation struct {
+	model.BaseModel
+	EntityType   string    `json:"entity_type" gorm:"not null;size:20"`
+	EntityID     uuid.UUID `json:"entity_id" gorm:"not null;type:uuid"`
+	LanguageCode string    `json:"language_code" gorm:"not null;size:10"`
+	Field        string    `json:"field" gorm:"not null;size:50"`
+	Value        string    `json:"value" gorm:"not null"`
+}
+
+// Translations indexes translated values by entity, language and field.
+type Translations map[uuid.UUID]map[string]map[string]string
+
+func NewTranslations(rows []ContentTranslation) Translations {
+	translations := Translations{}
+	for _, row := range rows {
+		byLanguage, ok := translations[row.EntityID]
+		if !ok {
+			byLanguage = map[string]map[string]string{}
+			translations[row.EntityID] = byLanguage
+		}
+		fields, ok := byLanguage[row.LanguageCode]
+		if !ok {
+			fields = map[string]string{}
+			byLanguage[row.LanguageCode] = fields
+		}
+		fields[row.Field] = row.Value
+	}
+	return translations
+}
+
+// Resolve returns the value of field in the first language of chain that
+// has a translation, or fallback, the authored text, when none does.
+func (t Translations) Resolve(entityID uuid.UUID, field string, chain []string, fallback string) string {
+	byLanguage := t[entityID]
+	for _, language := range chain {
+		if value, ok := byLanguage[language][field]; ok && value != "" {
+			return value
+		}
+	}
+	return fallback
+}
+
+// LocalizeCourse rewrites the translatable fields of a course tree in place.
+func (t Translations) LocalizeCourse(course *Course, chain []string) {
+	course.Title = t.Resolve(course.ID, "title", chain, course.Title)
+	course.Description = t.Resolve(course.ID, "description", chain, course.Description)
+	for i := range course.Units {
+		unit := &course.Units[i]
+		unit.Title = t.Resolve(unit.ID, "title", chain, unit.Title)
+		unit.Description = t.Resolve(unit.ID, "description", chain, unit.Description)
+		for j := range unit.Lessons {
+			t.LocalizeLesson(&unit.Lessons[j], chain)
+		}
+	}
+}
+
+func (t Translations) LocalizeLesson(lesson *Lesson, chain []string) {
+	lesson.Title = t.Resolve(lesson.ID, "title", chain, lesson.Title)
+	lesson.Description = t.Resolve(lesson.ID, "description", chain, lesson.Description)
+	for i := range lesson.Exercises {
+		exercise := &lesson.Exercises[i]
+		exercise.Prompt = t.Resolve(exercise.ID, "prompt", chain, exercise.Prompt)
+		exercise.Explanation = t.Resolve(exercise.ID, "explanation", chain, exercise.Explanation)
+	}
+}
+
+// LocalizeLessonSnapshot localizes a pinned lesson revision. Snapshot
+// exercises carry no IDs, so exerciseIDs maps their keys to live exercises.
+func (t Translations) LocalizeLessonSnapshot(lessonID uuid.UUID, snapshot *LessonSnapshot, exerciseIDs map[string]uuid.UUID, chain []string) {
+	snapshot.Title = t.Resolve(lessonID, "title", chain, snapshot.Title)
+	snapshot.Description = t.Resolve(lessonID, "description", chain, snapshot.Description)
+	for i := range snapshot.Exercises {
+		exercise := &snapshot.Exercises[i]
+		id, ok := exerciseIDs[exercise.Key]
+		if !ok {
+			continue
+		}
+		exercise.Prompt = t.Resolve(id, "prompt", chain, exercise.Prompt)
+		exercise.Explanation = t.Resolve(id, "explanation", chain, exercise.Explanation)
+	}
+}
+
+// CourseEntityIDs returns the IDs of a course tree that may carry translations.
+func CourseEntityIDs(course *Course) []uuid.UUID {
+	ids := []uuid.UUID{course.ID}
+	for _, unit := range course.Units {
+		ids = append(ids, unit.ID)
+		for _, lesson := range unit.Lessons {
+			ids = append(ids, lesson.ID)
+			for _, exercise := range lesson.Exercises {
+				ids = append(ids, exercise.ID)
+			}
+		}
+	}
+	return ids
+}
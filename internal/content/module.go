@@ -0,0 +1,103 @@
+package content
+
+import (
+	"context"
+	"s29-be/internal/content/adapters/http"
+	"s29-be/internal/content/adapters/repository"
+	"s29-be/internal/content/application"
+	userDomain "s29-be/internal/user/domain"
+	svcContext "s29-be/pkg/context"
+	"s29-be/pkg/lifecycle"
+	"s29-be/pkg/middleware"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const scheduledPublishInterval = time.Minute
+
+type ContentModule struct {
+	Repository         *repository.ContentRepository
+	Service            *application.ContentService
+	PackageService     *application.PackageService
+	RevisionService    *application.RevisionService
+	TranslationService *application.TranslationService
+	SearchService      *application.SearchService
+	Handler            *http.ContentHandler
+	PackageHandler     *http.PackageHandler
+	RevisionHandler    *http.RevisionHandler
+	TranslationHandler *http.TranslationHandler
+	SearchHandler      *http.SearchHandler
+	AuthMiddleware     *middleware.AuthMiddleware
+}
+
+func NewContentModule(serviceContext *svcContext.ServiceContext) *ContentModule {
+	contentRepo := repository.NewContentRepository(serviceContext.GetDB())
+	contentCache := serviceContext.GetCache()
+	contentService := application.NewContentService(contentRepo, contentCache)
+	contentHandler := http.NewContentHandler(contentService)
+	packageService := application.NewPackageService(contentRepo, contentCache)
+	packageHandler := http.NewPackageHandler(packageService)
+	revisionService := application.NewRevisionService(contentRepo, contentCache)
+	revisionHandler := http.NewRevisionHandler(revisionService)
+	translationService := application.NewTranslationService(contentRepo, contentCache)
+	translationHandler := http.NewTranslationHandler(translationService)
+	searchService := application.NewSearchService(contentRepo)
+	searchHandler := http.NewSearchHandler(searchService)
+
+	// Publishes revisions scheduled for a later time
+	serviceContext.GetLifecycle().Append(lifecycle.Background("content-publisher", func(ctx context.Context) {
+		revisionService.RunScheduledPublisher(ctx, scheduledPublishInterval)
+	}))
+
+	return &ContentModule{
+		Repository:         contentRepo,
+		Service:            contentService,
+		PackageService:     packageService,
+		RevisionService:    revisionService,
+		TranslationService: translationService,
+		SearchService:      searchService,
+		Handler:            contentHandler,
+		PackageHandler:     packageHandler,
+		RevisionHandler:    revisionHandler,
+		TranslationHandler: translationHandler,
+		SearchHandler:      searchHandler,
+		AuthMiddleware:     serviceContext.GetAuthMiddleware(),
+	}
+}
+
+func (m *ContentModule) RegisterRoutes(router fiber.Router) {
+	courses := router.Group("courses")
+	{
+		courses.Get("/", m.Handler.ListCourses)
+		courses.Get("/:courseId", m.Handler.GetCourse)
+	}
+
+	router.Get("search", m.SearchHandler.Search)
+
+	editors := m.AuthMiddleware.RequireRole(userDomain.RoleEditor, userDomain.RoleAdmin)
+
+	admin := router.Group("admin/content")
+	admin.Use(m.AuthMiddleware.RequireAuth(), m.AuthMiddleware.RequireRole(userDomain.RoleEditor, userDomain.RoleReviewer, userDomain.RoleAdmin))
+	{
+		admin.Post("/import", editors, m.PackageHandler.Import)
+		admin.Get("/courses/:slug/export", editors, m.PackageHandler.Export)
+
+		// Per-transition role checks happen in the revision service
+		admin.Get("/revisions", m.RevisionHandler.ListRevisions)
+		admin.Post("/revisions", editors, m.RevisionHandler.CreateDraft)
+		admin.Get("/revisions/:revisionId", m.RevisionHandler.GetRevision)
+		admin.Put("/revisions/:revisionId", editors, m.RevisionHandler.UpdateDraft)
+		admin.Post("/revisions/:revisionId/submit", m.RevisionHandler.Submit)
+		admin.Post("/revisions/:revisionId/withdraw", m.RevisionHandler.Withdraw)
+		admin.Post("/revisions/:revisionId/approve", m.RevisionHandler.Approve)
+		admin.Post("/revisions/:revisionId/reject", m.RevisionHandler.Reject)
+		admin.Post("/revisions/:revisionId/publish", m.RevisionHandler.Publish)
+		admin.Post("/revisions/:revisionId/rollback", m.RevisionHandler.Rollback)
+
+		admin.Get("/translations", m.TranslationHandler.ListTranslations)
+		admin.Put("/translations", editors, m.TranslationHandler.SaveTranslations)
+
+		admin.Post("/search/reindex", editors, m.SearchHandler.Reindex)
+	}
+}
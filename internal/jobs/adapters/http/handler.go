@@ -0,0 +1,129 @@
+package http
+
+import (
+	"s29-be/internal/jobs/application"
+	"s29-be/internal/jobs/domain"
+	appError "s29-be/pkg/error"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type JobHandler struct {
+	jobService *application.JobService
+}
+
+func NewJobHandler(jobService *application.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// @Summary List Scheduled Jobs
+// @Description List recurring jobs with their schedule, pause state and latest run
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Success 200 {array} domain.JobView
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs [get]
+func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
+	jobs, err := h.jobService.ListJobs(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, jobs)
+	return nil
+}
+
+// @Summary List Job Runs
+// @Description List the latest runs of a job, newest first
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param name path string true "Job name"
+// @Param limit query int false "Number of runs (default 20, max 100)"
+// @Success 200 {array} domain.ScheduledJobRun
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/runs [get]
+func (h *JobHandler) ListRuns(c *fiber.Ctx) error {
+	var query domain.ListRunsQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		return err
+	}
+
+	runs, err := h.jobService.ListRuns(c.UserContext(), c.Params("name"), &query)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, runs)
+	return nil
+}
+
+// @Summary Pause Job
+// @Description Stop scheduled runs of a job on every replica until it is resumed
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param name path string true "Job name"
+// @Success 200 {object} domain.ScheduledJob
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/pause [post]
+func (h *JobHandler) Pause(c *fiber.Ctx) error {
+	return h.setPaused(c, true)
+}
+
+// @Summary Resume Job
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param name path string true "Job name"
+// @Success 200 {object} domain.ScheduledJob
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/resume [post]
+func (h *JobHandler) Resume(c *fiber.Ctx) error {
+	return h.setPaused(c, false)
+}
+
+func (h *JobHandler) setPaused(c *fiber.Ctx, paused bool) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	job, err := h.jobService.SetPaused(c.UserContext(), c.Params("name"), paused, userID)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, job)
+	return nil
+}
+
+// @Summary Trigger Job
+// @Description Run a job now, even when it is paused. Returns 409 while the job is running.
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param name path string true "Job name"
+// @Success 201 {object} scheduler.Run
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/jobs/{name}/trigger [post]
+func (h *JobHandler) Trigger(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	run, err := h.jobService.Trigger(c.Params("name"), userID)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseCreated(c, run)
+	return nil
+}
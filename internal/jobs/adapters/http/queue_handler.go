@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/url"
+	"s29-be/internal/jobs/application"
+	"s29-be/internal/jobs/domain"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type QueueHandler struct {
+	queueService *application.QueueService
+}
+
+func NewQueueHandler(queueService *application.QueueService) *QueueHandler {
+	return &QueueHandler{
+		queueService: queueService,
+	}
+}
+
+// queueName decodes the queue name, which usually contains a colon and
+// may arrive percent-encoded.
+func queueName(c *fiber.Ctx) string {
+	name, err := url.PathUnescape(c.Params("queue"))
+	if err != nil {
+		return c.Params("queue")
+	}
+	return name
+}
+
+// @Summary List Queues
+// @Description Show pending, delayed, processing and dead-letter counts and the age of the oldest pending job for every worker queue
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Success 200 {array} cache.QueueStats
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/queues [get]
+func (h *QueueHandler) ListQueues(c *fiber.Ctx) error {
+	queues, err := h.queueService.ListQueues(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, queues)
+	return nil
+}
+
+// @Summary Get Queue
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param queue path string true "Queue name, e.g. jobs:default"
+// @Success 200 {object} cache.QueueStats
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/queues/{queue} [get]
+func (h *QueueHandler) GetQueue(c *fiber.Ctx) error {
+	stats, err := h.queueService.GetQueue(c.UserContext(), queueName(c))
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, stats)
+	return nil
+}
+
+// @Summary Purge Queue
+// @Description Drop every pending job of a queue. Jobs being processed are kept.
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param queue path string true "Queue name"
+// @Param delayed query bool false "Also drop delayed jobs and retries"
+// @Param dead_letters query bool false "Also drop dead letters"
+// @Success 200 {object} cache.PurgeResult
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/queues/{queue}/purge [post]
+func (h *QueueHandler) PurgeQueue(c *fiber.Ctx) error {
+	var query domain.PurgeQueueQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		return err
+	}
+
+	result, err := h.queueService.PurgeQueue(c.UserContext(), queueName(c), &query)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, result)
+	return nil
+}
+
+// @Summary List Dead Letters
+// @Description List jobs that failed permanently, newest first
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param queue path string true "Queue name"
+// @Param offset query int false "Dead letters to skip"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Success 200 {object} domain.DeadLettersResponse
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/queues/{queue}/dead-letters [get]
+func (h *QueueHandler) ListDeadLetters(c *fiber.Ctx) error {
+	var query domain.ListDeadLettersQuery
+	if err := validation.BindQuery(c, &query); err != nil {
+		return err
+	}
+
+	response, err := h.queueService.ListDeadLetters(c.UserContext(), queueName(c), &query)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, response)
+	return nil
+}
+
+// @Summary Requeue Dead Letter
+// @Description Put the original job back on its queue with a fresh attempt count
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param queue path string true "Queue name"
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} cache.DeadLetterEntry
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/queues/{queue}/dead-letters/{id}/requeue [post]
+func (h *QueueHandler) RequeueDeadLetter(c *fiber.Ctx) error {
+	entry, err := h.queueService.RequeueDeadLetter(c.UserContext(), queueName(c), c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, entry)
+	return nil
+}
+
+// @Summary Delete Dead Letter
+// @Tags Jobs Admin
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param queue path string true "Queue name"
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/admin/queues/{queue}/dead-letters/{id} [delete]
+func (h *QueueHandler) DeleteDeadLetter(c *fiber.Ctx) error {
+	if err := h.queueService.DeleteDeadLetter(c.UserContext(), queueName(c), c.Params("id")); err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, nil)
+	return nil
+}
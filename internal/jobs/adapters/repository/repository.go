@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"s29-be/internal/jobs/domain"
+	"s29-be/pkg/model"
+	"s29-be/pkg/scheduler"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobsRepository stores scheduled job state and implements scheduler.Store.
+type JobsRepository struct {
+	db *gorm.DB
+}
+
+func NewJobsRepository(db *gorm.DB) *JobsRepository {
+	return &JobsRepository{
+		db: db,
+	}
+}
+
+// WithContext returns a repository whose queries run under ctx, so they are
+// cancelled with the request and logged with its attributes.
+func (r *JobsRepository) WithContext(ctx context.Context) *JobsRepository {
+	return &JobsRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *JobsRepository) Transaction(fn func(txRepo *JobsRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&JobsRepository{db: tx})
+	})
+}
+
+// SyncJobs creates missing jobs and refreshes the schedule of existing ones
+// without touching their pause state.
+func (r *JobsRepository) SyncJobs(jobs []scheduler.JobInfo) error {
+	for _, job := range jobs {
+		err := r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"schedule", "updated_at"}),
+		}).Create(&domain.ScheduledJob{Name: job.Name, Schedule: job.Schedule}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *JobsRepository) IsPaused(name string) (bool, error) {
+	var job domain.ScheduledJob
+	if err := r.db.Select("paused").Where("name = ?", name).First(&job).Error; err != nil {
+		return false, err
+	}
+	return job.Paused, nil
+}
+
+// StartRun records a run unless its scheduled tick already has one.
+func (r *JobsRepository) StartRun(run *scheduler.Run) (bool, error) {
+	started := false
+	err := r.Transaction(func(repo *JobsRepository) error {
+		result := repo.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "job_name"}, {Name: "scheduled_for"}},
+			DoNothing: true,
+		}).Create(toRunModel(run))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		started = true
+		return repo.updateLastRun(run)
+	})
+	return started, err
+}
+
+func (r *JobsRepository) FinishRun(run *scheduler.Run) error {
+	return r.Transaction(func(repo *JobsRepository) error {
+		err := repo.db.Model(&domain.ScheduledJobRun{}).
+			Where("id = ?", run.ID).
+			Updates(map[string]interface{}{
+				"status":      run.Status,
+				"error":       nullableString(run.Error),
+				"finished_at": run.FinishedAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return repo.updateLastRun(run)
+	})
+}
+
+// updateLastRun is fenced: a run whose lock expired and was taken by a
+// newer run cannot overwrite the newer run's status.
+func (r *JobsRepository) updateLastRun(run *scheduler.Run) error {
+	return r.db.Model(&domain.ScheduledJob{}).
+		Where("name = ? AND last_fence <= ?", run.Job, run.Fence).
+		Updates(map[string]interface{}{
+			"last_run_id":      run.ID,
+			"last_status":      run.Status,
+			"last_error":       nullableString(run.Error),
+			"last_started_at":  run.StartedAt,
+			"last_finished_at": run.FinishedAt,
+			"last_fence":       run.Fence,
+		}).Error
+}
+
+func (r *JobsRepository) ListJobs(names []string) ([]domain.ScheduledJob, error) {
+	var jobs []domain.ScheduledJob
+	if len(names) == 0 {
+		return jobs, nil
+	}
+	if err := r.db.Where("name IN ?", names).Order("name ASC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *JobsRepository) FindJob(name string) (*domain.ScheduledJob, error) {
+	var job domain.ScheduledJob
+	if err := r.db.Where("name = ?", name).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *JobsRepository) UpdatePause(job *domain.ScheduledJob) error {
+	return r.db.Model(job).Updates(map[string]interface{}{
+		"paused":    job.Paused,
+		"paused_at": job.PausedAt,
+		"paused_by": job.PausedBy,
+	}).Error
+}
+
+func (r *JobsRepository) ListRuns(name string, limit int) ([]domain.ScheduledJobRun, error) {
+	var runs []domain.ScheduledJobRun
+	err := r.db.
+		Where("job_name = ?", name).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func toRunModel(run *scheduler.Run) *domain.ScheduledJobRun {
+	now := time.Now().UTC()
+	return &domain.ScheduledJobRun{
+		BaseModel:    model.BaseModel{ID: run.ID, CreatedAt: now, UpdatedAt: now},
+		JobName:      run.Job,
+		TriggerType:  run.Trigger,
+		ScheduledFor: run.ScheduledFor,
+		TriggeredBy:  run.TriggeredBy,
+		Fence:        run.Fence,
+		Instance:     run.Instance,
+		Status:       run.Status,
+		Error:        nullableString(run.Error),
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+	}
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"s29-be/internal/jobs/domain"
+	"s29-be/pkg/cache"
+	appError "s29-be/pkg/error"
+	"slices"
+)
+
+const (
+	defaultDeadLetterLimit = 50
+	maxDeadLetterLimit     = 200
+)
+
+// QueueService reports on the reliable queues consumed by the worker runtime
+// and manages their dead letters. Only configured queues are reachable.
+type QueueService struct {
+	cacheClient *cache.Client
+	queueNames  []string
+}
+
+func NewQueueService(cacheClient *cache.Client, queueNames []string) *QueueService {
+	return &QueueService{
+		cacheClient: cacheClient,
+		queueNames:  queueNames,
+	}
+}
+
+func (s *QueueService) ListQueues(ctx context.Context) ([]cache.QueueStats, error) {
+	queues := make([]cache.QueueStats, 0, len(s.queueNames))
+	for _, name := range s.queueNames {
+		stats, err := s.cacheClient.QueueStats(ctx, name)
+		if err != nil {
+			return nil, appError.NewInternalError(err, "failed to read queue stats")
+		}
+		queues = append(queues, *stats)
+	}
+	return queues, nil
+}
+
+func (s *QueueService) GetQueue(ctx context.Context, name string) (*cache.QueueStats, error) {
+	if err := s.checkQueue(name); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.cacheClient.QueueStats(ctx, name)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to read queue stats")
+	}
+	return stats, nil
+}
+
+func (s *QueueService) ListDeadLetters(ctx context.Context, name string, query *domain.ListDeadLettersQuery) (*domain.DeadLettersResponse, error) {
+	if err := s.checkQueue(name); err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultDeadLetterLimit
+	} else if limit > maxDeadLetterLimit {
+		limit = maxDeadLetterLimit
+	}
+	offset := max(query.Offset, 0)
+
+	entries, total, err := s.cacheClient.ListDeadLetters(ctx, name, offset, limit)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to list dead letters")
+	}
+	return &domain.DeadLettersResponse{Total: total, DeadLetters: entries}, nil
+}
+
+// RequeueDeadLetter puts the job of a dead letter back at the end of its queue.
+func (s *QueueService) RequeueDeadLetter(ctx context.Context, name, id string) (*cache.DeadLetterEntry, error) {
+	if err := s.checkQueue(name); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.cacheClient.RequeueDeadLetter(ctx, name, id)
+	if err != nil {
+		return nil, deadLetterError(err, "failed to requeue dead letter")
+	}
+	return entry, nil
+}
+
+func (s *QueueService) DeleteDeadLetter(ctx context.Context, name, id string) error {
+	if err := s.checkQueue(name); err != nil {
+		return err
+	}
+
+	if err := s.cacheClient.DeleteDeadLetter(ctx, name, id); err != nil {
+		return deadLetterError(err, "failed to delete dead letter")
+	}
+	return nil
+}
+
+func (s *QueueService) PurgeQueue(ctx context.Context, name string, query *domain.PurgeQueueQuery) (*cache.PurgeResult, error) {
+	if err := s.checkQueue(name); err != nil {
+		return nil, err
+	}
+
+	result, err := s.cacheClient.PurgeQueue(ctx, name, query.Delayed, query.DeadLetters)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to purge queue")
+	}
+	return result, nil
+}
+
+func (s *QueueService) checkQueue(name string) error {
+	if !slices.Contains(s.queueNames, name) {
+		return appError.NewNotFoundError(nil, "queue not found")
+	}
+	return nil
+}
+
+func deadLetterError(err error, message string) error {
+	if errors.Is(err, cache.ErrDeadLetterNotFound) {
+		return appError.NewNotFoundError(err, "dead letter not found")
+	}
+	return appError.NewInternalError(err, message)
+}
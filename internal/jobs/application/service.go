@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"s29-be/internal/jobs/adapters/repository"
+	"s29-be/internal/jobs/domain"
+	appError "s29-be/pkg/error"
+	"s29-be/pkg/scheduler"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultRunLimit = 20
+	maxRunLimit     = 100
+)
+
+// JobService lets admins inspect, pause and trigger scheduled jobs.
+type JobService struct {
+	jobsRepo  *repository.JobsRepository
+	scheduler *scheduler.Scheduler
+}
+
+func NewJobService(jobsRepo *repository.JobsRepository, scheduler *scheduler.Scheduler) *JobService {
+	return &JobService{
+		jobsRepo:  jobsRepo,
+		scheduler: scheduler,
+	}
+}
+
+// ListJobs returns every job registered on this replica with its shared state.
+func (s *JobService) ListJobs(ctx context.Context) ([]domain.JobView, error) {
+	registered := s.scheduler.Jobs()
+	names := make([]string, 0, len(registered))
+	for _, job := range registered {
+		names = append(names, job.Name)
+	}
+
+	stored, err := s.jobsRepo.WithContext(ctx).ListJobs(names)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to list jobs")
+	}
+	byName := make(map[string]domain.ScheduledJob, len(stored))
+	for _, job := range stored {
+		byName[job.Name] = job
+	}
+
+	views := make([]domain.JobView, 0, len(registered))
+	for _, job := range registered {
+		state, ok := byName[job.Name]
+		if !ok {
+			state = domain.ScheduledJob{Name: job.Name}
+		}
+		// This replica's configuration wins over whichever replica synced last
+		state.Schedule = job.Schedule
+		views = append(views, domain.JobView{ScheduledJob: state, NextRunAt: job.NextRunAt})
+	}
+	return views, nil
+}
+
+func (s *JobService) ListRuns(ctx context.Context, name string, query *domain.ListRunsQuery) ([]domain.ScheduledJobRun, error) {
+	if _, err := s.findJob(ctx, name); err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultRunLimit
+	} else if limit > maxRunLimit {
+		limit = maxRunLimit
+	}
+
+	runs, err := s.jobsRepo.WithContext(ctx).ListRuns(name, limit)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to list job runs")
+	}
+	return runs, nil
+}
+
+// SetPaused pauses or resumes the scheduled runs of a job on every replica.
+// Manual triggers still work while a job is paused.
+func (s *JobService) SetPaused(ctx context.Context, name string, paused bool, userID uuid.UUID) (*domain.ScheduledJob, error) {
+	job, err := s.findJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Paused = paused
+	job.PausedAt, job.PausedBy = nil, nil
+	if paused {
+		now := time.Now().UTC()
+		job.PausedAt, job.PausedBy = &now, &userID
+	}
+	if err := s.jobsRepo.WithContext(ctx).UpdatePause(job); err != nil {
+		return nil, appError.NewInternalError(err, "failed to update job")
+	}
+	return job, nil
+}
+
+// Trigger starts a run of the job now on this replica.
+func (s *JobService) Trigger(name string, userID uuid.UUID) (*scheduler.Run, error) {
+	run, err := s.scheduler.Trigger(name, &userID)
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		return nil, appError.NewNotFoundError(err, "job not found")
+	case errors.Is(err, scheduler.ErrJobRunning):
+		return nil, appError.NewConflictError(err, "job is already running")
+	case errors.Is(err, scheduler.ErrNotStarted):
+		return nil, appError.NewConflictError(err, "scheduler is not running on this replica")
+	case err != nil:
+		return nil, appError.NewInternalError(err, "failed to trigger job")
+	}
+	return run, nil
+}
+
+func (s *JobService) findJob(ctx context.Context, name string) (*domain.ScheduledJob, error) {
+	job, err := s.jobsRepo.WithContext(ctx).FindJob(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appError.NewNotFoundError(err, "job not found")
+		}
+		return nil, appError.NewInternalError(err, "failed to load job")
+	}
+	return job, nil
+}
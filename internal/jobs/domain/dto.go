@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"s29-be/pkg/cache"
+	"time"
+)
+
+// JobView combines a job's schedule on this replica with its shared state.
+type JobView struct {
+	ScheduledJob
+	// NextRunAt is nil when the job is switched off by configuration
+	NextRunAt *time.Time `json:"next_run_at"`
+}
+
+type ListRunsQuery struct {
+	Limit int `query:"limit" validate:"min=0"`
+}
+
+type ListDeadLettersQuery struct {
+	Offset int `query:"offset" validate:"min=0"`
+	Limit  int `query:"limit" validate:"min=0"`
+}
+
+type DeadLettersResponse struct {
+	Total       int64                   `json:"total"`
+	DeadLetters []cache.DeadLetterEntry `json:"dead_letters"`
+}
+
+// PurgeQueueQuery selects what besides pending jobs a purge removes.
+type PurgeQueueQuery struct {
+	Delayed     bool `query:"delayed"`
+	DeadLetters bool `query:"dead_letters"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"s29-be/pkg/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledJob is the shared state of a recurring job: whether it is paused
+// and how its latest run went.
+type ScheduledJob struct {
+	Name           string     `json:"name" gorm:"primaryKey;size:100"`
+	Schedule       string     `json:"schedule" gorm:"not null;size:100"`
+	Paused         bool       `json:"paused" gorm:"not null;default:false"`
+	PausedAt       *time.Time `json:"paused_at"`
+	PausedBy       *uuid.UUID `json:"paused_by" gorm:"type:uuid"`
+	LastRunID      *uuid.UUID `json:"last_run_id" gorm:"type:uuid"`
+	LastStatus     *string    `json:"last_status" gorm:"size:20"`
+	LastError      *string    `json:"last_error"`
+	LastStartedAt  *time.Time `json:"last_started_at"`
+	LastFinishedAt *time.Time `json:"last_finished_at"`
+	// LastFence is the fencing token of the run that last wrote the status
+	LastFence int64     `json:"-" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type ScheduledJobRun struct {
+	model.BaseModel
+	JobName      string     `json:"job_name" gorm:"not null;size:100"`
+	TriggerType  string     `json:"trigger_type" gorm:"not null;size:20"`
+	ScheduledFor *time.Time `json:"scheduled_for"`
+	TriggeredBy  *uuid.UUID `json:"triggered_by" gorm:"type:uuid"`
+	Fence        int64      `json:"fence" gorm:"not null"`
+	Instance     string     `json:"instance" gorm:"not null;size:255"`
+	Status       string     `json:"status" gorm:"not null;size:20"`
+	Error        *string    `json:"error"`
+	StartedAt    time.Time  `json:"started_at" gorm:"not null"`
+	FinishedAt   *time.Time `json:"finished_at"`
+}
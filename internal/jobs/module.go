@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"s29-be/internal/jobs/adapters/http"
+	"s29-be/internal/jobs/adapters/repository"
+	"s29-be/internal/jobs/application"
+	userDomain "s29-be/internal/user/domain"
+	svcContext "s29-be/pkg/context"
+	"s29-be/pkg/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JobsModule struct {
+	Repository     *repository.JobsRepository
+	Service        *application.JobService
+	QueueService   *application.QueueService
+	Handler        *http.JobHandler
+	QueueHandler   *http.QueueHandler
+	AuthMiddleware *middleware.AuthMiddleware
+}
+
+// NewJobsModule makes Postgres the scheduler's store, so it must be created
+// before the scheduler starts.
+func NewJobsModule(serviceContext *svcContext.ServiceContext) *JobsModule {
+	jobsRepo := repository.NewJobsRepository(serviceContext.GetDB())
+	jobScheduler := serviceContext.GetScheduler()
+	jobScheduler.SetStore(jobsRepo)
+	jobService := application.NewJobService(jobsRepo, jobScheduler)
+	jobHandler := http.NewJobHandler(jobService)
+	queueService := application.NewQueueService(serviceContext.GetCacheClient(), serviceContext.GetWorker().QueueNames())
+	queueHandler := http.NewQueueHandler(queueService)
+
+	return &JobsModule{
+		Repository:     jobsRepo,
+		Service:        jobService,
+		QueueService:   queueService,
+		Handler:        jobHandler,
+		QueueHandler:   queueHandler,
+		AuthMiddleware: serviceContext.GetAuthMiddleware(),
+	}
+}
+
+func (m *JobsModule) RegisterRoutes(router fiber.Router) {
+	admin := router.Group("admin/jobs")
+	admin.Use(m.AuthMiddleware.RequireAuth(), m.AuthMiddleware.RequireRole(userDomain.RoleAdmin))
+	{
+		admin.Get("/", m.Handler.ListJobs)
+		admin.Get("/:name/runs", m.Handler.ListRuns)
+		admin.Post("/:name/pause", m.Handler.Pause)
+		admin.Post("/:name/resume", m.Handler.Resume)
+		admin.Post("/:name/trigger", m.Handler.Trigger)
+	}
+
+	queues := router.Group("admin/queues")
+	queues.Use(m.AuthMiddleware.RequireAuth(), m.AuthMiddleware.RequireRole(userDomain.RoleAdmin))
+	{
+		queues.Get("/", m.QueueHandler.ListQueues)
+		queues.Get("/:queue", m.QueueHandler.GetQueue)
+		queues.Post("/:queue/purge", m.QueueHandler.PurgeQueue)
+		queues.Get("/:queue/dead-letters", m.QueueHandler.ListDeadLetters)
+		queues.Post("/:queue/dead-letters/:id/requeue", m.QueueHandler.RequeueDeadLetter)
+		queues.Delete("/:queue/dead-letters/:id", m.QueueHandler.DeleteDeadLetter)
+	}
+}
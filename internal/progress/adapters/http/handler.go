@@ -0,0 +1,239 @@
+package http
+
+import (
+	"s29-be/internal/progress/application"
+	"s29-be/internal/progress/domain"
+	appError "s29-be/pkg/error"
+	"s29-be/pkg/i18n"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ProgressHandler struct {
+	progressService *application.ProgressService
+}
+
+func NewProgressHandler(progressService *application.ProgressService) *ProgressHandler {
+	return &ProgressHandler{
+		progressService: progressService,
+	}
+}
+
+// @Summary Progress Summary
+// @Description Get the learner's progress across all enrolled courses for the home screen
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Success 200 {object} domain.ProgressSummary
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/progress/summary [get]
+func (h *ProgressHandler) GetSummary(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	summary, err := h.progressService.GetSummary(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, summary)
+	return nil
+}
+
+// @Summary Enroll In Course
+// @Description Start a course and unlock its first unit
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param courseId path string true "Course ID"
+// @Success 200 {object} domain.CourseProgressView
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/progress/courses/{courseId}/enroll [post]
+func (h *ProgressHandler) EnrollInCourse(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	courseID, err := uuid.Parse(c.Params("courseId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid course ID")
+	}
+
+	progress, err := h.progressService.EnrollInCourse(c.UserContext(), userID, courseID)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, progress)
+	return nil
+}
+
+// @Summary Get Course Progress
+// @Description Get the learner's unit and lesson states for a course
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param courseId path string true "Course ID"
+// @Success 200 {object} domain.CourseProgressView
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/progress/courses/{courseId} [get]
+func (h *ProgressHandler) GetCourseProgress(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	courseID, err := uuid.Parse(c.Params("courseId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid course ID")
+	}
+
+	progress, err := h.progressService.GetCourseProgress(c.UserContext(), userID, courseID)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, progress)
+	return nil
+}
+
+// @Summary Apply Placement
+// @Description Skip ahead in a course based on a placement test score or an explicit unit
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param courseId path string true "Course ID"
+// @Param placementRequest body domain.PlacementRequest true "Placement Request"
+// @Success 200 {object} domain.CourseProgressView
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/progress/courses/{courseId}/placement [post]
+func (h *ProgressHandler) ApplyPlacement(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	courseID, err := uuid.Parse(c.Params("courseId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid course ID")
+	}
+
+	var request domain.PlacementRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	progress, err := h.progressService.ApplyPlacement(c.UserContext(), userID, courseID, &request)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, progress)
+	return nil
+}
+
+// @Summary Start Lesson
+// @Description Mark an unlocked lesson as in progress and open a session pinned to the published lesson revision
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param lessonId path string true "Lesson ID"
+// @Success 200 {object} domain.LessonSessionView
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/progress/lessons/{lessonId}/start [post]
+func (h *ProgressHandler) StartLesson(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	lessonID, err := uuid.Parse(c.Params("lessonId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid lesson ID")
+	}
+
+	progress, err := h.progressService.StartLesson(c.UserContext(), userID, lessonID, i18n.Language(c))
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, progress)
+	return nil
+}
+
+// @Summary Get Lesson Session
+// @Description Get a lesson session with the exercises of the revision it started on
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} domain.LessonSessionView
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/progress/sessions/{sessionId} [get]
+func (h *ProgressHandler) GetSession(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	sessionID, err := uuid.Parse(c.Params("sessionId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid session ID")
+	}
+
+	session, err := h.progressService.GetSession(c.UserContext(), userID, sessionID, i18n.Language(c))
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, session)
+	return nil
+}
+
+// @Summary Complete Lesson
+// @Description Record a lesson attempt, award crowns and XP and unlock the next content
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param lessonId path string true "Lesson ID"
+// @Param completeLessonRequest body domain.CompleteLessonRequest true "Complete Lesson Request"
+// @Success 200 {object} domain.CompleteLessonResponse
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/progress/lessons/{lessonId}/complete [post]
+func (h *ProgressHandler) CompleteLesson(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return appError.NewUnauthorizedError(nil, "")
+	}
+
+	lessonID, err := uuid.Parse(c.Params("lessonId"))
+	if err != nil {
+		return appError.NewBadRequestError(nil, "Invalid lesson ID")
+	}
+
+	var request domain.CompleteLessonRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	response, err := h.progressService.CompleteLesson(c.UserContext(), userID, lessonID, &request)
+	if err != nil {
+		return err
+	}
+
+	jsonResponse.ResponseOK(c, response)
+	return nil
+}
@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	contentDomain "s29-be/internal/content/domain"
+	"s29-be/internal/progress/domain"
+	"s29-be/pkg/events"
+	"s29-be/pkg/outbox"
+
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProgressRepository struct {
+	db *gorm.DB
+}
+
+func NewProgressRepository(db *gorm.DB) *ProgressRepository {
+	return &ProgressRepository{
+		db: db,
+	}
+}
+
+// WithContext returns a repository whose queries run under ctx, so they are
+// cancelled with the request and logged with its attributes.
+func (r *ProgressRepository) WithContext(ctx context.Context) *ProgressRepository {
+	return &ProgressRepository{db: r.db.WithContext(ctx)}
+}
+
+// Transaction runs fn against a repository bound to a single database transaction.
+func (r *ProgressRepository) Transaction(fn func(txRepo *ProgressRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&ProgressRepository{db: tx})
+	})
+}
+
+func (r *ProgressRepository) FindCourseTree(courseID uuid.UUID) (*contentDomain.Course, error) {
+	var course contentDomain.Course
+	err := r.db.
+		Preload("Units", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Units.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ?", courseID).
+		First(&course).Error
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (r *ProgressRepository) FindCoursesByIDs(courseIDs []uuid.UUID) ([]contentDomain.Course, error) {
+	var courses []contentDomain.Course
+	err := r.db.Where("id IN ?", courseIDs).Find(&courses).Error
+	if err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+func (r *ProgressRepository) FindLessonByID(lessonID uuid.UUID) (*contentDomain.Lesson, error) {
+	var lesson contentDomain.Lesson
+	err := r.db.Where("id = ?", lessonID).First(&lesson).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (r *ProgressRepository) FindUnitByID(unitID uuid.UUID) (*contentDomain.Unit, error) {
+	var unit contentDomain.Unit
+	err := r.db.Where("id = ?", unitID).First(&unit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// CountLessonsByCourse returns the number of lessons in each of the given courses.
+func (r *ProgressRepository) CountLessonsByCourse(courseIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		CourseID uuid.UUID
+		Total    int
+	}
+	err := r.db.Table("lessons").
+		Select("units.course_id AS course_id, COUNT(lessons.id) AS total").
+		Joins("JOIN units ON units.id = lessons.unit_id").
+		Where("units.course_id IN ?", courseIDs).
+		Group("units.course_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.CourseID] = row.Total
+	}
+	return counts, nil
+}
+
+// SumCrownsByCourse returns the total lesson crown levels a user holds per course.
+func (r *ProgressRepository) SumCrownsByCourse(userID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		CourseID uuid.UUID
+		Crowns   int
+	}
+	err := r.db.Table("user_lesson_progress").
+		Select("units.course_id AS course_id, COALESCE(SUM(user_lesson_progress.crown_level), 0) AS crowns").
+		Joins("JOIN units ON units.id = user_lesson_progress.unit_id").
+		Where("user_lesson_progress.user_id = ?", userID).
+		Group("units.course_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	crowns := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		crowns[row.CourseID] = row.Crowns
+	}
+	return crowns, nil
+}
+
+func (r *ProgressRepository) FindCourseProgress(userID, courseID uuid.UUID) (*domain.UserCourseProgress, error) {
+	var progress domain.UserCourseProgress
+	err := r.db.Where("user_id = ? AND course_id = ?", userID, courseID).First(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// FindCourseProgressForUpdate loads a user's course progress and locks the
+// row, serialising concurrent updates to its counters.
+func (r *ProgressRepository) FindCourseProgressForUpdate(userID, courseID uuid.UUID) (*domain.UserCourseProgress, error) {
+	var progress domain.UserCourseProgress
+	err := r.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		First(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (r *ProgressRepository) ListCourseProgress(userID uuid.UUID) ([]domain.UserCourseProgress, error) {
+	var progress []domain.UserCourseProgress
+	err := r.db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (r *ProgressRepository) CreateCourseProgress(progress *domain.UserCourseProgress) error {
+	return r.db.Create(progress).Error
+}
+
+func (r *ProgressRepository) UpdateCourseProgress(progress *domain.UserCourseProgress) error {
+	return r.db.Save(progress).Error
+}
+
+func (r *ProgressRepository) FindUnitProgress(userID, unitID uuid.UUID) (*domain.UserUnitProgress, error) {
+	var progress domain.UserUnitProgress
+	err := r.db.Where("user_id = ? AND unit_id = ?", userID, unitID).First(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (r *ProgressRepository) ListUnitProgress(userID, courseID uuid.UUID) ([]domain.UserUnitProgress, error) {
+	var progress []domain.UserUnitProgress
+	err := r.db.Where("user_id = ? AND course_id = ?", userID, courseID).Find(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (r *ProgressRepository) CreateUnitProgress(progress *domain.UserUnitProgress) error {
+	return r.db.Create(progress).Error
+}
+
+func (r *ProgressRepository) UpdateUnitProgress(progress *domain.UserUnitProgress) error {
+	return r.db.Save(progress).Error
+}
+
+func (r *ProgressRepository) FindLessonProgress(userID, lessonID uuid.UUID) (*domain.UserLessonProgress, error) {
+	var progress domain.UserLessonProgress
+	err := r.db.Where("user_id = ? AND lesson_id = ?", userID, lessonID).First(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// FindLessonProgressForUpdate loads a user's lesson progress and locks the
+// row, so concurrent attempts see each other's completion.
+func (r *ProgressRepository) FindLessonProgressForUpdate(userID, lessonID uuid.UUID) (*domain.UserLessonProgress, error) {
+	var progress domain.UserLessonProgress
+	err := r.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND lesson_id = ?", userID, lessonID).
+		First(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (r *ProgressRepository) ListLessonProgressByUnits(userID uuid.UUID, unitIDs []uuid.UUID) ([]domain.UserLessonProgress, error) {
+	var progress []domain.UserLessonProgress
+	err := r.db.Where("user_id = ? AND unit_id IN ?", userID, unitIDs).Find(&progress).Error
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (r *ProgressRepository) CreateLessonProgress(progress *domain.UserLessonProgress) error {
+	return r.db.Create(progress).Error
+}
+
+func (r *ProgressRepository) UpdateLessonProgress(progress *domain.UserLessonProgress) error {
+	return r.db.Save(progress).Error
+}
+
+// FindLessonForUpdate loads a lesson with its exercises and locks the lesson
+// row, serialising concurrent baseline revision creation.
+func (r *ProgressRepository) FindLessonForUpdate(lessonID uuid.UUID) (*contentDomain.Lesson, error) {
+	var lesson contentDomain.Lesson
+	err := r.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Exercises", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id = ?", lessonID).
+		First(&lesson).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (r *ProgressRepository) FindPublishedLessonRevision(lessonID uuid.UUID) (*contentDomain.ContentRevision, error) {
+	var revision contentDomain.ContentRevision
+	err := r.db.Where("entity_type = ? AND entity_id = ? AND state = ?", contentDomain.EntityLesson, lessonID, contentDomain.RevisionPublished).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *ProgressRepository) FindRevisionByID(revisionID uuid.UUID) (*contentDomain.ContentRevision, error) {
+	var revision contentDomain.ContentRevision
+	err := r.db.Where("id = ?", revisionID).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *ProgressRepository) NextLessonRevisionNumber(lessonID uuid.UUID) (int, error) {
+	var current int
+	err := r.db.Model(&contentDomain.ContentRevision{}).
+		Select("COALESCE(MAX(revision_number), 0)").
+		Where("entity_type = ? AND entity_id = ?", contentDomain.EntityLesson, lessonID).
+		Scan(&current).Error
+	if err != nil {
+		return 0, err
+	}
+	return current + 1, nil
+}
+
+func (r *ProgressRepository) CreateRevision(revision *contentDomain.ContentRevision) error {
+	return r.db.Create(revision).Error
+}
+
+func (r *ProgressRepository) FindSession(userID, sessionID uuid.UUID) (*domain.LessonSession, error) {
+	var session domain.LessonSession
+	err := r.db.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *ProgressRepository) CreateSession(session *domain.LessonSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *ProgressRepository) UpdateSession(session *domain.LessonSession) error {
+	return r.db.Save(session).Error
+}
+
+// AbandonActiveSessions closes any unfinished attempts of a lesson before a new one starts.
+func (r *ProgressRepository) AbandonActiveSessions(userID, lessonID uuid.UUID) error {
+	return r.db.Model(&domain.LessonSession{}).
+		Where("user_id = ? AND lesson_id = ? AND status = ?", userID, lessonID, domain.SessionActive).
+		Update("status", domain.SessionAbandoned).Error
+}
+
+// ListTranslations loads content translations for the given entities in any of languages.
+func (r *ProgressRepository) ListTranslations(entityIDs []uuid.UUID, languages []string) ([]contentDomain.ContentTranslation, error) {
+	var translations []contentDomain.ContentTranslation
+	err := r.db.
+		Where("entity_id IN ? AND language_code IN ?", entityIDs, languages).
+		Find(&translations).Error
+	if err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// ListExerciseIDs maps the exercise keys of a lesson to their IDs.
+func (r *ProgressRepository) ListExerciseIDs(lessonID uuid.UUID) (map[string]uuid.UUID, error) {
+	var exercises []contentDomain.Exercise
+	if err := r.db.Select("id", "key").Where("lesson_id = ?", lessonID).Find(&exercises).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]uuid.UUID, len(exercises))
+	for _, exercise := range exercises {
+		ids[exercise.Key] = exercise.ID
+	}
+	return ids, nil
+}
+
+// RecordPractice extends the user's streak on the first passed lesson of a
+// day, or restarts it at one when the previous lesson was before yesterday.
+func (r *ProgressRepository) RecordPractice(userID uuid.UUID, at time.Time) error {
+	today := domain.StreakDay(at)
+	return r.db.Exec(`
+		UPDATE users SET
+			streak_days = CASE
+				WHEN last_lesson_at >= ? THEN GREATEST(streak_days, 1)
+				WHEN last_lesson_at >= ? THEN streak_days + 1
+				ELSE 1
+			END,
+			last_lesson_at = ?
+		WHERE id = ?`, today, domain.StreakCutoff(at), at, userID).Error
+}
+
+// ResetLapsedStreaks zeroes the streak of every user whose last lesson was before cutoff.
+func (r *ProgressRepository) ResetLapsedStreaks(cutoff time.Time) (int64, error) {
+	result := r.db.Exec(`
+		UPDATE users SET streak_days = 0
+		WHERE streak_days > 0 AND (last_lesson_at IS NULL OR last_lesson_at < ?)`, cutoff)
+	return result.RowsAffected, result.Error
+}
+
+// AddOutboxEvent stores event for publication once the surrounding
+// transaction commits.
+func (r *ProgressRepository) AddOutboxEvent(userID uuid.UUID, event events.Event) error {
+	return outbox.Add(r.db, "user", userID, event)
+}
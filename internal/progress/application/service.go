@@ -0,0 +1,934 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	contentDomain "s29-be/internal/content/domain"
+	"s29-be/internal/progress/adapters/repository"
+	"s29-be/internal/progress/domain"
+	appError "s29-be/pkg/error"
+	"s29-be/pkg/events"
+	"s29-be/pkg/i18n"
+	"s29-be/pkg/metrics"
+	baseModel "s29-be/pkg/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ProgressService struct {
+	progressRepo *repository.ProgressRepository
+}
+
+func NewProgressService(progressRepo *repository.ProgressRepository) *ProgressService {
+	return &ProgressService{
+		progressRepo: progressRepo,
+	}
+}
+
+// EnrollInCourse starts tracking a course for the user and unlocks its first
+// unit. Enrolling twice is a no-op.
+func (s *ProgressService) EnrollInCourse(ctx context.Context, userID, courseID uuid.UUID) (*domain.CourseProgressView, error) {
+	err := s.progressRepo.WithContext(ctx).Transaction(func(repo *repository.ProgressRepository) error {
+		course, err := loadCourse(repo, courseID)
+		if err != nil {
+			return err
+		}
+		_, err = enroll(repo, userID, course)
+		return err
+	})
+	if err != nil {
+		return nil, toAppError(err, "failed to enroll in course")
+	}
+
+	return s.GetCourseProgress(ctx, userID, courseID)
+}
+
+func (s *ProgressService) GetCourseProgress(ctx context.Context, userID, courseID uuid.UUID) (*domain.CourseProgressView, error) {
+	repo := s.progressRepo.WithContext(ctx)
+
+	course, err := loadCourse(repo, courseID)
+	if err != nil {
+		return nil, toAppError(err, "failed to load course")
+	}
+
+	courseProgress, err := repo.FindCourseProgress(userID, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appError.NewNotFoundError(err, "not enrolled in course")
+		}
+		return nil, appError.NewInternalError(err, "failed to load course progress")
+	}
+
+	unitProgress, err := repo.ListUnitProgress(userID, courseID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load unit progress")
+	}
+
+	lessonProgress, err := repo.ListLessonProgressByUnits(userID, unitIDs(course))
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load lesson progress")
+	}
+
+	return buildCourseView(course, courseProgress, unitProgress, lessonProgress), nil
+}
+
+// GetSummary aggregates progress across every course the user is enrolled in
+// for the home screen.
+func (s *ProgressService) GetSummary(ctx context.Context, userID uuid.UUID) (*domain.ProgressSummary, error) {
+	repo := s.progressRepo.WithContext(ctx)
+
+	courseProgress, err := repo.ListCourseProgress(userID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load course progress")
+	}
+
+	summary := &domain.ProgressSummary{
+		Courses: make([]domain.CourseSummaryView, 0, len(courseProgress)),
+	}
+	if len(courseProgress) == 0 {
+		return summary, nil
+	}
+
+	courseIDs := make([]uuid.UUID, 0, len(courseProgress))
+	for _, progress := range courseProgress {
+		courseIDs = append(courseIDs, progress.CourseID)
+	}
+
+	courses, err := repo.FindCoursesByIDs(courseIDs)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load courses")
+	}
+	coursesByID := make(map[uuid.UUID]contentDomain.Course, len(courses))
+	for _, course := range courses {
+		coursesByID[course.ID] = course
+	}
+
+	lessonTotals, err := repo.CountLessonsByCourse(courseIDs)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to count lessons")
+	}
+
+	crowns, err := repo.SumCrownsByCourse(userID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to count crowns")
+	}
+
+	for _, progress := range courseProgress {
+		course := coursesByID[progress.CourseID]
+		total := lessonTotals[progress.CourseID]
+
+		percent := 0
+		if total > 0 {
+			percent = progress.LessonsCompleted * 100 / total
+		}
+
+		summary.TotalXP += progress.XPEarned
+		summary.TotalCrowns += crowns[progress.CourseID]
+		if progress.Status == domain.StatusCompleted {
+			summary.CoursesCompleted++
+		} else {
+			summary.CoursesInProgress++
+		}
+
+		summary.Courses = append(summary.Courses, domain.CourseSummaryView{
+			CourseID:         progress.CourseID,
+			Slug:             course.Slug,
+			Title:            course.Title,
+			Status:           progress.Status,
+			PercentComplete:  percent,
+			LessonsCompleted: progress.LessonsCompleted,
+			TotalLessons:     total,
+			Crowns:           crowns[progress.CourseID],
+			XPEarned:         progress.XPEarned,
+			CurrentUnitID:    progress.CurrentUnitID,
+			CurrentLessonID:  progress.CurrentLessonID,
+			LastActivityAt:   progress.UpdatedAt,
+		})
+	}
+
+	return summary, nil
+}
+
+// StartLesson marks an unlocked lesson, and its unit, as in progress and
+// opens a lesson session pinned to the lesson's published revision.
+func (s *ProgressService) StartLesson(ctx context.Context, userID, lessonID uuid.UUID, language string) (*domain.LessonSessionView, error) {
+	var view *domain.LessonSessionView
+	err := s.progressRepo.WithContext(ctx).Transaction(func(repo *repository.ProgressRepository) error {
+		lesson, unit, err := loadLesson(repo, lessonID)
+		if err != nil {
+			return err
+		}
+
+		courseProgress, lessonProgress, err := loadAccessibleLesson(repo, userID, unit.CourseID, lessonID)
+		if err != nil {
+			return err
+		}
+
+		if lessonProgress.Status == domain.StatusUnlocked {
+			lessonProgress.Status = domain.StatusInProgress
+			if err := repo.UpdateLessonProgress(lessonProgress); err != nil {
+				return err
+			}
+		}
+
+		unitProgress, err := repo.FindUnitProgress(userID, unit.ID)
+		if err != nil {
+			return err
+		}
+		if unitProgress.Status == domain.StatusUnlocked {
+			unitProgress.Status = domain.StatusInProgress
+			if err := repo.UpdateUnitProgress(unitProgress); err != nil {
+				return err
+			}
+		}
+
+		courseProgress.CurrentUnitID = &unit.ID
+		courseProgress.CurrentLessonID = &lesson.ID
+		if err := repo.UpdateCourseProgress(courseProgress); err != nil {
+			return err
+		}
+
+		revision, err := publishedLessonRevision(repo, lessonID)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.AbandonActiveSessions(userID, lessonID); err != nil {
+			return err
+		}
+
+		base, err := baseModel.NewBaseModel()
+		if err != nil {
+			return err
+		}
+		session := &domain.LessonSession{
+			BaseModel:  *base,
+			UserID:     userID,
+			LessonID:   lessonID,
+			RevisionID: revision.ID,
+			Status:     domain.SessionActive,
+		}
+		if err := repo.CreateSession(session); err != nil {
+			return err
+		}
+
+		view, err = buildSessionView(session, revision, *lesson, lessonProgress)
+		return err
+	})
+	if err != nil {
+		return nil, toAppError(err, "failed to start lesson")
+	}
+
+	if err := localizeSession(s.progressRepo.WithContext(ctx), view, language); err != nil {
+		return nil, appError.NewInternalError(err, "failed to load translations")
+	}
+	return view, nil
+}
+
+// GetSession returns a lesson session with the content of the revision it is pinned to.
+func (s *ProgressService) GetSession(ctx context.Context, userID, sessionID uuid.UUID, language string) (*domain.LessonSessionView, error) {
+	repo := s.progressRepo.WithContext(ctx)
+
+	session, err := repo.FindSession(userID, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appError.NewNotFoundError(err, "lesson session not found")
+		}
+		return nil, appError.NewInternalError(err, "failed to load lesson session")
+	}
+
+	revision, err := repo.FindRevisionByID(session.RevisionID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load lesson revision")
+	}
+
+	lesson, err := repo.FindLessonByID(session.LessonID)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to load lesson")
+	}
+
+	lessonProgress, err := repo.FindLessonProgress(userID, session.LessonID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appError.NewInternalError(err, "failed to load lesson progress")
+	}
+
+	view, err := buildSessionView(session, revision, *lesson, lessonProgress)
+	if err != nil {
+		return nil, appError.NewInternalError(err, "failed to decode lesson revision")
+	}
+
+	if err := localizeSession(repo, view, language); err != nil {
+		return nil, appError.NewInternalError(err, "failed to load translations")
+	}
+	return view, nil
+}
+
+// CompleteLesson records a lesson attempt. A passing score levels up the
+// lesson crown, awards XP and applies the unlocking rules: the next lesson in
+// the unit unlocks, and once every lesson of the unit reaches
+// UnitUnlockCrownLevel the unit completes and the next unit unlocks.
+func (s *ProgressService) CompleteLesson(ctx context.Context, userID, lessonID uuid.UUID, request *domain.CompleteLessonRequest) (*domain.CompleteLessonResponse, error) {
+	if request.Score < 0 || request.Score > 100 {
+		return nil, appError.NewBadRequestError(nil, "score must be between 0 and 100")
+	}
+
+	var response *domain.CompleteLessonResponse
+	err := s.progressRepo.WithContext(ctx).Transaction(func(repo *repository.ProgressRepository) error {
+		lesson, unit, err := loadLesson(repo, lessonID)
+		if err != nil {
+			return err
+		}
+
+		courseProgress, lessonProgress, err := loadAccessibleLesson(repo, userID, unit.CourseID, lessonID)
+		if err != nil {
+			return err
+		}
+
+		if request.SessionID != nil {
+			if err := finishSession(repo, userID, lessonID, *request.SessionID, request.Score); err != nil {
+				return err
+			}
+		}
+
+		lessonProgress.Attempts++
+		if request.Score > lessonProgress.BestScore {
+			lessonProgress.BestScore = request.Score
+		}
+
+		response = &domain.CompleteLessonResponse{
+			LessonID:   lesson.ID,
+			CrownLevel: lessonProgress.CrownLevel,
+		}
+
+		if request.Score < domain.PassingScore {
+			if lessonProgress.Status == domain.StatusUnlocked {
+				lessonProgress.Status = domain.StatusInProgress
+			}
+			if err := repo.UpdateLessonProgress(lessonProgress); err != nil {
+				return err
+			}
+			return addCompletionEvents(repo, userID, unit.CourseID, request.Score, response)
+		}
+
+		now := time.Now().UTC()
+		alreadyCompleted := lessonProgress.Status == domain.StatusCompleted
+		lessonProgress.Status = domain.StatusCompleted
+		lessonProgress.CrownLevel = domain.NextCrownLevel(lessonProgress.CrownLevel)
+		if lessonProgress.CompletedAt == nil {
+			lessonProgress.CompletedAt = &now
+		}
+		if err := repo.UpdateLessonProgress(lessonProgress); err != nil {
+			return err
+		}
+		if err := repo.RecordPractice(userID, now); err != nil {
+			return err
+		}
+
+		xp := domain.LessonXP(lesson.XPReward, alreadyCompleted)
+		courseProgress.XPEarned += int64(xp)
+		if !alreadyCompleted {
+			courseProgress.LessonsCompleted++
+		}
+
+		response.Passed = true
+		response.CrownLevel = lessonProgress.CrownLevel
+		response.XPAwarded = xp
+
+		course, err := loadCourse(repo, unit.CourseID)
+		if err != nil {
+			return err
+		}
+		unitIndex := indexOfUnit(course, unit.ID)
+		currentUnit := course.Units[unitIndex]
+
+		// Unlock the next lesson in this unit
+		lessonIndex := indexOfLesson(currentUnit, lesson.ID)
+		if lessonIndex+1 < len(currentUnit.Lessons) {
+			next := currentUnit.Lessons[lessonIndex+1]
+			if err := unlockLesson(repo, userID, next); err != nil {
+				return err
+			}
+			response.NextLessonID = &next.ID
+			courseProgress.CurrentLessonID = &next.ID
+		}
+
+		unitCompleted, err := refreshUnitProgress(repo, userID, currentUnit, now)
+		if err != nil {
+			return err
+		}
+		response.UnitCompleted = unitCompleted
+
+		if unitCompleted {
+			if unitIndex+1 < len(course.Units) {
+				nextUnit := course.Units[unitIndex+1]
+				if err := unlockUnit(repo, userID, course.ID, nextUnit); err != nil {
+					return err
+				}
+				response.NextUnitID = &nextUnit.ID
+				courseProgress.CurrentUnitID = &nextUnit.ID
+				courseProgress.CurrentLessonID = firstLessonID(nextUnit)
+			} else if courseProgress.Status != domain.StatusCompleted {
+				courseProgress.Status = domain.StatusCompleted
+				courseProgress.CompletedAt = &now
+			}
+		}
+		response.CourseComplete = courseProgress.Status == domain.StatusCompleted
+
+		if err := repo.UpdateCourseProgress(courseProgress); err != nil {
+			return err
+		}
+		return addCompletionEvents(repo, userID, unit.CourseID, request.Score, response)
+	})
+	if err != nil {
+		return nil, toAppError(err, "failed to complete lesson")
+	}
+
+	result := "failed"
+	if response.Passed {
+		result = "passed"
+	}
+	metrics.LessonsCompleted.WithLabelValues(result).Inc()
+	metrics.XPAwarded.Add(float64(response.XPAwarded))
+
+	return response, nil
+}
+
+// addCompletionEvents announces a recorded attempt through the outbox, so
+// the events are published exactly when the attempt commits.
+func addCompletionEvents(repo *repository.ProgressRepository, userID, courseID uuid.UUID, score int, response *domain.CompleteLessonResponse) error {
+	now := time.Now().UTC()
+	published := []events.Event{events.LessonCompleted{
+		UserID:      userID,
+		CourseID:    courseID,
+		LessonID:    response.LessonID,
+		Score:       score,
+		Passed:      response.Passed,
+		CrownLevel:  response.CrownLevel,
+		CompletedAt: now,
+	}}
+	if response.XPAwarded > 0 {
+		published = append(published, events.XPAwarded{
+			UserID:    userID,
+			CourseID:  courseID,
+			LessonID:  response.LessonID,
+			Amount:    response.XPAwarded,
+			AwardedAt: now,
+		})
+	}
+
+	for _, event := range published {
+		if err := repo.AddOutboxEvent(userID, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetLapsedStreaks zeroes the streaks that lapsed as of at. It is
+// idempotent, so a rerun of the same day changes nothing.
+func (s *ProgressService) ResetLapsedStreaks(ctx context.Context, at time.Time) (int64, error) {
+	reset, err := s.progressRepo.WithContext(ctx).ResetLapsedStreaks(domain.StreakCutoff(at))
+	if err != nil {
+		return 0, appError.NewInternalError(err, "failed to reset streaks")
+	}
+	return reset, nil
+}
+
+// ApplyPlacement skips the learner ahead to a unit chosen either explicitly
+// or from a placement test score. Every unit before it is marked completed
+// at UnitUnlockCrownLevel without awarding XP. Placement can only be taken
+// once per course and never locks content that is already unlocked.
+func (s *ProgressService) ApplyPlacement(ctx context.Context, userID, courseID uuid.UUID, request *domain.PlacementRequest) (*domain.CourseProgressView, error) {
+	if request.Score == nil && request.UnitID == nil {
+		return nil, appError.NewBadRequestError(nil, "either score or unit_id is required")
+	}
+
+	err := s.progressRepo.WithContext(ctx).Transaction(func(repo *repository.ProgressRepository) error {
+		course, err := loadCourse(repo, courseID)
+		if err != nil {
+			return err
+		}
+		if len(course.Units) == 0 {
+			return appError.NewBadRequestError(nil, "course has no units")
+		}
+
+		courseProgress, err := enroll(repo, userID, course)
+		if err != nil {
+			return err
+		}
+		if courseProgress.PlacementTestedAt != nil {
+			return appError.NewBadRequestError(nil, "placement has already been applied for this course")
+		}
+
+		targetIndex := 0
+		if request.UnitID != nil {
+			targetIndex = indexOfUnit(course, *request.UnitID)
+			if targetIndex < 0 {
+				return appError.NewBadRequestError(nil, "unit does not belong to course")
+			}
+		} else {
+			targetIndex = domain.PlacementUnitIndex(*request.Score, len(course.Units))
+		}
+
+		now := time.Now().UTC()
+		for _, unit := range course.Units[:targetIndex] {
+			completed, err := completeUnitForPlacement(repo, userID, course.ID, unit, now)
+			if err != nil {
+				return err
+			}
+			courseProgress.LessonsCompleted += completed
+		}
+
+		target := course.Units[targetIndex]
+		if err := unlockUnit(repo, userID, course.ID, target); err != nil {
+			return err
+		}
+
+		courseProgress.CurrentUnitID = &target.ID
+		courseProgress.CurrentLessonID = firstLessonID(target)
+		courseProgress.PlacementTestedAt = &now
+		return repo.UpdateCourseProgress(courseProgress)
+	})
+	if err != nil {
+		return nil, toAppError(err, "failed to apply placement")
+	}
+
+	return s.GetCourseProgress(ctx, userID, courseID)
+}
+
+func loadCourse(repo *repository.ProgressRepository, courseID uuid.UUID) (*contentDomain.Course, error) {
+	course, err := repo.FindCourseTree(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appError.NewNotFoundError(err, "course not found")
+		}
+		return nil, err
+	}
+	if !course.IsPublished {
+		return nil, appError.NewNotFoundError(nil, "course not found")
+	}
+	return course, nil
+}
+
+func loadLesson(repo *repository.ProgressRepository, lessonID uuid.UUID) (*contentDomain.Lesson, *contentDomain.Unit, error) {
+	lesson, err := repo.FindLessonByID(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, appError.NewNotFoundError(err, "lesson not found")
+		}
+		return nil, nil, err
+	}
+
+	unit, err := repo.FindUnitByID(lesson.UnitID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lesson, unit, nil
+}
+
+// loadAccessibleLesson returns the course and lesson progress, failing when
+// the user is not enrolled or the lesson is still locked. Both rows stay
+// locked until the transaction ends, course first, so concurrent attempts on
+// the same course apply their counters one after the other.
+func loadAccessibleLesson(repo *repository.ProgressRepository, userID, courseID, lessonID uuid.UUID) (*domain.UserCourseProgress, *domain.UserLessonProgress, error) {
+	courseProgress, err := repo.FindCourseProgressForUpdate(userID, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, appError.NewForbiddenError(err, "not enrolled in course")
+		}
+		return nil, nil, err
+	}
+
+	lessonProgress, err := repo.FindLessonProgressForUpdate(userID, lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, appError.NewForbiddenError(err, "lesson is locked")
+		}
+		return nil, nil, err
+	}
+	if !lessonProgress.Status.IsAccessible() {
+		return nil, nil, appError.NewForbiddenError(nil, "lesson is locked")
+	}
+
+	return courseProgress, lessonProgress, nil
+}
+
+// publishedLessonRevision returns the live revision of a lesson. Lessons
+// that have never gone through the revision workflow, or whose content was
+// changed by an import since, get a baseline revision of their current
+// content so sessions always have something to pin to.
+func publishedLessonRevision(repo *repository.ProgressRepository, lessonID uuid.UUID) (*contentDomain.ContentRevision, error) {
+	lesson, err := repo.FindLessonForUpdate(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := repo.FindPublishedLessonRevision(lessonID)
+	if err == nil {
+		return revision, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	snapshot, err := json.Marshal(contentDomain.NewLessonSnapshot(lesson))
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := repo.NextLessonRevisionNumber(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := baseModel.NewBaseModel()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	revision = &contentDomain.ContentRevision{
+		BaseModel:      *base,
+		EntityType:     contentDomain.EntityLesson,
+		EntityID:       lessonID,
+		RevisionNumber: number,
+		State:          contentDomain.RevisionPublished,
+		Snapshot:       snapshot,
+		PublishedAt:    &now,
+	}
+	if err := repo.CreateRevision(revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func finishSession(repo *repository.ProgressRepository, userID, lessonID, sessionID uuid.UUID, score int) error {
+	session, err := repo.FindSession(userID, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appError.NewNotFoundError(err, "lesson session not found")
+		}
+		return err
+	}
+	if session.LessonID != lessonID {
+		return appError.NewBadRequestError(nil, "lesson session belongs to another lesson")
+	}
+	if session.Status != domain.SessionActive {
+		return appError.NewBadRequestError(nil, "lesson session is no longer active")
+	}
+
+	now := time.Now().UTC()
+	session.Status = domain.SessionCompleted
+	session.Score = &score
+	session.CompletedAt = &now
+	return repo.UpdateSession(session)
+}
+
+// enroll returns the user's course progress, locked for the rest of the
+// transaction, creating it with the first unit unlocked on first enrolment.
+func enroll(repo *repository.ProgressRepository, userID uuid.UUID, course *contentDomain.Course) (*domain.UserCourseProgress, error) {
+	existing, err := repo.FindCourseProgressForUpdate(userID, course.ID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	base, err := baseModel.NewBaseModel()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &domain.UserCourseProgress{
+		BaseModel: *base,
+		UserID:    userID,
+		CourseID:  course.ID,
+		Status:    domain.StatusInProgress,
+	}
+
+	if len(course.Units) > 0 {
+		first := course.Units[0]
+		if err := unlockUnit(repo, userID, course.ID, first); err != nil {
+			return nil, err
+		}
+		progress.CurrentUnitID = &first.ID
+		progress.CurrentLessonID = firstLessonID(first)
+	}
+
+	if err := repo.CreateCourseProgress(progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// unlockUnit unlocks a unit and its first lesson unless they already have progress.
+func unlockUnit(repo *repository.ProgressRepository, userID, courseID uuid.UUID, unit contentDomain.Unit) error {
+	_, err := repo.FindUnitProgress(userID, unit.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		base, err := baseModel.NewBaseModel()
+		if err != nil {
+			return err
+		}
+		err = repo.CreateUnitProgress(&domain.UserUnitProgress{
+			BaseModel: *base,
+			UserID:    userID,
+			CourseID:  courseID,
+			UnitID:    unit.ID,
+			Status:    domain.StatusUnlocked,
+		})
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if len(unit.Lessons) == 0 {
+		return nil
+	}
+	return unlockLesson(repo, userID, unit.Lessons[0])
+}
+
+func unlockLesson(repo *repository.ProgressRepository, userID uuid.UUID, lesson contentDomain.Lesson) error {
+	_, err := repo.FindLessonProgress(userID, lesson.ID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	base, err := baseModel.NewBaseModel()
+	if err != nil {
+		return err
+	}
+	return repo.CreateLessonProgress(&domain.UserLessonProgress{
+		BaseModel: *base,
+		UserID:    userID,
+		UnitID:    lesson.UnitID,
+		LessonID:  lesson.ID,
+		Status:    domain.StatusUnlocked,
+	})
+}
+
+// refreshUnitProgress recomputes the unit crown level from its lessons and
+// reports whether the unit has just been completed.
+func refreshUnitProgress(repo *repository.ProgressRepository, userID uuid.UUID, unit contentDomain.Unit, now time.Time) (bool, error) {
+	unitProgress, err := repo.FindUnitProgress(userID, unit.ID)
+	if err != nil {
+		return false, err
+	}
+
+	lessonProgress, err := repo.ListLessonProgressByUnits(userID, []uuid.UUID{unit.ID})
+	if err != nil {
+		return false, err
+	}
+	crownsByLesson := make(map[uuid.UUID]int, len(lessonProgress))
+	for _, progress := range lessonProgress {
+		crownsByLesson[progress.LessonID] = progress.CrownLevel
+	}
+
+	crowns := make([]int, 0, len(unit.Lessons))
+	for _, lesson := range unit.Lessons {
+		crowns = append(crowns, crownsByLesson[lesson.ID])
+	}
+
+	wasCompleted := unitProgress.Status == domain.StatusCompleted
+	unitProgress.CrownLevel = domain.UnitCrownLevel(crowns)
+	if unitProgress.CrownLevel >= domain.UnitUnlockCrownLevel {
+		unitProgress.Status = domain.StatusCompleted
+		if unitProgress.CompletedAt == nil {
+			unitProgress.CompletedAt = &now
+		}
+	} else {
+		unitProgress.Status = domain.StatusInProgress
+	}
+
+	if err := repo.UpdateUnitProgress(unitProgress); err != nil {
+		return false, err
+	}
+	return !wasCompleted && unitProgress.Status == domain.StatusCompleted, nil
+}
+
+// completeUnitForPlacement marks a skipped unit and all of its lessons as
+// completed and returns how many lessons were newly completed.
+func completeUnitForPlacement(repo *repository.ProgressRepository, userID, courseID uuid.UUID, unit contentDomain.Unit, now time.Time) (int, error) {
+	if err := unlockUnit(repo, userID, courseID, unit); err != nil {
+		return 0, err
+	}
+
+	newlyCompleted := 0
+	for _, lesson := range unit.Lessons {
+		if err := unlockLesson(repo, userID, lesson); err != nil {
+			return 0, err
+		}
+		lessonProgress, err := repo.FindLessonProgress(userID, lesson.ID)
+		if err != nil {
+			return 0, err
+		}
+		if lessonProgress.Status == domain.StatusCompleted {
+			continue
+		}
+
+		lessonProgress.Status = domain.StatusCompleted
+		if lessonProgress.CrownLevel < domain.UnitUnlockCrownLevel {
+			lessonProgress.CrownLevel = domain.UnitUnlockCrownLevel
+		}
+		lessonProgress.CompletedAt = &now
+		if err := repo.UpdateLessonProgress(lessonProgress); err != nil {
+			return 0, err
+		}
+		newlyCompleted++
+	}
+
+	if _, err := refreshUnitProgress(repo, userID, unit, now); err != nil {
+		return 0, err
+	}
+	return newlyCompleted, nil
+}
+
+func buildCourseView(course *contentDomain.Course, courseProgress *domain.UserCourseProgress, unitProgress []domain.UserUnitProgress, lessonProgress []domain.UserLessonProgress) *domain.CourseProgressView {
+	unitsByID := make(map[uuid.UUID]*domain.UserUnitProgress, len(unitProgress))
+	for i := range unitProgress {
+		unitsByID[unitProgress[i].UnitID] = &unitProgress[i]
+	}
+	lessonsByID := make(map[uuid.UUID]*domain.UserLessonProgress, len(lessonProgress))
+	for i := range lessonProgress {
+		lessonsByID[lessonProgress[i].LessonID] = &lessonProgress[i]
+	}
+
+	view := &domain.CourseProgressView{
+		CourseID:         course.ID,
+		Title:            course.Title,
+		Status:           courseProgress.Status,
+		LessonsCompleted: courseProgress.LessonsCompleted,
+		XPEarned:         courseProgress.XPEarned,
+		Units:            make([]domain.UnitProgressView, 0, len(course.Units)),
+	}
+
+	for _, unit := range course.Units {
+		unitView := domain.UnitProgressView{
+			UnitID:   unit.ID,
+			Title:    unit.Title,
+			Position: unit.Position,
+			Status:   domain.StatusLocked,
+			Lessons:  make([]domain.LessonProgressView, 0, len(unit.Lessons)),
+		}
+		if progress, ok := unitsByID[unit.ID]; ok {
+			unitView.Status = progress.Status
+			unitView.CrownLevel = progress.CrownLevel
+		}
+
+		for _, lesson := range unit.Lessons {
+			unitView.Lessons = append(unitView.Lessons, *buildLessonView(lesson, lessonsByID[lesson.ID]))
+		}
+
+		view.TotalLessons += len(unit.Lessons)
+		view.Units = append(view.Units, unitView)
+	}
+
+	return view
+}
+
+func buildLessonView(lesson contentDomain.Lesson, progress *domain.UserLessonProgress) *domain.LessonProgressView {
+	view := &domain.LessonProgressView{
+		LessonID: lesson.ID,
+		Title:    lesson.Title,
+		Position: lesson.Position,
+		Status:   domain.StatusLocked,
+	}
+	if progress != nil {
+		view.Status = progress.Status
+		view.CrownLevel = progress.CrownLevel
+		view.BestScore = progress.BestScore
+		view.Attempts = progress.Attempts
+		view.CompletedAt = progress.CompletedAt
+	}
+	return view
+}
+
+func buildSessionView(session *domain.LessonSession, revision *contentDomain.ContentRevision, lesson contentDomain.Lesson, progress *domain.UserLessonProgress) (*domain.LessonSessionView, error) {
+	var content contentDomain.LessonSnapshot
+	if err := json.Unmarshal(revision.Snapshot, &content); err != nil {
+		return nil, err
+	}
+
+	return &domain.LessonSessionView{
+		SessionID:      session.ID,
+		Status:         session.Status,
+		RevisionID:     revision.ID,
+		RevisionNumber: revision.RevisionNumber,
+		Progress:       *buildLessonView(lesson, progress),
+		Content:        content,
+		StartedAt:      session.CreatedAt,
+	}, nil
+}
+
+// localizeSession translates the pinned lesson content. Translations are
+// keyed by live exercise IDs, matched to the snapshot through exercise keys.
+func localizeSession(repo *repository.ProgressRepository, view *domain.LessonSessionView, language string) error {
+	lessonID := view.Progress.LessonID
+	exerciseIDs, err := repo.ListExerciseIDs(lessonID)
+	if err != nil {
+		return err
+	}
+
+	entityIDs := []uuid.UUID{lessonID}
+	for _, id := range exerciseIDs {
+		entityIDs = append(entityIDs, id)
+	}
+
+	chain := i18n.FallbackChain(language)
+	rows, err := repo.ListTranslations(entityIDs, chain)
+	if err != nil {
+		return err
+	}
+
+	translations := contentDomain.NewTranslations(rows)
+	translations.LocalizeLessonSnapshot(lessonID, &view.Content, exerciseIDs, chain)
+	view.Progress.Title = view.Content.Title
+	return nil
+}
+
+func unitIDs(course *contentDomain.Course) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(course.Units))
+	for _, unit := range course.Units {
+		ids = append(ids, unit.ID)
+	}
+	return ids
+}
+
+func indexOfUnit(course *contentDomain.Course, unitID uuid.UUID) int {
+	for i, unit := range course.Units {
+		if unit.ID == unitID {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexOfLesson(unit contentDomain.Unit, lessonID uuid.UUID) int {
+	for i, lesson := range unit.Lessons {
+		if lesson.ID == lessonID {
+			return i
+		}
+	}
+	return -1
+}
+
+func firstLessonID(unit contentDomain.Unit) *uuid.UUID {
+	if len(unit.Lessons) == 0 {
+		return nil
+	}
+	return &unit.Lessons[0].ID
+}
+
+func toAppError(err error, message string) error {
+	if appError.IsAppError(err) {
+		return err
+	}
+	return appError.NewInternalError(err, message)
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	contentDomain "s29-be/internal/content/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CompleteLessonRequest struct {
+	Score     int        `json:"score" validate:"min=0,max=100"`
+	SessionID *uuid.UUID `json:"session_id"`
+}
+
+type CompleteLessonResponse struct {
+	LessonID       uuid.UUID  `json:"lesson_id"`
+	Passed         bool       `json:"passed"`
+	CrownLevel     int        `json:"crown_level"`
+	XPAwarded      int        `json:"xp_awarded"`
+	UnitCompleted  bool       `json:"unit_completed"`
+	CourseComplete bool       `json:"course_completed"`
+	NextLessonID   *uuid.UUID `json:"next_lesson_id,omitempty"`
+	NextUnitID     *uuid.UUID `json:"next_unit_id,omitempty"`
+}
+
+// PlacementRequest skips a learner ahead either to an explicit unit or to
+// the unit matching their placement test score.
+type PlacementRequest struct {
+	Score  *int       `json:"score" validate:"required_without=UnitID,omitempty,min=0,max=100"`
+	UnitID *uuid.UUID `json:"unit_id" validate:"required_without=Score"`
+}
+
+type LessonProgressView struct {
+	LessonID    uuid.UUID  `json:"lesson_id"`
+	Title       string     `json:"title"`
+	Position    int        `json:"position"`
+	Status      Status     `json:"status"`
+	CrownLevel  int        `json:"crown_level"`
+	BestScore   int        `json:"best_score"`
+	Attempts    int        `json:"attempts"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type UnitProgressView struct {
+	UnitID     uuid.UUID            `json:"unit_id"`
+	Title      string               `json:"title"`
+	Position   int                  `json:"position"`
+	Status     Status               `json:"status"`
+	CrownLevel int                  `json:"crown_level"`
+	Lessons    []LessonProgressView `json:"lessons"`
+}
+
+type CourseProgressView struct {
+	CourseID         uuid.UUID          `json:"course_id"`
+	Title            string             `json:"title"`
+	Status           Status             `json:"status"`
+	LessonsCompleted int                `json:"lessons_completed"`
+	TotalLessons     int                `json:"total_lessons"`
+	XPEarned         int64              `json:"xp_earned"`
+	Units            []UnitProgressView `json:"units"`
+}
+
+type CourseSummaryView struct {
+	CourseID         uuid.UUID  `json:"course_id"`
+	Slug             string     `json:"slug"`
+	Title            string     `json:"title"`
+	Status           Status     `json:"status"`
+	PercentComplete  int        `json:"percent_complete"`
+	LessonsCompleted int        `json:"lessons_completed"`
+	TotalLessons     int        `json:"total_lessons"`
+	Crowns           int        `json:"crowns"`
+	XPEarned         int64      `json:"xp_earned"`
+	CurrentUnitID    *uuid.UUID `json:"current_unit_id,omitempty"`
+	CurrentLessonID  *uuid.UUID `json:"current_lesson_id,omitempty"`
+	LastActivityAt   time.Time  `json:"last_activity_at"`
+}
+
+type ProgressSummary struct {
+	TotalXP           int64               `json:"total_xp"`
+	TotalCrowns       int                 `json:"total_crowns"`
+	CoursesInProgress int                 `json:"courses_in_progress"`
+	CoursesCompleted  int                 `json:"courses_completed"`
+	Courses           []CourseSummaryView `json:"courses"`
+}
+
+type LessonSessionView struct {
+	SessionID      uuid.UUID                    `json:"session_id"`
+	Status         SessionStatus                `json:"status"`
+	RevisionID     uuid.UUID                    `json:"revision_id"`
+	RevisionNumber int                          `json:"revision_number"`
+	Progress       LessonProgressView           `json:"progress"`
+	Content        contentDomain.LessonSnapshot `json:"content"`
+	StartedAt      time.Time                    `json:"started_at"`
+}
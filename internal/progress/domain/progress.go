@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"s29-be/pkg/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusLocked     Status = "locked"
+	StatusUnlocked   Status = "unlocked"
+	StatusInProgress Status = "in_progress"
+	StatusCompleted  Status = "completed"
+)
+
+type UserCourseProgress struct {
+	model.BaseModel
+	UserID            uuid.UUID  `json:"user_id" gorm:"not null;type:uuid"`
+	CourseID          uuid.UUID  `json:"course_id" gorm:"not null;type:uuid"`
+	Status            Status     `json:"status" gorm:"not null;size:20;default:unlocked"`
+	CurrentUnitID     *uuid.UUID `json:"current_unit_id" gorm:"type:uuid"`
+	CurrentLessonID   *uuid.UUID `json:"current_lesson_id" gorm:"type:uuid"`
+	LessonsCompleted  int        `json:"lessons_completed" gorm:"not null;default:0"`
+	XPEarned          int64      `json:"xp_earned" gorm:"column:xp_earned;not null;default:0"`
+	PlacementTestedAt *time.Time `json:"placement_tested_at"`
+	CompletedAt       *time.Time `json:"completed_at"`
+}
+
+func (UserCourseProgress) TableName() string {
+	return "user_course_progress"
+}
+
+type UserUnitProgress struct {
+	model.BaseModel
+	UserID      uuid.UUID  `json:"user_id" gorm:"not null;type:uuid"`
+	CourseID    uuid.UUID  `json:"course_id" gorm:"not null;type:uuid"`
+	UnitID      uuid.UUID  `json:"unit_id" gorm:"not null;type:uuid"`
+	Status      Status     `json:"status" gorm:"not null;size:20;default:unlocked"`
+	CrownLevel  int        `json:"crown_level" gorm:"not null;default:0"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+func (UserUnitProgress) TableName() string {
+	return "user_unit_progress"
+}
+
+type UserLessonProgress struct {
+	model.BaseModel
+	UserID      uuid.UUID  `json:"user_id" gorm:"not null;type:uuid"`
+	UnitID      uuid.UUID  `json:"unit_id" gorm:"not null;type:uuid"`
+	LessonID    uuid.UUID  `json:"lesson_id" gorm:"not null;type:uuid"`
+	Status      Status     `json:"status" gorm:"not null;size:20;default:unlocked"`
+	CrownLevel  int        `json:"crown_level" gorm:"not null;default:0"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	BestScore   int        `json:"best_score" gorm:"not null;default:0"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+func (UserLessonProgress) TableName() string {
+	return "user_lesson_progress"
+}
+
+type SessionStatus string
+
+const (
+	SessionActive    SessionStatus = "active"
+	SessionCompleted SessionStatus = "completed"
+	SessionAbandoned SessionStatus = "abandoned"
+)
+
+// LessonSession is one attempt at a lesson. It is pinned to the content
+// revision that was live when it started, so publishing a new revision
+// mid-lesson does not change the exercises under the learner.
+type LessonSession struct {
+	model.BaseModel
+	UserID      uuid.UUID     `json:"user_id" gorm:"not null;type:uuid"`
+	LessonID    uuid.UUID     `json:"lesson_id" gorm:"not null;type:uuid"`
+	RevisionID  uuid.UUID     `json:"revision_id" gorm:"not null;type:uuid"`
+	Status      SessionStatus `json:"status" gorm:"not null;size:20;default:active"`
+	Score       *int          `json:"score"`
+	CompletedAt *time.Time    `json:"completed_at"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+const (
+	// MaxCrownLevel caps how many times a lesson can be levelled up.
+	MaxCrownLevel = 5
+	// PassingScore is the minimum lesson score (0-100) that counts as a completion.
+	PassingScore = 60
+	// UnitUnlockCrownLevel is the crown level every lesson of a unit needs before the next unit unlocks.
+	UnitUnlockCrownLevel = 1
+)
+
+// IsAccessible reports whether a learner may start a lesson or unit in this state.
+func (s Status) IsAccessible() bool {
+	return s == StatusUnlocked || s == StatusInProgress || s == StatusCompleted
+}
+
+// NextCrownLevel returns the crown level reached after another passing attempt.
+func NextCrownLevel(current int) int {
+	if current >= MaxCrownLevel {
+		return MaxCrownLevel
+	}
+	return current + 1
+}
+
+// LessonXP returns the XP awarded for a passing attempt. Replays of an
+// already completed lesson are worth half, so grinding stays possible but
+// progressing through new material is preferred.
+func LessonXP(reward int, alreadyCompleted bool) int {
+	if alreadyCompleted {
+		return reward / 2
+	}
+	return reward
+}
+
+// UnitCrownLevel is the lowest crown level across the unit's lessons; a
+// lesson without progress counts as zero.
+func UnitCrownLevel(lessonCrowns []int) int {
+	if len(lessonCrowns) == 0 {
+		return 0
+	}
+	lowest := lessonCrowns[0]
+	for _, crown := range lessonCrowns[1:] {
+		if crown < lowest {
+			lowest = crown
+		}
+	}
+	return lowest
+}
+
+// PlacementUnitIndex maps a placement test score (0-100) to the index of the
+// unit the learner should start on. A perfect score places the learner on
+// the last unit rather than past the end of the course.
+func PlacementUnitIndex(score, unitCount int) int {
+	if unitCount == 0 || score <= 0 {
+		return 0
+	}
+	if score > 100 {
+		score = 100
+	}
+	index := score * unitCount / 100
+	if index >= unitCount {
+		index = unitCount - 1
+	}
+	return index
+}
+
+// StreakDay is the start of the UTC day t falls on. A streak grows by one
+// for each consecutive day with a passed lesson.
+func StreakDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// StreakCutoff is the earliest last lesson time that keeps a streak alive
+// at t: a learner who last passed a lesson before yesterday has lost it.
+func StreakCutoff(t time.Time) time.Time {
+	return StreakDay(t).AddDate(0, 0, -1)
+}
@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"context"
+	"log/slog"
+	"s29-be/internal/progress/adapters/http"
+	"s29-be/internal/progress/adapters/repository"
+	"s29-be/internal/progress/application"
+	svcContext "s29-be/pkg/context"
+	"s29-be/pkg/middleware"
+	"s29-be/pkg/scheduler"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Streaks are counted in UTC days, so lapsed ones are reset just after UTC midnight
+const streakResetSchedule = "5 0 * * *"
+
+type ProgressModule struct {
+	Repository     *repository.ProgressRepository
+	Service        *application.ProgressService
+	Handler        *http.ProgressHandler
+	AuthMiddleware *middleware.AuthMiddleware
+	Idempotency    fiber.Handler
+}
+
+func NewProgressModule(serviceContext *svcContext.ServiceContext) *ProgressModule {
+	progressRepo := repository.NewProgressRepository(serviceContext.GetDB())
+	progressService := application.NewProgressService(progressRepo)
+	progressHandler := http.NewProgressHandler(progressService)
+
+	serviceContext.GetScheduler().Register(scheduler.Job{
+		Name:     "streak-reset",
+		Schedule: streakResetSchedule,
+		Timeout:  10 * time.Minute,
+		Run: func(ctx context.Context, run *scheduler.Run) error {
+			at := time.Now()
+			if run.ScheduledFor != nil {
+				at = *run.ScheduledFor
+			}
+			reset, err := progressService.ResetLapsedStreaks(ctx, at)
+			if err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "Reset lapsed streaks", slog.Int64("count", reset))
+			return nil
+		},
+	})
+
+	// Without Redis a retry could award XP again, so keyed requests wait
+	idempotencyConfig := middleware.DefaultIdempotencyConfig()
+	idempotencyConfig.FailClosed = true
+
+	return &ProgressModule{
+		Repository:     progressRepo,
+		Service:        progressService,
+		Handler:        progressHandler,
+		AuthMiddleware: serviceContext.GetAuthMiddleware(),
+		// Clients retry completions on flaky networks; XP must be awarded once
+		Idempotency: middleware.Idempotency(serviceContext.GetCacheClient(), idempotencyConfig),
+	}
+}
+
+func (m *ProgressModule) RegisterRoutes(router fiber.Router) {
+	progress := router.Group("progress")
+	progress.Use(m.AuthMiddleware.RequireAuth(), m.Idempotency)
+	{
+		progress.Get("/summary", m.Handler.GetSummary)
+		progress.Get("/courses/:courseId", m.Handler.GetCourseProgress)
+		progress.Post("/courses/:courseId/enroll", m.Handler.EnrollInCourse)
+		progress.Post("/courses/:courseId/placement", m.Handler.ApplyPlacement)
+		progress.Post("/lessons/:lessonId/start", m.Handler.StartLesson)
+		progress.Post("/lessons/:lessonId/complete", m.Handler.CompleteLesson)
+		progress.Get("/sessions/:sessionId", m.Handler.GetSession)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	model "s29-be/internal/user/domain"
 	app_error "s29-be/pkg/error"
 	json_response "s29-be/pkg/json"
+	"s29-be/pkg/validation"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type UserHandler struct {
@@ -19,33 +21,47 @@ func NewUserHandler(userService *user_service.UserService) *UserHandler {
 	}
 }
 
-func (h *UserHandler) HandleError(c *fiber.Ctx, err error) bool {
-	if err == nil {
-		return false
+func (h *UserHandler) AfterRegistration(c *fiber.Ctx) error {
+	var request model.AfterRegistrationRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
 	}
 
-	if appErr, ok := app_error.GetAppError(err); ok {
-		json_response.ResponseJSON(c, appErr.StatusCode, appErr.Message, appErr.Data)
-		return true
+	userID, err := h.userService.CreateUserAfterRegistration(c.UserContext(), &request)
+	if err != nil {
+		return err
 	}
 
-	json_response.ResponseInternalError(c, err)
-	return true
+	json_response.ResponseOK(c, userID)
+	return nil
 }
 
-func (h *UserHandler) AfterRegistration(c *fiber.Ctx) error {
-	var request model.AfterRegistrationRequest
-	if err := c.BodyParser(&request); err != nil {
-		json_response.ResponseBadRequest(c, err.Error())
-		return nil
+// @Summary Update Preferred Language
+// @Description Save the language used for API messages and content; send an empty language to follow Accept-Language again
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer {token}"
+// @Param request body model.UpdateLanguageRequest true "Preferred language"
+// @Success 200 {object} model.User
+// @Failure default {object} models.ErrorResponse
+// @Router /api/v1/users/me/language [put]
+func (h *UserHandler) UpdatePreferredLanguage(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uuid.UUID)
+	if !ok {
+		return app_error.NewUnauthorizedError(nil, "")
 	}
 
-	userID, err := h.userService.CreateUserAfterRegistration(&request)
+	var request model.UpdateLanguageRequest
+	if err := validation.BindBody(c, &request); err != nil {
+		return err
+	}
+
+	user, err := h.userService.UpdatePreferredLanguage(c.UserContext(), userID, &request)
 	if err != nil {
-		h.HandleError(c, err)
-		return nil
+		return err
 	}
 
-	json_response.ResponseOK(c, userID)
+	json_response.ResponseOK(c, user)
 	return nil
 }
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"context"
 	model "s29-be/internal/user/domain"
+	"s29-be/pkg/events"
+	"s29-be/pkg/outbox"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +21,19 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	}
 }
 
+// WithContext returns a repository whose queries run under ctx, so they are
+// cancelled with the request and logged with its attributes.
+func (r *UserRepository) WithContext(ctx context.Context) *UserRepository {
+	return &UserRepository{db: r.db.WithContext(ctx)}
+}
+
+// Transaction runs fn against a repository bound to a single database transaction.
+func (r *UserRepository) Transaction(fn func(txRepo *UserRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&UserRepository{db: tx})
+	})
+}
+
 func (r *UserRepository) CreateUserAfterRegistration(user *model.User) (*model.User, error) {
 	err := r.db.Create(user).Error
 	if err != nil {
@@ -25,3 +42,30 @@ func (r *UserRepository) CreateUserAfterRegistration(user *model.User) (*model.U
 
 	return user, nil
 }
+
+func (r *UserRepository) FindUserByID(userID uuid.UUID) (*model.User, error) {
+	var user model.User
+	if err := r.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) UpdatePreferredLanguage(user *model.User) error {
+	return r.db.Model(user).Update("preferred_language", user.PreferredLanguage).Error
+}
+
+// RefreshXPPoints sets users.xp_points to the XP earned across all courses.
+func (r *UserRepository) RefreshXPPoints(userID uuid.UUID) error {
+	return r.db.Exec(`
+		UPDATE users SET xp_points = (
+			SELECT COALESCE(SUM(xp_earned), 0) FROM user_course_progress WHERE user_id = ?
+		)
+		WHERE id = ?`, userID, userID).Error
+}
+
+// AddOutboxEvent stores event for publication once the surrounding
+// transaction commits.
+func (r *UserRepository) AddOutboxEvent(userID uuid.UUID, event events.Event) error {
+	return outbox.Add(r.db, "user", userID, event)
+}
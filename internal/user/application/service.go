@@ -1,11 +1,18 @@
 package application
 
 import (
+	"context"
+	"errors"
 	"s29-be/internal/user/adapters/repository"
 	model "s29-be/internal/user/domain"
+	appError "s29-be/pkg/error"
+	"s29-be/pkg/events"
+	"s29-be/pkg/i18n"
+	"s29-be/pkg/metrics"
 	baseModel "s29-be/pkg/model"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UserService struct {
@@ -18,7 +25,7 @@ func NewUserService(userRepo *repository.UserRepository) *UserService {
 	}
 }
 
-func (s *UserService) CreateUserAfterRegistration(user *model.AfterRegistrationRequest) (*uuid.UUID, error) {
+func (s *UserService) CreateUserAfterRegistration(ctx context.Context, user *model.AfterRegistrationRequest) (*uuid.UUID, error) {
 	identityID, err := uuid.Parse(user.Identity.ID)
 	if err != nil {
 		return nil, err
@@ -29,17 +36,66 @@ func (s *UserService) CreateUserAfterRegistration(user *model.AfterRegistrationR
 		return nil, err
 	}
 
-	userModel, err := s.userRepo.CreateUserAfterRegistration(&model.User{
-		BaseModel:        *baseModelInstance,
-		KratosIdentityID: identityID,
-		Email:            user.Identity.Traits.Email,
-		IsActive:         true,
-		LastLoginAt:      nil,
-	})
+	var userModel *model.User
+	err = s.userRepo.WithContext(ctx).Transaction(func(repo *repository.UserRepository) error {
+		created, err := repo.CreateUserAfterRegistration(&model.User{
+			BaseModel:        *baseModelInstance,
+			KratosIdentityID: identityID,
+			Email:            user.Identity.Traits.Email,
+			IsActive:         true,
+			Role:             model.RoleLearner,
+			LastLoginAt:      nil,
+		})
+		if err != nil {
+			return err
+		}
+		userModel = created
 
+		return repo.AddOutboxEvent(created.ID, events.UserRegistered{
+			UserID:           created.ID,
+			KratosIdentityID: created.KratosIdentityID,
+			Email:            created.Email,
+			RegisteredAt:     created.CreatedAt,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
+	metrics.Registrations.Inc()
 
 	return &userModel.ID, nil
 }
+
+func (s *UserService) UpdatePreferredLanguage(ctx context.Context, userID uuid.UUID, request *model.UpdateLanguageRequest) (*model.User, error) {
+	repo := s.userRepo.WithContext(ctx)
+
+	var language *string
+	if request.Language != "" {
+		normalized := i18n.Normalize(request.Language)
+		if normalized == "" {
+			return nil, appError.NewBadRequestError(nil, "Invalid language")
+		}
+		language = &normalized
+	}
+
+	user, err := repo.FindUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appError.NewNotFoundError(err, "user not found")
+		}
+		return nil, appError.NewInternalError(err, "failed to load user")
+	}
+
+	user.PreferredLanguage = language
+	if err := repo.UpdatePreferredLanguage(user); err != nil {
+		return nil, appError.NewInternalError(err, "failed to update language")
+	}
+
+	return user, nil
+}
+
+// RefreshXP recomputes the user's XP total from their course progress.
+// Recomputing rather than adding keeps redelivered XPAwarded events harmless.
+func (s *UserService) RefreshXP(ctx context.Context, userID uuid.UUID) error {
+	return s.userRepo.WithContext(ctx).RefreshXPPoints(userID)
+}
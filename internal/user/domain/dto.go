@@ -9,7 +9,7 @@ type AfterRegistrationRequest struct {
 }
 
 type IdentityData struct {
-	ID        string     `json:"id"`
+	ID        string     `json:"id" validate:"required,uuid"`
 	Traits    UserTraits `json:"traits"`
 	SchemaID  string     `json:"schema_id"`
 	State     string     `json:"state"`
@@ -18,8 +18,8 @@ type IdentityData struct {
 }
 
 type UserTraits struct {
-	Email    string `json:"email"`
-	UserName string `json:"username"`
+	Email    string `json:"email" validate:"required,email"`
+	UserName string `json:"username" validate:"required,username"`
 	Age      int    `json:"age"`
 }
 
@@ -38,3 +38,9 @@ type RequestContextData struct {
 	IPAddress *string `json:"ip_address"`
 	Timestamp string  `json:"timestamp"`
 }
+
+// UpdateLanguageRequest saves the user's language; an empty value clears it
+// so Accept-Language is used again.
+type UpdateLanguageRequest struct {
+	Language string `json:"language" validate:"omitempty,locale"`
+}
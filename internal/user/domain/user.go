@@ -7,10 +7,24 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	RoleLearner  = "learner"
+	RoleEditor   = "editor"
+	RoleReviewer = "reviewer"
+	RoleAdmin    = "admin"
+)
+
 type User struct {
 	model.BaseModel
-	KratosIdentityID uuid.UUID  `json:"kratos_identity_id" gorm:"not null;unique;type:uuid"`
-	Email            string     `json:"email" gorm:"not null;unique;size:255"`
-	IsActive         bool       `json:"is_active" gorm:"default:true"`
-	LastLoginAt      *time.Time `json:"last_login_at"`
+	KratosIdentityID uuid.UUID `json:"kratos_identity_id" gorm:"not null;unique;type:uuid"`
+	Email            string    `json:"email" gorm:"not null;unique;size:255"`
+	IsActive         bool      `json:"is_active" gorm:"default:true"`
+	Role             string    `json:"role" gorm:"not null;size:20;default:learner"`
+	// PreferredLanguage overrides Accept-Language when set
+	PreferredLanguage *string    `json:"preferred_language" gorm:"size:10"`
+	LastLoginAt       *time.Time `json:"last_login_at"`
+	// Kept up to date in SQL as lessons are completed
+	XPPoints     int64      `json:"xp_points" gorm:"default:0"`
+	StreakDays   int        `json:"streak_days" gorm:"default:0"`
+	LastLessonAt *time.Time `json:"last_lesson_at"`
 }
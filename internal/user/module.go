@@ -1,10 +1,13 @@
 package user
 
 import (
+	"context"
 	"s29-be/internal/user/adapters/http"
 	"s29-be/internal/user/adapters/repository"
 	"s29-be/internal/user/application"
 	ctx2 "s29-be/pkg/context"
+	"s29-be/pkg/events"
+	"s29-be/pkg/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,6 +16,8 @@ type UserModule struct {
 	Repository *repository.UserRepository
 	Service    *application.UserService
 	Handler    *http.UserHandler
+
+	AuthMiddleware *middleware.AuthMiddleware
 }
 
 func NewUserModule(serviceContext *ctx2.ServiceContext) *UserModule {
@@ -20,14 +25,23 @@ func NewUserModule(serviceContext *ctx2.ServiceContext) *UserModule {
 	userService := application.NewUserService(userRepo)
 	userHandler := http.NewUserHandler(userService)
 
+	events.Subscribe(serviceContext.GetEventBus(), "user.refresh-xp", func(ctx context.Context, event events.XPAwarded) error {
+		return userService.RefreshXP(ctx, event.UserID)
+	})
+
 	return &UserModule{
 		Repository: userRepo,
 		Service:    userService,
 		Handler:    userHandler,
+
+		AuthMiddleware: serviceContext.GetAuthMiddleware(),
 	}
 }
 
 func (u *UserModule) RegisterRoutes(router fiber.Router) {
 	internal := router.Group("/internal")
 	internal.Post("/hooks/after-registration", u.Handler.AfterRegistration)
+
+	users := router.Group("/users", u.AuthMiddleware.RequireAuth())
+	users.Put("/me/language", u.Handler.UpdatePreferredLanguage)
 }
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE courses (
+    id UUID PRIMARY KEY NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    language_code VARCHAR(10) NOT NULL DEFAULT 'vi',
+    level VARCHAR(20) NOT NULL DEFAULT 'beginner',
+    position INT NOT NULL DEFAULT 0,
+    is_published BOOLEAN DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE units (
+    id UUID PRIMARY KEY NOT NULL,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    slug VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, slug)
+);
+
+CREATE INDEX idx_units_course_position ON units (course_id, position);
+
+CREATE TABLE lessons (
+    id UUID PRIMARY KEY NOT NULL,
+    unit_id UUID NOT NULL REFERENCES units(id) ON DELETE CASCADE,
+    slug VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    position INT NOT NULL DEFAULT 0,
+    xp_reward INT NOT NULL DEFAULT 10,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (unit_id, slug)
+);
+
+CREATE INDEX idx_lessons_unit_position ON lessons (unit_id, position);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS lessons;
+DROP TABLE IF EXISTS units;
+DROP TABLE IF EXISTS courses;
+
+-- +goose StatementEnd
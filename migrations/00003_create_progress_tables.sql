@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-course enrollment and roll-up of a learner's progress
+CREATE TABLE user_course_progress (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'unlocked',
+    current_unit_id UUID REFERENCES units(id) ON DELETE SET NULL,
+    current_lesson_id UUID REFERENCES lessons(id) ON DELETE SET NULL,
+    lessons_completed INT NOT NULL DEFAULT 0,
+    xp_earned BIGINT NOT NULL DEFAULT 0,
+    placement_tested_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, course_id)
+);
+
+-- Units and lessons without a row are locked for the learner
+CREATE TABLE user_unit_progress (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    unit_id UUID NOT NULL REFERENCES units(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'unlocked',
+    crown_level INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, unit_id)
+);
+
+CREATE INDEX idx_user_unit_progress_course ON user_unit_progress (user_id, course_id);
+
+CREATE TABLE user_lesson_progress (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    unit_id UUID NOT NULL REFERENCES units(id) ON DELETE CASCADE,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'unlocked',
+    crown_level INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    best_score INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, lesson_id)
+);
+
+CREATE INDEX idx_user_lesson_progress_unit ON user_lesson_progress (user_id, unit_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS user_lesson_progress;
+DROP TABLE IF EXISTS user_unit_progress;
+DROP TABLE IF EXISTS user_course_progress;
+
+-- +goose StatementEnd
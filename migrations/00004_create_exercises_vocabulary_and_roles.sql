@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE exercises (
+    id UUID PRIMARY KEY NOT NULL,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    key VARCHAR(100) NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    prompt TEXT NOT NULL,
+    answer TEXT NOT NULL,
+    choices TEXT[],
+    explanation TEXT,
+    audio_url VARCHAR(500),
+    image_url VARCHAR(500),
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (lesson_id, key)
+);
+
+CREATE INDEX idx_exercises_lesson_position ON exercises (lesson_id, position);
+
+CREATE TABLE vocabulary (
+    id UUID PRIMARY KEY NOT NULL,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    word VARCHAR(255) NOT NULL,
+    translation VARCHAR(255) NOT NULL,
+    part_of_speech VARCHAR(30),
+    example TEXT,
+    audio_url VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, word)
+);
+
+-- learner, editor, reviewer or admin
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'learner';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+DROP TABLE IF EXISTS vocabulary;
+DROP TABLE IF EXISTS exercises;
+
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Draft/review/publish history for courses, units and lessons. The snapshot
+-- holds the editable fields of the entity (and a lesson's exercises).
+CREATE TABLE content_revisions (
+    id UUID PRIMARY KEY NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    revision_number INT NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'draft',
+    snapshot JSONB NOT NULL,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_comment TEXT,
+    scheduled_publish_at TIMESTAMP WITH TIME ZONE,
+    published_at TIMESTAMP WITH TIME ZONE,
+    published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    rolled_back_from UUID REFERENCES content_revisions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id, revision_number)
+);
+
+-- At most one live revision per entity
+CREATE UNIQUE INDEX idx_content_revisions_published ON content_revisions (entity_type, entity_id) WHERE state = 'published';
+
+CREATE INDEX idx_content_revisions_scheduled ON content_revisions (scheduled_publish_at) WHERE state = 'approved' AND scheduled_publish_at IS NOT NULL;
+
+-- A learner's attempt at a lesson, pinned to the revision it started on
+CREATE TABLE lesson_sessions (
+    id UUID PRIMARY KEY NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    lesson_id UUID NOT NULL REFERENCES lessons(id) ON DELETE CASCADE,
+    revision_id UUID NOT NULL REFERENCES content_revisions(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    score INT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_lesson_sessions_user_lesson ON lesson_sessions (user_id, lesson_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS lesson_sessions;
+DROP TABLE IF EXISTS content_revisions;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- NULL means the Accept-Language header decides
+ALTER TABLE users ADD COLUMN preferred_language VARCHAR(10);
+
+-- Translated values of text fields on courses, units, lessons, exercises and
+-- vocabulary. The entity's own column holds the authored text and is the
+-- last step of every fallback chain. Rows outlive deleted entities, which
+-- is harmless because entity IDs are never reused.
+CREATE TABLE content_translations (
+    id UUID PRIMARY KEY NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    language_code VARCHAR(10) NOT NULL,
+    field VARCHAR(50) NOT NULL,
+    value TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id, language_code, field)
+);
+
+CREATE INDEX idx_content_translations_entity ON content_translations (entity_id, language_code);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS content_translations;
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_language;
+
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Strips diacritics before indexing so "xin chao" matches "xin chào". The
+-- simple parser keeps Vietnamese syllables intact since there is no stemmer.
+CREATE TEXT SEARCH CONFIGURATION vi_unaccent (COPY = simple);
+ALTER TEXT SEARCH CONFIGURATION vi_unaccent
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+
+-- unaccent() is only STABLE because its dictionary can change; pinning the
+-- dictionary makes it safe to use in indexes.
+CREATE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- One row per searchable course, lesson or vocabulary entry of a published
+-- course, rebuilt whenever the course's live content changes.
+CREATE TABLE search_documents (
+    id UUID PRIMARY KEY NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id UUID NOT NULL,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    level VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    title_normalized TEXT NOT NULL,
+    document TSVECTOR NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id)
+);
+
+CREATE INDEX idx_search_documents_document ON search_documents USING GIN (document);
+CREATE INDEX idx_search_documents_title_trgm ON search_documents USING GIN (title_normalized gin_trgm_ops);
+CREATE INDEX idx_search_documents_course ON search_documents (course_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS search_documents;
+DROP FUNCTION IF EXISTS immutable_unaccent(text);
+DROP TEXT SEARCH CONFIGURATION IF EXISTS vi_unaccent;
+
+-- +goose StatementEnd
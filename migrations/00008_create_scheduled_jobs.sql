@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Recurring jobs and the outcome of their latest run. last_fence is the
+-- fencing token of the run that last wrote here; older runs may not
+-- overwrite a newer run's status.
+CREATE TABLE scheduled_jobs (
+    name VARCHAR(100) PRIMARY KEY NOT NULL,
+    schedule VARCHAR(100) NOT NULL,
+    paused BOOLEAN NOT NULL DEFAULT false,
+    paused_at TIMESTAMP WITH TIME ZONE,
+    paused_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    last_run_id UUID,
+    last_status VARCHAR(20),
+    last_error TEXT,
+    last_started_at TIMESTAMP WITH TIME ZONE,
+    last_finished_at TIMESTAMP WITH TIME ZONE,
+    last_fence BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per run. The unique key lets only one replica record a scheduled
+-- tick; manual runs have no scheduled_for and are never deduplicated.
+CREATE TABLE scheduled_job_runs (
+    id UUID PRIMARY KEY NOT NULL,
+    job_name VARCHAR(100) NOT NULL REFERENCES scheduled_jobs(name) ON DELETE CASCADE,
+    trigger_type VARCHAR(20) NOT NULL,
+    scheduled_for TIMESTAMP WITH TIME ZONE,
+    triggered_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    fence BIGINT NOT NULL,
+    instance VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (job_name, scheduled_for)
+);
+
+CREATE INDEX idx_scheduled_job_runs_job_started ON scheduled_job_runs (job_name, started_at DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS scheduled_job_runs;
+DROP TABLE IF EXISTS scheduled_jobs;
+
+-- +goose StatementEnd
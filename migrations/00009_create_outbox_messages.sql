@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Events written in the same transaction as the change they describe and
+-- published to the event bus by the outbox relay, in sequence order.
+CREATE TABLE outbox_messages (
+    id UUID PRIMARY KEY NOT NULL,
+    sequence BIGSERIAL NOT NULL UNIQUE,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_name VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_messages_unpublished ON outbox_messages (sequence) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_messages_published ON outbox_messages (published_at) WHERE published_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS outbox_messages;
+
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+// Package migrations embeds the goose SQL migrations, so the binaries
+// migrate the database without the files being shipped alongside them.
+package migrations
+
+import "embed"
+
+// FS holds every migration at its root.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -5,9 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -16,18 +16,17 @@ type Client struct {
 }
 
 type Config struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Host     string `yaml:"host" env:"REDIS_HOST"`
+	Port     string `yaml:"port" env:"REDIS_PORT"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD"`
+	DB       int    `yaml:"db" env:"REDIS_DB"`
 }
 
-func NewConfig() *Config {
+func DefaultConfig() *Config {
 	return &Config{
-		Host:     getEnv("REDIS_HOST", "localhost"),
-		Port:     getEnv("REDIS_PORT", "6379"),
-		Password: getEnv("REDIS_PASSWORD", ""),
-		DB:       0, // default DB
+		Host: "localhost",
+		Port: "6379",
+		DB:   0, // default DB
 	}
 }
 
@@ -38,6 +37,12 @@ func NewClient(config *Config) (*Client, error) {
 		DB:       config.DB,
 	})
 
+	// Commands become child spans of their context's span; arguments are
+	// left out since they include tokens and cached payloads
+	if err := redisotel.InstrumentTracing(rdb, redisotel.WithDBStatement(false)); err != nil {
+		return nil, fmt.Errorf("failed to instrument Redis: %w", err)
+	}
+
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -54,29 +59,43 @@ func (c *Client) Close() error {
 	return c.rdb.Close()
 }
 
+func (c *Client) Ping(ctx context.Context) error {
+	return c.rdb.Ping(ctx).Err()
+}
+
+// PoolStats reports the state of the connection pool.
+func (c *Client) PoolStats() *redis.PoolStats {
+	return c.rdb.PoolStats()
+}
+
 // Queue operations
+
+// Enqueue pushes data in a Job envelope with no type, so reliable consumers
+// lease it by its ID like any other job.
 func (c *Client) Enqueue(ctx context.Context, queueName string, data interface{}) error {
-	jsonData, err := json.Marshal(data)
+	_, raw, err := newJob("", data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
+		return err
 	}
 
-	return c.rdb.LPush(ctx, queueName, jsonData).Err()
+	return c.rdb.LPush(ctx, queueName, raw).Err()
 }
 
 func (c *Client) EnqueueWithDelay(ctx context.Context, queueName string, data interface{}, delay time.Duration) error {
-	jsonData, err := json.Marshal(data)
+	_, raw, err := newJob("", data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
+		return err
 	}
 
-	score := float64(time.Now().Add(delay).Unix())
-	return c.rdb.ZAdd(ctx, queueName+":delayed", redis.Z{
-		Score:  score,
-		Member: jsonData,
+	return c.rdb.ZAdd(ctx, delayedKey(queueName), redis.Z{
+		Score:  delayedScore(time.Now().Add(delay)),
+		Member: raw,
 	}).Err()
 }
 
+// Dequeue pops a job with BRPOP, so the job is lost if the caller crashes
+// before finishing it. Use a Consumer for jobs that must not be lost. Jobs
+// are returned without their envelope, as they were passed to Enqueue.
 func (c *Client) Dequeue(ctx context.Context, queueName string, timeout time.Duration) ([]byte, error) {
 	result, err := c.rdb.BRPop(ctx, timeout, queueName).Result()
 	if err != nil {
@@ -90,6 +109,10 @@ func (c *Client) Dequeue(ctx context.Context, queueName string, timeout time.Dur
 		return nil, fmt.Errorf("unexpected result format")
 	}
 
+	var job Job
+	if err := json.Unmarshal([]byte(result[1]), &job); err == nil && job.ID != "" && job.Payload != nil {
+		return job.Payload, nil
+	}
 	return []byte(result[1]), nil
 }
 
@@ -132,38 +155,8 @@ func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
 	return c.rdb.LLen(ctx, key).Result()
 }
 
-// Sorted set operations for delayed jobs
+// ProcessDelayedJobs moves every due job of the queue's delayed set onto the queue.
 func (c *Client) ProcessDelayedJobs(ctx context.Context, queueName string) error {
-	now := float64(time.Now().Unix())
-	
-	// Get jobs that are ready to be processed
-	jobs, err := c.rdb.ZRangeByScore(ctx, queueName+":delayed", &redis.ZRangeBy{
-		Min: "0",
-		Max: fmt.Sprintf("%f", now),
-	}).Result()
-	
-	if err != nil {
-		return err
-	}
-
-	for _, job := range jobs {
-		// Move job to main queue
-		pipe := c.rdb.TxPipeline()
-		pipe.LPush(ctx, queueName, job)
-		pipe.ZRem(ctx, queueName+":delayed", job)
-		_, err = pipe.Exec(ctx)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	_, err := c.PromoteDelayedJobs(ctx, queueName)
+	return err
 }
-
-// Utility functions
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
\ No newline at end of file
@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Delayed jobs live in <queue>:delayed scored by the Unix time in
+// milliseconds at which they become due. Scores written in seconds by
+// older builds read as long overdue and are promoted on the next pass.
+
+const (
+	promoteBatchSize  = 500
+	promoterLeaderKey = "delayed:promoter:leader"
+)
+
+func delayedKey(queueName string) string {
+	return queueName + ":delayed"
+}
+
+func delayedScore(at time.Time) float64 {
+	return float64(at.UnixMilli())
+}
+
+// Moves up to a batch of due jobs, oldest first, and reports how overdue
+// the oldest one was.
+//
+// KEYS: delayed, queue
+// ARGV: batch size
+var promoteScript = redis.NewScript(nowMillis + `
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', now, 'WITHSCORES', 'LIMIT', 0, tonumber(ARGV[1]))
+if #due == 0 then
+	return {0, 0}
+end
+local members = {}
+for i = 1, #due, 2 do
+	members[#members + 1] = due[i]
+end
+for i = 1, #members, 100 do
+	local chunk = {}
+	for j = i, math.min(i + 99, #members) do
+		chunk[#chunk + 1] = members[j]
+	end
+	redis.call('LPUSH', KEYS[2], unpack(chunk))
+	redis.call('ZREM', KEYS[1], unpack(chunk))
+end
+return {#members, now - tonumber(due[2])}
+`)
+
+// PromoteResult reports one promotion pass over a queue.
+type PromoteResult struct {
+	Promoted int
+	// Lag is how long past its due time the oldest promoted job waited
+	Lag time.Duration
+}
+
+// PromoteDelayedJobs atomically moves every due job from the queue's
+// delayed set to the back of the queue, in batches and in due order.
+// Concurrent callers never move a job twice.
+func (c *Client) PromoteDelayedJobs(ctx context.Context, queueName string) (*PromoteResult, error) {
+	result := &PromoteResult{}
+	for {
+		counts, err := promoteScript.Run(ctx, c.rdb, []string{delayedKey(queueName), queueName}, promoteBatchSize).Int64Slice()
+		if err != nil {
+			return result, err
+		}
+		if result.Promoted == 0 && counts[0] > 0 {
+			result.Lag = time.Duration(counts[1]) * time.Millisecond
+		}
+		result.Promoted += int(counts[0])
+		if counts[0] < promoteBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// DelayedStats describes the backlog of a delayed set.
+type DelayedStats struct {
+	Depth int64 `json:"depth"`
+	// Due counts jobs whose time has come but that are not yet promoted
+	Due int64 `json:"due"`
+	// Lag is how long the oldest due job has been waiting for promotion
+	Lag time.Duration `json:"lag_ns"`
+}
+
+func (c *Client) DelayedStats(ctx context.Context, queueName string) (*DelayedStats, error) {
+	key := delayedKey(queueName)
+	now := time.Now()
+
+	pipe := c.rdb.Pipeline()
+	depth := pipe.ZCard(ctx, key)
+	due := pipe.ZCount(ctx, key, "-inf", fmt.Sprintf("%d", now.UnixMilli()))
+	oldest := pipe.ZRangeWithScores(ctx, key, 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	stats := &DelayedStats{Depth: depth.Val(), Due: due.Val()}
+	if members := oldest.Val(); len(members) > 0 {
+		if dueAt := time.UnixMilli(int64(members[0].Score)); dueAt.Before(now) {
+			stats.Lag = now.Sub(dueAt)
+		}
+	}
+	return stats, nil
+}
+
+type PromoterConfig struct {
+	Interval time.Duration
+	// LeaderTTL bounds how long promotion stalls when the leader dies
+	LeaderTTL time.Duration
+}
+
+// PromoterStats are cumulative figures for the promoter in this process.
+type PromoterStats struct {
+	Leader        bool                    `json:"leader"`
+	Promoted      int64                   `json:"promoted"`
+	LastLag       time.Duration           `json:"last_lag_ns"`
+	LastRunAt     time.Time               `json:"last_run_at"`
+	LastError     string                  `json:"last_error,omitempty"`
+	QueueBacklogs map[string]DelayedStats `json:"queue_backlogs"`
+}
+
+// Promoter moves due delayed jobs onto their queues. Every replica may run
+// one; a leader lock keeps all but one idle, and the promotion script is
+// atomic, so a brief overlap during failover is harmless.
+type Promoter struct {
+	client *Client
+	queues []string
+	config PromoterConfig
+
+	mu    sync.Mutex
+	stats PromoterStats
+}
+
+func NewPromoter(client *Client, queueNames []string, config PromoterConfig) *Promoter {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.LeaderTTL <= 0 {
+		config.LeaderTTL = 5 * config.Interval
+	}
+	return &Promoter{
+		client: client,
+		queues: queueNames,
+		config: config,
+	}
+}
+
+// Run promotes due jobs every interval until ctx is cancelled.
+func (p *Promoter) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	var leader *Lock
+	defer func() {
+		if leader != nil {
+			_ = leader.Release(context.WithoutCancel(ctx))
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if leader == nil {
+			lock, err := p.client.AcquireLock(ctx, promoterLeaderKey, p.config.LeaderTTL)
+			if err != nil {
+				if !errors.Is(err, ErrLockNotHeld) && ctx.Err() == nil {
+					p.recordError(err)
+				}
+				p.setLeader(false)
+				continue
+			}
+			leader = lock
+		} else if err := leader.Refresh(ctx); err != nil {
+			leader = nil
+			p.setLeader(false)
+			if ctx.Err() == nil {
+				p.recordError(fmt.Errorf("lost promoter leadership: %w", err))
+			}
+			continue
+		}
+
+		p.setLeader(true)
+		p.promote(ctx)
+	}
+}
+
+func (p *Promoter) promote(ctx context.Context) {
+	for _, queueName := range p.queues {
+		result, err := p.client.PromoteDelayedJobs(ctx, queueName)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.recordError(fmt.Errorf("promote %s: %w", queueName, err))
+			}
+			continue
+		}
+
+		p.mu.Lock()
+		p.stats.Promoted += int64(result.Promoted)
+		if result.Promoted > 0 {
+			p.stats.LastLag = result.Lag
+		}
+		p.stats.LastRunAt = time.Now()
+		p.mu.Unlock()
+	}
+}
+
+// Stats returns promoter counters along with the current backlog of each queue.
+func (p *Promoter) Stats(ctx context.Context) (PromoterStats, error) {
+	backlogs := make(map[string]DelayedStats, len(p.queues))
+	for _, queueName := range p.queues {
+		stats, err := p.client.DelayedStats(ctx, queueName)
+		if err != nil {
+			return PromoterStats{}, err
+		}
+		backlogs[queueName] = *stats
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.QueueBacklogs = backlogs
+	return stats, nil
+}
+
+func (p *Promoter) setLeader(leader bool) {
+	p.mu.Lock()
+	p.stats.Leader = leader
+	p.mu.Unlock()
+}
+
+func (p *Promoter) recordError(err error) {
+	slog.Error("Delayed promoter", slog.Any("error", err))
+	p.mu.Lock()
+	p.stats.LastError = err.Error()
+	p.mu.Unlock()
+}
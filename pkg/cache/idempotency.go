@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrIdempotencyClaimLost is returned when a claim expired and was taken by
+// another request before its holder completed or released it.
+var ErrIdempotencyClaimLost = errors.New("idempotency claim lost")
+
+// IdempotencyRecord is what is stored under an idempotency key: first a
+// claim while the request runs, then its response.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	// Token identifies the claim, so only its holder can complete or
+	// release it
+	Token     string `json:"token,omitempty"`
+	Completed bool   `json:"completed"`
+	// ResponseUnavailable marks a request that completed without its
+	// response being stored
+	ResponseUnavailable bool   `json:"response_unavailable,omitempty"`
+	Status              int    `json:"status,omitempty"`
+	ContentType         string `json:"content_type,omitempty"`
+	Body                []byte `json:"body,omitempty"`
+}
+
+var claimIdempotencyScript = redis.NewScript(`
+local existing = redis.call('GET', KEYS[1])
+if existing then
+	return existing
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false
+`)
+
+// holdsClaim checks that the record under KEYS[1] carries the token in
+// ARGV[1].
+const holdsClaimFunc = `
+local function holdsClaim()
+	local current = redis.call('GET', KEYS[1])
+	if not current then
+		return false
+	end
+	local ok, record = pcall(cjson.decode, current)
+	return ok and type(record) == 'table' and record.token == ARGV[1]
+end
+`
+
+// ARGV: token, record, ttl ms
+var completeIdempotencyScript = redis.NewScript(holdsClaimFunc + `
+if not holdsClaim() then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// ARGV: token
+var releaseIdempotencyScript = redis.NewScript(holdsClaimFunc + `
+if not holdsClaim() then
+	return 0
+end
+return redis.call('DEL', KEYS[1])
+`)
+
+// ClaimIdempotencyKey claims key for a request with the given fingerprint
+// for up to ttl and returns the claim's token. If the key is already taken,
+// the existing record is returned instead and nothing changes.
+func (c *Client) ClaimIdempotencyKey(ctx context.Context, key, fingerprint string, ttl time.Duration) (string, *IdempotencyRecord, error) {
+	token := uuid.NewString()
+	claim, err := json.Marshal(IdempotencyRecord{Fingerprint: fingerprint, Token: token})
+	if err != nil {
+		return "", nil, err
+	}
+
+	existing, err := claimIdempotencyScript.Run(ctx, c.rdb, []string{key}, claim, ttl.Milliseconds()).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return token, nil, nil
+		}
+		return "", nil, err
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal([]byte(existing), &record); err != nil {
+		return "", nil, err
+	}
+	return "", &record, nil
+}
+
+// CompleteIdempotencyKey replaces the claim holding token with the response
+// of its request, kept for ttl.
+func (c *Client) CompleteIdempotencyKey(ctx context.Context, key, token string, record *IdempotencyRecord, ttl time.Duration) error {
+	record.Token = token
+	record.Completed = true
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	stored, err := completeIdempotencyScript.Run(ctx, c.rdb, []string{key}, token, data, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if stored == 0 {
+		return ErrIdempotencyClaimLost
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey drops the claim holding token so the request can be
+// retried. A claim that has since been taken by another request is left
+// alone.
+func (c *Client) ReleaseIdempotencyKey(ctx context.Context, key, token string) error {
+	released, err := releaseIdempotencyScript.Run(ctx, c.rdb, []string{key}, token).Int()
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return ErrIdempotencyClaimLost
+	}
+	return nil
+}
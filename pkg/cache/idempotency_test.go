@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyClaimIsHeldByToken(t *testing.T) {
+	client, prefix := testRedis(t)
+	ctx := context.Background()
+	key := prefix + ":key"
+
+	stale, existing, err := client.ClaimIdempotencyKey(ctx, key, "request", time.Minute)
+	if err != nil || existing != nil || stale == "" {
+		t.Fatalf("ClaimIdempotencyKey() = %q, %v, %v; want a fresh claim", stale, existing, err)
+	}
+
+	// The first request outlives its claim and a retry takes the key
+	if err := client.Del(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	token, existing, err := client.ClaimIdempotencyKey(ctx, key, "request", time.Minute)
+	if err != nil || existing != nil || token == "" || token == stale {
+		t.Fatalf("ClaimIdempotencyKey() after expiry = %q, %v, %v; want a new claim", token, existing, err)
+	}
+
+	late := &IdempotencyRecord{Fingerprint: "request", Status: 200}
+	if err := client.CompleteIdempotencyKey(ctx, key, stale, late, time.Minute); !errors.Is(err, ErrIdempotencyClaimLost) {
+		t.Errorf("CompleteIdempotencyKey() with an expired claim = %v, want %v", err, ErrIdempotencyClaimLost)
+	}
+	if err := client.ReleaseIdempotencyKey(ctx, key, stale); !errors.Is(err, ErrIdempotencyClaimLost) {
+		t.Errorf("ReleaseIdempotencyKey() with an expired claim = %v, want %v", err, ErrIdempotencyClaimLost)
+	}
+
+	_, existing, err = client.ClaimIdempotencyKey(ctx, key, "request", time.Minute)
+	if err != nil || existing == nil || existing.Completed || existing.Token != token {
+		t.Fatalf("record = %+v, %v; want the retry's claim untouched", existing, err)
+	}
+
+	response := &IdempotencyRecord{Fingerprint: "request", Status: 201, Body: []byte(`{"ok":true}`)}
+	if err := client.CompleteIdempotencyKey(ctx, key, token, response, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	_, existing, err = client.ClaimIdempotencyKey(ctx, key, "request", time.Minute)
+	if err != nil || existing == nil || !existing.Completed || existing.Status != 201 || string(existing.Body) != `{"ok":true}` {
+		t.Fatalf("record = %+v, %v; want the stored response", existing, err)
+	}
+}
+
+func TestReleaseIdempotencyKeyFreesClaim(t *testing.T) {
+	client, prefix := testRedis(t)
+	ctx := context.Background()
+	key := prefix + ":key"
+
+	token, _, err := client.ClaimIdempotencyKey(ctx, key, "request", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ReleaseIdempotencyKey(ctx, key, token); err != nil {
+		t.Fatal(err)
+	}
+
+	retry, existing, err := client.ClaimIdempotencyKey(ctx, key, "request", time.Minute)
+	if err != nil || existing != nil || retry == "" {
+		t.Fatalf("ClaimIdempotencyKey() after release = %q, %v, %v; want a fresh claim", retry, existing, err)
+	}
+}
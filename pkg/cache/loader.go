@@ -0,0 +1,465 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vmihailenco/msgpack/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound is returned by loaders for values that do not exist. With
+// negative caching enabled, GetOrLoad remembers the absence and keeps
+// returning ErrNotFound without calling the loader until it expires.
+var ErrNotFound = errors.New("cache: not found")
+
+// Codec turns cached values into bytes and back.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// msgpackCodec honours json struct tags, so types need no extra tags.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
+
+var (
+	JSONCodec    Codec = jsonCodec{}
+	MsgPackCodec Codec = msgpackCodec{}
+)
+
+// Fields of the hash holding a cached value
+const (
+	entryData     = "d"
+	entryNegative = "n"
+	entryVersions = "v"
+)
+
+type CacheConfig struct {
+	// Prefix namespaces every key, tag and channel of the cache
+	Prefix string
+	Codec  Codec
+	TTL    time.Duration
+	// Jitter shortens each TTL by a random fraction up to this value, so
+	// entries written together do not expire together
+	Jitter float64
+	// NegativeTTL is how long ErrNotFound is remembered; zero disables
+	NegativeTTL time.Duration
+	// L1Size caps the in-process layer in front of Redis; zero disables it
+	L1Size int
+	// L1TTL bounds how stale the in-process layer can be when an
+	// invalidation message is missed
+	L1TTL time.Duration
+}
+
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Prefix:      "cache:",
+		Codec:       JSONCodec,
+		TTL:         10 * time.Minute,
+		Jitter:      0.1,
+		NegativeTTL: 30 * time.Second,
+		L1Size:      10000,
+		L1TTL:       10 * time.Second,
+	}
+}
+
+// LoadOptions override the cache configuration for one call. Tags let
+// InvalidateTags drop the value along with everything else sharing a tag.
+type LoadOptions struct {
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	Codec       Codec
+	Tags        []string
+}
+
+type l1Entry struct {
+	data      []byte
+	negative  bool
+	tags      []string
+	expiresAt time.Time
+}
+
+// invalidation is broadcast so every replica drops its L1 copies.
+type invalidation struct {
+	Keys []string `json:"keys,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Cache is a cache-aside layer over Redis with an optional in-process L1.
+// A nil *Cache caches nothing, which suits tools running without Redis.
+//
+// Tags are versioned rather than listing their keys: an entry records the
+// versions of its tags as they were before loading, and InvalidateTags bumps
+// them, so entries written by loads racing an invalidation are stale on
+// arrival instead of outliving it.
+type Cache struct {
+	client *Client
+	config CacheConfig
+	group  singleflight.Group
+
+	mu sync.Mutex
+	l1 map[string]l1Entry
+	// generation counts the invalidations applied to l1, so a load that
+	// raced one does not put back what it dropped
+	generation uint64
+}
+
+func NewCache(client *Client, config CacheConfig) *Cache {
+	defaults := DefaultCacheConfig()
+	if config.Prefix == "" {
+		config.Prefix = defaults.Prefix
+	}
+	if config.Codec == nil {
+		config.Codec = defaults.Codec
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.Jitter < 0 || config.Jitter >= 1 {
+		config.Jitter = defaults.Jitter
+	}
+	if config.L1TTL <= 0 {
+		config.L1TTL = defaults.L1TTL
+	}
+	return &Cache{
+		client: client,
+		config: config,
+		l1:     make(map[string]l1Entry),
+	}
+}
+
+// GetOrLoad returns the cached value for key, calling load on a miss.
+// Concurrent misses for the same key in this process share one load. Redis
+// errors are logged and fall back to load, so the cache never becomes a hard
+// dependency. Values are decoded afresh for every caller, who may modify them.
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, options LoadOptions, load func(ctx context.Context) (T, error)) (T, error) {
+	if c == nil {
+		return load(ctx)
+	}
+
+	var value T
+	codec := c.config.Codec
+	if options.Codec != nil {
+		codec = options.Codec
+	}
+
+	fullKey := c.config.Prefix + key
+	if entry, ok := c.getL1(fullKey); ok {
+		return decodeEntry[T](codec, entry)
+	}
+
+	result, err, _ := c.group.Do(fullKey, func() (interface{}, error) {
+		// Shared by every waiting caller, so one caller giving up must not
+		// fail the others
+		loadCtx := context.WithoutCancel(ctx)
+		generation := c.l1Generation()
+
+		entry, versions, err := c.read(loadCtx, fullKey, options.Tags)
+		if err != nil {
+			c.logError(fmt.Errorf("read %s: %w", key, err))
+		}
+		if entry != nil {
+			c.setL1(fullKey, *entry, generation)
+			return *entry, nil
+		}
+
+		loaded, err := load(loadCtx)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		fresh := l1Entry{tags: options.Tags}
+		ttl := c.ttl(options.TTL)
+		if err != nil {
+			negativeTTL := c.config.NegativeTTL
+			if options.NegativeTTL != 0 {
+				negativeTTL = options.NegativeTTL
+			}
+			if negativeTTL <= 0 {
+				return nil, err
+			}
+			fresh.negative = true
+			ttl = negativeTTL
+		} else if fresh.data, err = codec.Marshal(loaded); err != nil {
+			return nil, fmt.Errorf("encode %s: %w", key, err)
+		}
+
+		if versions != nil {
+			if err := c.write(loadCtx, fullKey, fresh, versions, ttl); err != nil {
+				c.logError(fmt.Errorf("write %s: %w", key, err))
+			}
+		}
+		c.setL1(fullKey, fresh, generation)
+		return fresh, nil
+	})
+	if err != nil {
+		return value, err
+	}
+	return decodeEntry[T](codec, result.(l1Entry))
+}
+
+func decodeEntry[T any](codec Codec, entry l1Entry) (T, error) {
+	var value T
+	if entry.negative {
+		return value, ErrNotFound
+	}
+	if err := codec.Unmarshal(entry.data, &value); err != nil {
+		return value, fmt.Errorf("decode cached value: %w", err)
+	}
+	return value, nil
+}
+
+// read fetches the entry and the current versions of its tags in one round
+// trip. A nil entry is a miss, including one whose tags have moved on. The
+// versions are nil when Redis failed, in which case nothing is written back.
+func (c *Cache) read(ctx context.Context, key string, tags []string) (*l1Entry, []string, error) {
+	pipe := c.client.rdb.Pipeline()
+	get := pipe.HGetAll(ctx, key)
+	var tagVersions *redis.SliceCmd
+	if len(tags) > 0 {
+		tagVersions = pipe.MGet(ctx, c.tagKeys(tags)...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	versions := make([]string, len(tags))
+	if tagVersions != nil {
+		for i, version := range tagVersions.Val() {
+			if s, ok := version.(string); ok {
+				versions[i] = s
+			}
+		}
+	}
+
+	fields := get.Val()
+	if len(fields) == 0 || fields[entryVersions] != strings.Join(versions, ",") {
+		return nil, versions, nil
+	}
+	return &l1Entry{
+		data:     []byte(fields[entryData]),
+		negative: fields[entryNegative] == "1",
+		tags:     tags,
+	}, versions, nil
+}
+
+func (c *Cache) write(ctx context.Context, key string, entry l1Entry, versions []string, ttl time.Duration) error {
+	negative := "0"
+	if entry.negative {
+		negative = "1"
+	}
+
+	pipe := c.client.rdb.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key,
+		entryData, entry.data,
+		entryNegative, negative,
+		entryVersions, strings.Join(versions, ","),
+	)
+	pipe.PExpire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Invalidate drops the given keys on every replica.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	if c == nil || len(keys) == 0 {
+		return nil
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = c.config.Prefix + key
+	}
+	if err := c.client.rdb.Del(ctx, fullKeys...).Err(); err != nil {
+		return err
+	}
+	return c.broadcast(ctx, invalidation{Keys: fullKeys})
+}
+
+// InvalidateTags drops every value cached with any of the tags on every
+// replica.
+func (c *Cache) InvalidateTags(ctx context.Context, tags ...string) error {
+	if c == nil || len(tags) == 0 {
+		return nil
+	}
+
+	pipe := c.client.rdb.Pipeline()
+	for _, tagKey := range c.tagKeys(tags) {
+		pipe.Incr(ctx, tagKey)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	return c.broadcast(ctx, invalidation{Tags: tags})
+}
+
+// Run applies invalidations broadcast by other replicas to the L1 layer
+// until ctx is cancelled. Messages missed while Redis is unreachable, or
+// without Run at all, leave L1 entries stale for up to L1TTL.
+func (c *Cache) Run(ctx context.Context) {
+	if c.config.L1Size <= 0 {
+		return
+	}
+
+	subscription := c.client.rdb.Subscribe(ctx, c.channel())
+	defer subscription.Close()
+
+	messages := subscription.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var received invalidation
+			if err := json.Unmarshal([]byte(message.Payload), &received); err != nil {
+				c.logError(fmt.Errorf("invalid invalidation message: %w", err))
+				continue
+			}
+			c.dropL1(received)
+		}
+	}
+}
+
+func (c *Cache) broadcast(ctx context.Context, message invalidation) error {
+	// Apply locally first, so this replica reads its own invalidation even
+	// when the broadcast fails
+	c.dropL1(message)
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return c.client.rdb.Publish(ctx, c.channel(), payload).Err()
+}
+
+func (c *Cache) getL1(key string) (l1Entry, bool) {
+	if c.config.L1Size <= 0 {
+		return l1Entry{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.l1[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return l1Entry{}, false
+	}
+	return entry, true
+}
+
+func (c *Cache) l1Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// setL1 stores entry unless an invalidation was applied since generation
+// was read, since the entry may predate it; the next call goes to Redis,
+// where tag versions tell stale entries apart.
+func (c *Cache) setL1(key string, entry l1Entry, generation uint64) {
+	if c.config.L1Size <= 0 {
+		return
+	}
+
+	entry.expiresAt = time.Now().Add(c.config.L1TTL)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	if _, exists := c.l1[key]; !exists && len(c.l1) >= c.config.L1Size {
+		// Map iteration order is random, which makes this random eviction
+		for evicted := range c.l1 {
+			delete(c.l1, evicted)
+			break
+		}
+	}
+	c.l1[key] = entry
+}
+
+func (c *Cache) dropL1(message invalidation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, key := range message.Keys {
+		delete(c.l1, key)
+	}
+	if len(message.Tags) == 0 {
+		return
+	}
+	for key, entry := range c.l1 {
+		for _, tag := range entry.tags {
+			if containsString(message.Tags, tag) {
+				delete(c.l1, key)
+				break
+			}
+		}
+	}
+}
+
+// ttl applies jitter to the TTL of a call, or to the default one.
+func (c *Cache) ttl(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		ttl = c.config.TTL
+	}
+	return ttl - time.Duration(rand.Float64()*c.config.Jitter*float64(ttl))
+}
+
+func (c *Cache) tagKeys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = c.config.Prefix + "tag:" + tag
+	}
+	return keys
+}
+
+func (c *Cache) channel() string {
+	return c.config.Prefix + "invalidations"
+}
+
+func (c *Cache) logError(err error) {
+	slog.Error("Cache", slog.Any("error", err))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
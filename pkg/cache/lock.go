@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockNotHeld is returned when a lock could not be acquired, or has
+// expired or been taken over by another holder.
+var ErrLockNotHeld = errors.New("lock not held")
+
+// Takes the lock and hands out the next fencing token. The token counter
+// never expires so tokens keep increasing across holders.
+//
+// KEYS: lock, fence
+// ARGV: token, ttl ms
+var acquireLockScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return redis.call('INCR', KEYS[2])
+end
+return 0
+`)
+
+// KEYS: lock
+// ARGV: token, ttl ms
+var refreshLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// KEYS: lock
+// ARGV: token
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock is a Redis lock held until it is released or its TTL passes
+// without a refresh.
+type Lock struct {
+	client *Client
+	key    string
+	token  string
+	ttl    time.Duration
+	fence  int64
+}
+
+// AcquireLock takes key for ttl, returning ErrLockNotHeld if someone else holds it.
+func (c *Client) AcquireLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	token := uuid.NewString()
+	fence, err := acquireLockScript.Run(ctx, c.rdb, []string{key, key + ":fence"}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, err
+	}
+	if fence == 0 {
+		return nil, ErrLockNotHeld
+	}
+	return &Lock{client: c, key: key, token: token, ttl: ttl, fence: fence}, nil
+}
+
+func (l *Lock) Key() string {
+	return l.key
+}
+
+// Fence is the fencing token of this acquisition. Every acquisition of the
+// same key gets a larger one, so storage written under the lock can reject
+// writes from a holder whose lock has since expired.
+func (l *Lock) Fence() int64 {
+	return l.fence
+}
+
+// Refresh extends the lock by its TTL if it is still ours.
+func (l *Lock) Refresh(ctx context.Context) error {
+	refreshed, err := refreshLockScript.Run(ctx, l.client.rdb, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if refreshed == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Release gives the lock up if it is still ours.
+func (l *Lock) Release(ctx context.Context) error {
+	released, err := releaseLockScript.Run(ctx, l.client.rdb, []string{l.key}, l.token).Int()
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// KeepAlive refreshes the lock every third of its TTL until ctx is done or
+// the lock is lost. The returned context is cancelled in either case so
+// work guarded by the lock stops once it may no longer be exclusive.
+func (l *Lock) KeepAlive(ctx context.Context) (context.Context, context.CancelFunc) {
+	held, cancel := context.WithCancel(ctx)
+
+	go func() {
+		defer cancel()
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		refreshedAt := time.Now()
+		for {
+			select {
+			case <-held.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := l.Refresh(held)
+			if err == nil {
+				refreshedAt = time.Now()
+				continue
+			}
+			// Transient errors are retried until the lock would have expired
+			if errors.Is(err, ErrLockNotHeld) || time.Since(refreshedAt) >= l.ttl {
+				return
+			}
+		}
+	}()
+
+	return held, cancel
+}
@@ -0,0 +1,583 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// A reliable queue keeps every job in Redis until it is acknowledged:
+//
+//	<queue>                      pending jobs, pushed left and taken from the right
+//	<queue>:processing:<worker>  jobs a worker has taken but not yet acked
+//	<queue>:leases               job ID → visibility deadline (ms, Redis clock)
+//	<queue>:owners               job ID → worker holding it
+//	<queue>:leased               job ID → raw job, so the reaper can find it
+//	<queue>:deliveries           job ID → delivery count
+//	<queue>:workers              worker → last heartbeat (ms, Redis clock)
+//
+// Jobs whose lease expires, or whose worker stops heartbeating, are moved
+// back to <queue> by Reap. Delivery is therefore at-least-once.
+
+// ErrLeaseLost is returned when a job is acked or nacked after its lease
+// expired and the reaper already handed it to another worker.
+var ErrLeaseLost = errors.New("job lease lost")
+
+const reapBatchSize = 100
+
+// Job is the envelope stored in a reliable queue. Every job carries an ID,
+// which its lease is keyed by.
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+}
+
+// Delivery is a job taken by a consumer. Attempt counts deliveries,
+// including ones lost to crashed workers.
+type Delivery struct {
+	Job
+	Queue   string
+	Attempt int
+
+	raw string
+}
+
+// Raw returns the exact bytes stored in Redis for the job.
+func (d *Delivery) Raw() string {
+	return d.raw
+}
+
+func processingKey(queueName, workerID string) string {
+	return queueName + ":processing:" + workerID
+}
+
+// leaseKeys returns the keys shared by the lease scripts, in the order
+// they expect them after any script-specific keys.
+func leaseKeys(queueName string) []string {
+	return []string{queueName + ":leases", queueName + ":owners", queueName + ":leased"}
+}
+
+// nowMillis reads the Redis server clock so replicas with skewed clocks
+// agree on lease deadlines.
+const nowMillis = `
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+`
+
+// jobIDFunc reads the ID of a raw job. Leases taken by older builds were
+// keyed by the raw job itself, which is what it falls back to.
+const jobIDFunc = `
+local function jobID(raw)
+	local ok, job = pcall(cjson.decode, raw)
+	if ok and type(job) == 'table' and type(job.id) == 'string' and job.id ~= '' then
+		return job.id
+	end
+	return raw
+end
+`
+
+// releaseLease drops the lease bookkeeping of a job ID.
+const releaseLeaseFunc = `
+local function releaseLease(leases, owners, leased, id)
+	redis.call('ZREM', leases, id)
+	redis.call('HDEL', owners, id)
+	redis.call('HDEL', leased, id)
+end
+`
+
+// A payload pushed bare by an older build is swapped in the processing list
+// for the envelope Receive wrapped it in.
+//
+// KEYS: processing, leases, owners, leased, deliveries, workers
+// ARGV: raw, worker, visibility ms, job ID, bare payload it replaces
+var leaseScript = redis.NewScript(nowMillis + `
+if ARGV[5] ~= '' then
+	redis.call('LREM', KEYS[1], 1, ARGV[5])
+	redis.call('LPUSH', KEYS[1], ARGV[1])
+end
+redis.call('ZADD', KEYS[2], now + tonumber(ARGV[3]), ARGV[4])
+redis.call('HSET', KEYS[3], ARGV[4], ARGV[2])
+redis.call('HSET', KEYS[4], ARGV[4], ARGV[1])
+redis.call('ZADD', KEYS[6], now, ARGV[2])
+return redis.call('HINCRBY', KEYS[5], ARGV[4], 1)
+`)
+
+// KEYS: processing, leases, owners, leased, deliveries
+// ARGV: raw, job ID
+var ackScript = redis.NewScript(releaseLeaseFunc + `
+local removed = redis.call('LREM', KEYS[1], -1, ARGV[1])
+if removed == 0 then
+	return 0
+end
+releaseLease(KEYS[2], KEYS[3], KEYS[4], ARGV[2])
+redis.call('HDEL', KEYS[5], ARGV[2])
+return removed
+`)
+
+// KEYS: processing, leases, owners, leased, queue, delayed
+// ARGV: raw, job ID, delayed score (empty to requeue immediately)
+var nackScript = redis.NewScript(releaseLeaseFunc + `
+local removed = redis.call('LREM', KEYS[1], -1, ARGV[1])
+if removed == 0 then
+	return 0
+end
+releaseLease(KEYS[2], KEYS[3], KEYS[4], ARGV[2])
+if ARGV[3] == '' then
+	redis.call('LPUSH', KEYS[5], ARGV[1])
+else
+	redis.call('ZADD', KEYS[6], ARGV[3], ARGV[1])
+end
+return removed
+`)
+
+// KEYS: leases, owners
+// ARGV: job ID, worker, visibility ms
+var extendScript = redis.NewScript(nowMillis + `
+if redis.call('HGET', KEYS[2], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+return redis.call('ZADD', KEYS[1], 'XX', 'CH', now + tonumber(ARGV[3]), ARGV[1])
+`)
+
+// KEYS: workers
+// ARGV: worker
+var heartbeatScript = redis.NewScript(nowMillis + `
+redis.call('ZADD', KEYS[1], now, ARGV[1])
+return now
+`)
+
+// Processing lists are named from the owner stored in the script, so the
+// reaper scripts assume a single Redis node rather than a cluster.
+//
+// KEYS: leases, owners, leased, queue
+// ARGV: processing key prefix, batch size
+var reapExpiredScript = redis.NewScript(nowMillis + releaseLeaseFunc + `
+local expired = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', now, 'LIMIT', 0, tonumber(ARGV[2]))
+local recovered = 0
+for _, id in ipairs(expired) do
+	local owner = redis.call('HGET', KEYS[2], id)
+	local raw = redis.call('HGET', KEYS[3], id) or id
+	local present = 1
+	if owner then
+		present = redis.call('LREM', ARGV[1] .. owner, -1, raw)
+	end
+	releaseLease(KEYS[1], KEYS[2], KEYS[3], id)
+	if present > 0 then
+		redis.call('RPUSH', KEYS[4], raw)
+		recovered = recovered + 1
+	end
+end
+return {#expired, recovered}
+`)
+
+// KEYS: workers, leases, owners, leased, queue
+// ARGV: processing key prefix, dead after ms
+var reapWorkersScript = redis.NewScript(nowMillis + jobIDFunc + releaseLeaseFunc + `
+local dead = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', now - tonumber(ARGV[2]))
+local recovered = 0
+for _, worker in ipairs(dead) do
+	local processing = ARGV[1] .. worker
+	while true do
+		local raw = redis.call('RPOP', processing)
+		if not raw then
+			break
+		end
+		releaseLease(KEYS[2], KEYS[3], KEYS[4], jobID(raw))
+		redis.call('RPUSH', KEYS[5], raw)
+		recovered = recovered + 1
+	end
+	redis.call('ZREM', KEYS[1], worker)
+end
+return {#dead, recovered}
+`)
+
+// KEYS: processing, leases, owners, leased, queue, workers
+// ARGV: worker
+var releaseScript = redis.NewScript(jobIDFunc + releaseLeaseFunc + `
+local released = 0
+while true do
+	local raw = redis.call('RPOP', KEYS[1])
+	if not raw then
+		break
+	end
+	releaseLease(KEYS[2], KEYS[3], KEYS[4], jobID(raw))
+	redis.call('RPUSH', KEYS[5], raw)
+	released = released + 1
+end
+redis.call('ZREM', KEYS[6], ARGV[1])
+return released
+`)
+
+// EnqueueJob wraps payload in a Job envelope with a unique ID and pushes it
+// onto a reliable queue.
+func (c *Client) EnqueueJob(ctx context.Context, queueName, jobType string, payload interface{}) (*Job, error) {
+	job, raw, err := newJob(jobType, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.rdb.LPush(ctx, queueName, raw).Err(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// EnqueueJobWithDelay schedules a job on the queue's delayed set.
+func (c *Client) EnqueueJobWithDelay(ctx context.Context, queueName, jobType string, payload interface{}, delay time.Duration) (*Job, error) {
+	job, raw, err := newJob(jobType, payload)
+	if err != nil {
+		return nil, err
+	}
+	err = c.rdb.ZAdd(ctx, delayedKey(queueName), redis.Z{
+		Score:  delayedScore(time.Now().Add(delay)),
+		Member: raw,
+	}).Err()
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func newJob(jobType string, payload interface{}) (*Job, []byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+	return wrapJob(jobType, data)
+}
+
+// wrapJob puts an encoded payload in an envelope with a fresh ID.
+func wrapJob(jobType string, data json.RawMessage) (*Job, []byte, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job := &Job{
+		ID:         id.String(),
+		Type:       jobType,
+		Payload:    data,
+		EnqueuedAt: time.Now().UTC(),
+	}
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, raw, nil
+}
+
+// Consumer takes jobs from one reliable queue on behalf of one worker.
+type Consumer struct {
+	client     *Client
+	queue      string
+	workerID   string
+	visibility time.Duration
+}
+
+// NewConsumer creates a consumer holding leases of the given visibility
+// timeout. An empty workerID generates one from the host name and PID.
+func (c *Client) NewConsumer(queueName, workerID string, visibility time.Duration) *Consumer {
+	if workerID == "" {
+		workerID = NewWorkerID()
+	}
+	return &Consumer{
+		client:     c,
+		queue:      queueName,
+		workerID:   workerID,
+		visibility: visibility,
+	}
+}
+
+// NewWorkerID returns an identifier unique to this process and call.
+func NewWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	host = strings.ReplaceAll(host, ":", "-")
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
+
+func (c *Consumer) WorkerID() string {
+	return c.workerID
+}
+
+func (c *Consumer) Queue() string {
+	return c.queue
+}
+
+// Receive waits up to wait for a job and leases it for the visibility
+// timeout. It returns nil, nil when the wait elapses with no job.
+func (c *Consumer) Receive(ctx context.Context, wait time.Duration) (*Delivery, error) {
+	processing := processingKey(c.queue, c.workerID)
+	raw, err := c.client.rdb.BLMove(ctx, c.queue, processing, "RIGHT", "LEFT", wait).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	delivery := &Delivery{Queue: c.queue, raw: raw}
+	bare := ""
+	if err := json.Unmarshal([]byte(raw), &delivery.Job); err != nil || delivery.Job.Payload == nil || delivery.ID == "" {
+		// Pushed bare by an older build; the envelope replaces it from now on
+		job, wrapped, err := wrapJob("", rawJob(raw))
+		if err != nil {
+			_ = c.client.rdb.LMove(context.WithoutCancel(ctx), processing, c.queue, "LEFT", "RIGHT").Err()
+			return nil, err
+		}
+		delivery.Job, delivery.raw, bare = *job, string(wrapped), raw
+	}
+
+	keys := append([]string{processing}, leaseKeys(c.queue)...)
+	attempt, err := leaseScript.Run(ctx, c.client.rdb,
+		append(keys, c.queue+":deliveries", c.queue+":workers"),
+		delivery.raw, c.workerID, c.visibility.Milliseconds(), delivery.ID, bare,
+	).Int()
+	if err != nil {
+		// Without a lease only the dead-worker sweep would find the job, so
+		// try to hand it straight back
+		_ = c.client.rdb.LMove(context.WithoutCancel(ctx), processing, c.queue, "LEFT", "RIGHT").Err()
+		return nil, fmt.Errorf("failed to lease job: %w", err)
+	}
+	delivery.Attempt = attempt
+
+	return delivery, nil
+}
+
+// Ack removes a finished job from the queue for good.
+func (c *Consumer) Ack(ctx context.Context, delivery *Delivery) error {
+	keys := append([]string{processingKey(c.queue, c.workerID)}, leaseKeys(c.queue)...)
+	removed, err := ackScript.Run(ctx, c.client.rdb,
+		append(keys, c.queue+":deliveries"),
+		delivery.raw, delivery.ID,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Nack returns a job to the queue, immediately when delay is zero or via
+// the delayed set otherwise.
+func (c *Consumer) Nack(ctx context.Context, delivery *Delivery, delay time.Duration) error {
+	score := ""
+	if delay > 0 {
+		score = fmt.Sprintf("%f", delayedScore(time.Now().Add(delay)))
+	}
+
+	keys := append([]string{processingKey(c.queue, c.workerID)}, leaseKeys(c.queue)...)
+	removed, err := nackScript.Run(ctx, c.client.rdb,
+		append(keys, c.queue, delayedKey(c.queue)),
+		delivery.raw, delivery.ID, score,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Extend pushes the lease deadline of a long-running job out by another
+// visibility timeout, provided the worker still holds the lease.
+func (c *Consumer) Extend(ctx context.Context, delivery *Delivery) error {
+	changed, err := extendScript.Run(ctx, c.client.rdb,
+		[]string{c.queue + ":leases", c.queue + ":owners"},
+		delivery.ID, c.workerID, c.visibility.Milliseconds(),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if changed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Heartbeat marks the worker as alive; Reap recovers the jobs of workers
+// that stop calling it.
+func (c *Consumer) Heartbeat(ctx context.Context) error {
+	return heartbeatScript.Run(ctx, c.client.rdb, []string{c.queue + ":workers"}, c.workerID).Err()
+}
+
+// RunHeartbeat calls Heartbeat every interval until ctx is cancelled.
+func (c *Consumer) RunHeartbeat(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Heartbeat(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("Queue: heartbeat failed", slog.String("queue", c.queue), slog.String("worker", c.workerID), slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close hands any jobs still held by the worker back to the queue and
+// deregisters it. Call it after in-flight jobs have been acked or nacked.
+func (c *Consumer) Close(ctx context.Context) (int, error) {
+	keys := append([]string{processingKey(c.queue, c.workerID)}, leaseKeys(c.queue)...)
+	return releaseScript.Run(ctx, c.client.rdb,
+		append(keys, c.queue, c.queue+":workers"),
+		c.workerID,
+	).Int()
+}
+
+type ReapResult struct {
+	ExpiredLeases int
+	DeadWorkers   int
+	Recovered     int
+}
+
+// Reap returns jobs with expired leases, and every job held by workers
+// silent for longer than deadAfter, to the queue.
+func (c *Client) Reap(ctx context.Context, queueName string, deadAfter time.Duration) (*ReapResult, error) {
+	result := &ReapResult{}
+	prefix := processingKey(queueName, "")
+
+	for {
+		counts, err := reapExpiredScript.Run(ctx, c.rdb,
+			append(leaseKeys(queueName), queueName),
+			prefix, reapBatchSize,
+		).Int64Slice()
+		if err != nil {
+			return result, err
+		}
+		result.ExpiredLeases += int(counts[0])
+		result.Recovered += int(counts[1])
+		if counts[0] < reapBatchSize {
+			break
+		}
+	}
+
+	keys := append([]string{queueName + ":workers"}, leaseKeys(queueName)...)
+	counts, err := reapWorkersScript.Run(ctx, c.rdb,
+		append(keys, queueName),
+		prefix, deadAfter.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return result, err
+	}
+	result.DeadWorkers = int(counts[0])
+	result.Recovered += int(counts[1])
+
+	return result, nil
+}
+
+// RunReaper reaps the given queues every interval until ctx is cancelled.
+// Reaping is idempotent, so every replica may run it.
+func (c *Client) RunReaper(ctx context.Context, queueNames []string, interval, deadAfter time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, queueName := range queueNames {
+			result, err := c.Reap(ctx, queueName, deadAfter)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Error("Queue: reap failed", slog.String("queue", queueName), slog.Any("error", err))
+				}
+				continue
+			}
+			if result.Recovered > 0 {
+				slog.Info("Queue: recovered jobs", slog.String("queue", queueName), slog.Int("recovered", result.Recovered),
+					slog.Int("expired_leases", result.ExpiredLeases), slog.Int("dead_workers", result.DeadWorkers))
+			}
+		}
+	}
+}
+
+// maxDeadLetters bounds each dead-letter list; the oldest entries are dropped.
+const maxDeadLetters = 10000
+
+// DeadLetter records a job that failed permanently. Job holds the original
+// envelope so the job can be requeued unchanged.
+type DeadLetter struct {
+	Job      json.RawMessage `json:"job"`
+	Queue    string          `json:"queue"`
+	Error    string          `json:"error"`
+	Attempts int             `json:"attempts"`
+	WorkerID string          `json:"worker_id"`
+	FailedAt time.Time       `json:"failed_at"`
+}
+
+func deadLetterKey(queueName string) string {
+	return queueName + ":dead"
+}
+
+// KEYS: processing, leases, owners, leased, deliveries, dead
+// ARGV: raw, job ID, dead letter, max dead letters
+var deadLetterScript = redis.NewScript(releaseLeaseFunc + `
+local removed = redis.call('LREM', KEYS[1], -1, ARGV[1])
+if removed == 0 then
+	return 0
+end
+releaseLease(KEYS[2], KEYS[3], KEYS[4], ARGV[2])
+redis.call('HDEL', KEYS[5], ARGV[2])
+redis.call('LPUSH', KEYS[6], ARGV[3])
+redis.call('LTRIM', KEYS[6], 0, tonumber(ARGV[4]) - 1)
+return removed
+`)
+
+// DeadLetter moves a job that will not succeed to the queue's dead-letter
+// list together with the reason it failed.
+func (c *Consumer) DeadLetter(ctx context.Context, delivery *Delivery, reason error) error {
+	entry, err := json.Marshal(DeadLetter{
+		Job:      rawJob(delivery.raw),
+		Queue:    c.queue,
+		Error:    reason.Error(),
+		Attempts: delivery.Attempt,
+		WorkerID: c.workerID,
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	keys := append([]string{processingKey(c.queue, c.workerID)}, leaseKeys(c.queue)...)
+	removed, err := deadLetterScript.Run(ctx, c.client.rdb,
+		append(keys, c.queue+":deliveries", deadLetterKey(c.queue)),
+		delivery.raw, delivery.ID, entry, maxDeadLetters,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// rawJob keeps JSON envelopes as nested JSON and quotes anything else.
+func rawJob(raw string) json.RawMessage {
+	if json.Valid([]byte(raw)) {
+		return json.RawMessage(raw)
+	}
+	quoted, _ := json.Marshal(raw)
+	return quoted
+}
@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrDeadLetterNotFound is returned when a dead letter was already
+// requeued, deleted or trimmed.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+const deadLetterScanBatch = 500
+
+// QueueStats is a snapshot of a reliable queue.
+type QueueStats struct {
+	Name    string `json:"name"`
+	Pending int64  `json:"pending"`
+	Delayed int64  `json:"delayed"`
+	// DelayedDue counts delayed jobs that are due but not yet promoted
+	DelayedDue  int64 `json:"delayed_due"`
+	Processing  int64 `json:"processing"`
+	Workers     int64 `json:"workers"`
+	DeadLetters int64 `json:"dead_letters"`
+	// OldestAge is how long the next job to be taken has been waiting; zero
+	// when the queue is empty or the job carries no enqueue time
+	OldestAge time.Duration `json:"oldest_age_ns"`
+}
+
+// QueueStats reads the sizes of every list and set backing a queue. Counts
+// are read in one round trip but are not an atomic snapshot.
+func (c *Client) QueueStats(ctx context.Context, queueName string) (*QueueStats, error) {
+	now := time.Now()
+
+	pipe := c.rdb.Pipeline()
+	pending := pipe.LLen(ctx, queueName)
+	next := pipe.LIndex(ctx, queueName, -1)
+	delayed := pipe.ZCard(ctx, delayedKey(queueName))
+	due := pipe.ZCount(ctx, delayedKey(queueName), "-inf", fmt.Sprintf("%d", now.UnixMilli()))
+	processing := pipe.ZCard(ctx, queueName+":leases")
+	workers := pipe.ZCard(ctx, queueName+":workers")
+	dead := pipe.LLen(ctx, deadLetterKey(queueName))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	stats := &QueueStats{
+		Name:        queueName,
+		Pending:     pending.Val(),
+		Delayed:     delayed.Val(),
+		DelayedDue:  due.Val(),
+		Processing:  processing.Val(),
+		Workers:     workers.Val(),
+		DeadLetters: dead.Val(),
+	}
+
+	var job Job
+	if raw := next.Val(); raw != "" && json.Unmarshal([]byte(raw), &job) == nil && !job.EnqueuedAt.IsZero() {
+		stats.OldestAge = now.Sub(job.EnqueuedAt)
+	}
+	return stats, nil
+}
+
+// DeadLetterEntry is a dead letter together with the ID used to requeue or
+// delete it. The ID is a hash of the stored entry, so it stays valid while
+// newer failures are pushed in front of it.
+type DeadLetterEntry struct {
+	ID string `json:"id"`
+	DeadLetter
+}
+
+// ListDeadLetters returns a page of a queue's dead letters, newest first,
+// and the total number of dead letters.
+func (c *Client) ListDeadLetters(ctx context.Context, queueName string, offset, limit int) ([]DeadLetterEntry, int64, error) {
+	key := deadLetterKey(queueName)
+
+	pipe := c.rdb.Pipeline()
+	total := pipe.LLen(ctx, key)
+	raws := pipe.LRange(ctx, key, int64(offset), int64(offset+limit-1))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, err
+	}
+
+	entries := make([]DeadLetterEntry, 0, len(raws.Val()))
+	for _, raw := range raws.Val() {
+		entries = append(entries, newDeadLetterEntry(raw))
+	}
+	return entries, total.Val(), nil
+}
+
+// KEYS: dead, queue
+// ARGV: dead letter, raw job
+var requeueDeadLetterScript = redis.NewScript(`
+local removed = redis.call('LREM', KEYS[1], 1, ARGV[1])
+if removed == 0 then
+	return 0
+end
+redis.call('LPUSH', KEYS[2], ARGV[2])
+return removed
+`)
+
+// RequeueDeadLetter pushes the original job of a dead letter back onto its
+// queue, with a fresh attempt count, and removes the dead letter.
+func (c *Client) RequeueDeadLetter(ctx context.Context, queueName, id string) (*DeadLetterEntry, error) {
+	raw, entry, err := c.findDeadLetter(ctx, queueName, id)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := requeueDeadLetterScript.Run(ctx, c.rdb,
+		[]string{deadLetterKey(queueName), queueName},
+		raw, originalJob(entry.Job),
+	).Int()
+	if err != nil {
+		return nil, err
+	}
+	if removed == 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+	return entry, nil
+}
+
+func (c *Client) DeleteDeadLetter(ctx context.Context, queueName, id string) error {
+	raw, _, err := c.findDeadLetter(ctx, queueName, id)
+	if err != nil {
+		return err
+	}
+
+	removed, err := c.rdb.LRem(ctx, deadLetterKey(queueName), 1, raw).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+func (c *Client) findDeadLetter(ctx context.Context, queueName, id string) (string, *DeadLetterEntry, error) {
+	key := deadLetterKey(queueName)
+	for start := int64(0); ; start += deadLetterScanBatch {
+		raws, err := c.rdb.LRange(ctx, key, start, start+deadLetterScanBatch-1).Result()
+		if err != nil {
+			return "", nil, err
+		}
+		for _, raw := range raws {
+			if deadLetterID(raw) == id {
+				entry := newDeadLetterEntry(raw)
+				return raw, &entry, nil
+			}
+		}
+		if len(raws) < deadLetterScanBatch {
+			return "", nil, ErrDeadLetterNotFound
+		}
+	}
+}
+
+// PurgeResult counts the jobs removed by PurgeQueue.
+type PurgeResult struct {
+	Pending     int64 `json:"pending"`
+	Delayed     int64 `json:"delayed"`
+	DeadLetters int64 `json:"dead_letters"`
+}
+
+// PurgeQueue drops every pending job of a queue and, optionally, its
+// delayed and dead-letter jobs. Jobs being processed are left alone.
+func (c *Client) PurgeQueue(ctx context.Context, queueName string, delayed, deadLetters bool) (*PurgeResult, error) {
+	keys := []string{queueName}
+	if delayed {
+		keys = append(keys, delayedKey(queueName))
+	}
+	if deadLetters {
+		keys = append(keys, deadLetterKey(queueName))
+	}
+
+	pipe := c.rdb.TxPipeline()
+	pending := pipe.LLen(ctx, queueName)
+	delayedCount := pipe.ZCard(ctx, delayedKey(queueName))
+	deadCount := pipe.LLen(ctx, deadLetterKey(queueName))
+	pipe.Del(ctx, keys...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &PurgeResult{Pending: pending.Val()}
+	if delayed {
+		result.Delayed = delayedCount.Val()
+	}
+	if deadLetters {
+		result.DeadLetters = deadCount.Val()
+	}
+	return result, nil
+}
+
+func newDeadLetterEntry(raw string) DeadLetterEntry {
+	entry := DeadLetterEntry{ID: deadLetterID(raw)}
+	if err := json.Unmarshal([]byte(raw), &entry.DeadLetter); err != nil {
+		entry.Job = rawJob(raw)
+	}
+	return entry
+}
+
+func deadLetterID(raw string) string {
+	sum := sha1.Sum([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// originalJob undoes rawJob: payloads that were not JSON were stored quoted.
+func originalJob(job json.RawMessage) string {
+	var quoted string
+	if json.Unmarshal(job, &quoted) == nil {
+		return quoted
+	}
+	return string(job)
+}
@@ -0,0 +1,310 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// testRedisAddrEnv names a Redis the tests may write to, e.g.
+// "localhost:6379". Every test works under its own key prefix and deletes it.
+const testRedisAddrEnv = "TEST_REDIS_ADDR"
+
+type lessonCompleted struct {
+	LessonID string `json:"lesson_id"`
+}
+
+func TestIdenticalPayloadsGetSeparateLeases(t *testing.T) {
+	client, queue := testRedis(t)
+	ctx := context.Background()
+
+	payload := lessonCompleted{LessonID: "lesson-1"}
+	for range 2 {
+		if err := client.Enqueue(ctx, queue, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := client.NewConsumer(queue, "first", time.Minute)
+	second := client.NewConsumer(queue, "second", time.Minute)
+	a := receive(t, first)
+	b := receive(t, second)
+	if a.ID == "" || a.ID == b.ID {
+		t.Fatalf("job IDs = %q and %q, want two distinct IDs", a.ID, b.ID)
+	}
+	if string(a.Payload) != `{"lesson_id":"lesson-1"}` {
+		t.Errorf("payload = %s, want the enqueued data", a.Payload)
+	}
+
+	if err := first.Ack(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if owner := client.rdb.HGet(ctx, queue+":owners", b.ID).Val(); owner != "second" {
+		t.Errorf("owner of the second job = %q after acking the first, want %q", owner, "second")
+	}
+	if err := second.Extend(ctx, b); err != nil {
+		t.Fatalf("Extend() of the second job = %v", err)
+	}
+	if err := second.Ack(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	assertNoLeases(t, client, queue)
+}
+
+func TestBarePayloadIsWrappedOnReceive(t *testing.T) {
+	client, queue := testRedis(t)
+	ctx := context.Background()
+
+	// As pushed by builds that predate the envelope
+	if err := client.LPush(ctx, queue, `{"lesson_id":"lesson-1"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := client.NewConsumer(queue, "worker", time.Minute)
+	delivery := receive(t, consumer)
+	if delivery.ID == "" {
+		t.Fatal("bare payload was delivered without an ID")
+	}
+	if string(delivery.Payload) != `{"lesson_id":"lesson-1"}` {
+		t.Errorf("payload = %s, want the bare payload", delivery.Payload)
+	}
+
+	held := client.rdb.LRange(ctx, processingKey(queue, "worker"), 0, -1).Val()
+	if len(held) != 1 || held[0] != delivery.Raw() {
+		t.Errorf("processing list = %q, want only the wrapped job", held)
+	}
+
+	if err := consumer.Ack(ctx, delivery); err != nil {
+		t.Fatal(err)
+	}
+	assertNoLeases(t, client, queue)
+}
+
+func TestExpiredLeaseIsReaped(t *testing.T) {
+	client, queue := testRedis(t)
+	ctx := context.Background()
+
+	if _, err := client.EnqueueJob(ctx, queue, "test", lessonCompleted{LessonID: "lesson-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	slow := client.NewConsumer(queue, "slow", 50*time.Millisecond)
+	delivery := receive(t, slow)
+	time.Sleep(100 * time.Millisecond)
+
+	// The worker is alive, only its lease ran out
+	result, err := client.Reap(ctx, queue, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExpiredLeases != 1 || result.Recovered != 1 || result.DeadWorkers != 0 {
+		t.Errorf("Reap() = %+v, want one expired lease recovered", *result)
+	}
+
+	if err := slow.Extend(ctx, delivery); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Extend() after reaping = %v, want %v", err, ErrLeaseLost)
+	}
+	if err := slow.Ack(ctx, delivery); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Ack() after reaping = %v, want %v", err, ErrLeaseLost)
+	}
+
+	next := client.NewConsumer(queue, "next", time.Minute)
+	redelivered := receive(t, next)
+	if redelivered.ID != delivery.ID || redelivered.Attempt != 2 {
+		t.Errorf("redelivered job %s attempt %d, want %s attempt 2", redelivered.ID, redelivered.Attempt, delivery.ID)
+	}
+	if err := next.Ack(ctx, redelivered); err != nil {
+		t.Fatal(err)
+	}
+	assertNoLeases(t, client, queue)
+}
+
+func TestCrashedWorkerJobsAreReaped(t *testing.T) {
+	client, queue := testRedis(t)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := client.EnqueueJob(ctx, queue, "test", lessonCompleted{LessonID: "lesson-1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Leases that would outlive the test: only the missing heartbeat
+	// gives the crash away
+	crashed := client.NewConsumer(queue, "crashed", time.Hour)
+	receive(t, crashed)
+	receive(t, crashed)
+	time.Sleep(100 * time.Millisecond)
+
+	result, err := client.Reap(ctx, queue, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DeadWorkers != 1 || result.Recovered != 2 || result.ExpiredLeases != 0 {
+		t.Errorf("Reap() = %+v, want one dead worker with two jobs recovered", *result)
+	}
+	if held := client.rdb.LLen(ctx, processingKey(queue, "crashed")).Val(); held != 0 {
+		t.Errorf("crashed worker still holds %d jobs", held)
+	}
+	assertNoLeases(t, client, queue)
+	if pending := client.rdb.LLen(ctx, queue).Val(); pending != 2 {
+		t.Errorf("pending jobs = %d, want 2", pending)
+	}
+}
+
+func TestNackRequeuesJob(t *testing.T) {
+	client, queue := testRedis(t)
+	ctx := context.Background()
+
+	if _, err := client.EnqueueJob(ctx, queue, "test", lessonCompleted{LessonID: "lesson-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := client.NewConsumer(queue, "worker", time.Minute)
+	delivery := receive(t, consumer)
+	if err := consumer.Nack(ctx, delivery, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	retried := receive(t, consumer)
+	if retried.ID != delivery.ID || retried.Attempt != 2 {
+		t.Errorf("retried job %s attempt %d, want %s attempt 2", retried.ID, retried.Attempt, delivery.ID)
+	}
+
+	if err := consumer.Nack(ctx, retried, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if delayed := client.rdb.ZCard(ctx, delayedKey(queue)).Val(); delayed != 1 {
+		t.Errorf("delayed jobs = %d, want 1", delayed)
+	}
+	assertNoLeases(t, client, queue)
+}
+
+func TestDeadLetterCanBeRequeued(t *testing.T) {
+	client, queue := testRedis(t)
+	ctx := context.Background()
+
+	if _, err := client.EnqueueJob(ctx, queue, "test", lessonCompleted{LessonID: "lesson-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := client.NewConsumer(queue, "worker", time.Minute)
+	delivery := receive(t, consumer)
+	if err := consumer.DeadLetter(ctx, delivery, errors.New("lesson not found")); err != nil {
+		t.Fatal(err)
+	}
+	if err := consumer.Ack(ctx, delivery); !errors.Is(err, ErrLeaseLost) {
+		t.Errorf("Ack() after dead-lettering = %v, want %v", err, ErrLeaseLost)
+	}
+	assertNoLeases(t, client, queue)
+
+	entries, total, err := client.ListDeadLetters(ctx, queue, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || entries[0].Error != "lesson not found" || entries[0].Attempts != 1 {
+		t.Fatalf("dead letters = %+v (total %d), want the failed job", entries, total)
+	}
+
+	if _, err := client.RequeueDeadLetter(ctx, queue, entries[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	requeued := receive(t, consumer)
+	if requeued.ID != delivery.ID || requeued.Attempt != 1 {
+		t.Errorf("requeued job %s attempt %d, want %s with a fresh attempt count", requeued.ID, requeued.Attempt, delivery.ID)
+	}
+}
+
+func TestCloseReleasesHeldJobs(t *testing.T) {
+	client, queue := testRedis(t)
+	ctx := context.Background()
+
+	if _, err := client.EnqueueJob(ctx, queue, "test", lessonCompleted{LessonID: "lesson-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := client.NewConsumer(queue, "worker", time.Minute)
+	receive(t, consumer)
+
+	released, err := consumer.Close(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released != 1 {
+		t.Errorf("Close() released %d jobs, want 1", released)
+	}
+	assertNoLeases(t, client, queue)
+	if workers := client.rdb.ZCard(ctx, queue+":workers").Val(); workers != 0 {
+		t.Errorf("registered workers = %d after Close, want 0", workers)
+	}
+	if pending := client.rdb.LLen(ctx, queue).Val(); pending != 1 {
+		t.Errorf("pending jobs = %d, want 1", pending)
+	}
+}
+
+// testRedis connects to the Redis named by TEST_REDIS_ADDR, skipping the
+// test when the variable is unset, and returns a key prefix unique to the
+// test.
+func testRedis(t *testing.T) (*Client, string) {
+	t.Helper()
+
+	addr := os.Getenv(testRedisAddrEnv)
+	if addr == "" {
+		t.Skipf("%s is not set", testRedisAddrEnv)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(&Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := "test:" + uuid.NewString()
+	t.Cleanup(func() {
+		ctx := context.Background()
+		if keys := client.rdb.Keys(ctx, prefix+"*").Val(); len(keys) > 0 {
+			client.rdb.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+	return client, prefix
+}
+
+func receive(t *testing.T, consumer *Consumer) *Delivery {
+	t.Helper()
+
+	delivery, err := consumer.Receive(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery == nil {
+		t.Fatalf("no job on %s", consumer.Queue())
+	}
+	return delivery
+}
+
+// assertNoLeases fails when any lease bookkeeping is left behind.
+func assertNoLeases(t *testing.T, client *Client, queue string) {
+	t.Helper()
+
+	ctx := context.Background()
+	pipe := client.rdb.Pipeline()
+	leases := pipe.ZCard(ctx, queue+":leases")
+	owners := pipe.HLen(ctx, queue+":owners")
+	leased := pipe.HLen(ctx, queue+":leased")
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		t.Fatal(err)
+	}
+	if leases.Val() != 0 || owners.Val() != 0 || leased.Val() != 0 {
+		t.Errorf("leases = %d, owners = %d, leased = %d; want none", leases.Val(), owners.Val(), leased.Val())
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimit allows Requests per Window, refilled evenly, with bursts of up
+// to Requests.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long a denied caller has to wait for one request
+	RetryAfter time.Duration
+	// ResetAfter is how long until the full burst is available again
+	ResetAfter time.Duration
+}
+
+// rateLimitScript implements GCRA, a token bucket that stores only the
+// theoretical arrival time of the next request. It reads the clock of the
+// Redis server, so replicas with skewed clocks share one view of time.
+var rateLimitScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local tat = tonumber(redis.call('GET', KEYS[1])) or now
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - burst * interval
+if now < allow_at then
+	return {0, 0, allow_at - now, tat - now}
+end
+
+redis.call('SET', KEYS[1], new_tat, 'PX', new_tat - now)
+return {1, math.floor((now - allow_at) / interval), 0, new_tat - now}
+`)
+
+// AllowRate takes one request from the bucket at key.
+func (c *Client) AllowRate(ctx context.Context, key string, limit RateLimit) (*RateLimitResult, error) {
+	if limit.Requests <= 0 || limit.Window <= 0 {
+		return nil, fmt.Errorf("invalid rate limit %s", limit)
+	}
+	interval := limit.Window.Milliseconds() / int64(limit.Requests)
+	if interval < 1 {
+		interval = 1
+	}
+
+	values, err := rateLimitScript.Run(ctx, c.rdb, []string{key}, limit.Requests, interval).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Streams carry each message in a single field so callers choose the encoding.
+const streamField = "data"
+
+// StreamMessage is an entry read from a stream by a consumer group.
+// Deliveries counts how often the group handed it out, this time included.
+type StreamMessage struct {
+	ID         string
+	Data       string
+	Deliveries int64
+}
+
+// AppendStream adds data to a stream, trimming it to roughly maxLen entries.
+func (c *Client) AppendStream(ctx context.Context, stream string, data []byte, maxLen int64) (string, error) {
+	return c.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: []interface{}{streamField, data},
+	}).Result()
+}
+
+// EnsureStreamGroup creates a consumer group starting at the oldest entry
+// the stream retains, creating the stream if needed, so entries added before
+// the group existed are still delivered to it. An existing group is left as
+// is.
+func (c *Client) EnsureStreamGroup(ctx context.Context, stream, group string) error {
+	err := c.rdb.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// ReadStreamGroup waits up to block for entries never delivered to the
+// group. It returns no messages and no error when the wait elapses.
+func (c *Client) ReadStreamGroup(ctx context.Context, stream, group, consumer string, count int64, block time.Duration) ([]StreamMessage, error) {
+	streams, err := c.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var messages []StreamMessage
+	for _, s := range streams {
+		for _, message := range s.Messages {
+			messages = append(messages, toStreamMessage(message, 1))
+		}
+	}
+	return messages, nil
+}
+
+// ClaimStaleStream takes over entries another consumer of the group read
+// but did not acknowledge within minIdle, e.g. because it crashed or its
+// handler failed.
+func (c *Client) ClaimStaleStream(ctx context.Context, stream, group, consumer string, minIdle time.Duration, count int64) ([]StreamMessage, error) {
+	pending, err := c.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  group,
+		Idle:   minIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  count,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(pending))
+	deliveries := make(map[string]int64, len(pending))
+	for _, entry := range pending {
+		ids = append(ids, entry.ID)
+		deliveries[entry.ID] = entry.RetryCount + 1
+	}
+
+	claimed, err := c.rdb.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]StreamMessage, 0, len(claimed))
+	for _, message := range claimed {
+		messages = append(messages, toStreamMessage(message, deliveries[message.ID]))
+	}
+	return messages, nil
+}
+
+func (c *Client) AckStream(ctx context.Context, stream, group string, ids ...string) error {
+	return c.rdb.XAck(ctx, stream, group, ids...).Err()
+}
+
+func toStreamMessage(message redis.XMessage, deliveries int64) StreamMessage {
+	data, _ := message.Values[streamField].(string)
+	return StreamMessage{ID: message.ID, Data: data, Deliveries: deliveries}
+}
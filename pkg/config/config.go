@@ -0,0 +1,251 @@
+// Package config loads the settings of the API and its tools into typed
+// structs once at startup, so a missing or unsafe value stops the process
+// before it serves anything.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"s29-be/pkg/cache"
+	"s29-be/pkg/database"
+	"s29-be/pkg/logging"
+	"s29-be/pkg/metrics"
+	"s29-be/pkg/tracing"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvTest        = "test"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+const (
+	EventsRedis  = "redis"
+	EventsMemory = "memory"
+)
+
+type Config struct {
+	App       App             `yaml:"app"`
+	Log       logging.Config  `yaml:"log"`
+	Tracing   tracing.Config  `yaml:"tracing"`
+	Metrics   metrics.Config  `yaml:"metrics"`
+	Database  database.Config `yaml:"database"`
+	Redis     cache.Config    `yaml:"redis"`
+	Kratos    Kratos          `yaml:"kratos"`
+	JWT       JWT             `yaml:"jwt"`
+	Worker    Worker          `yaml:"worker"`
+	Scheduler Scheduler       `yaml:"scheduler"`
+	Events    Events          `yaml:"events"`
+}
+
+type App struct {
+	Env  string `yaml:"env" env:"APP_ENV"`
+	Port int    `yaml:"port" env:"APP_PORT"`
+	// RateLimits overrides named rate limits, e.g. "auth=5/1m;api=off"
+	RateLimits string `yaml:"rate_limits" env:"RATE_LIMITS"`
+	// DrainDelay is how long /readyz fails before the listener closes on
+	// shutdown, giving the load balancer time to notice
+	DrainDelay time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	// ShutdownTimeout bounds the whole shutdown, drain delay included
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// TrustedProxies lists the IPs and CIDRs of load balancers, e.g.
+	// "10.0.0.0/8,192.168.1.10". Only requests from them may set the client
+	// IP through ProxyHeader
+	TrustedProxies string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	ProxyHeader    string `yaml:"proxy_header" env:"PROXY_HEADER"`
+}
+
+type Kratos struct {
+	PublicURL string `yaml:"public_url" env:"KRATOS_PUBLIC_URL"`
+	AdminURL  string `yaml:"admin_url" env:"KRATOS_ADMIN_URL"`
+}
+
+type JWT struct {
+	Secret string        `yaml:"secret" env:"JWT_SECRET"`
+	Issuer string        `yaml:"issuer" env:"JWT_ISSUER"`
+	TTL    time.Duration `yaml:"ttl" env:"JWT_TTL"`
+}
+
+type Worker struct {
+	// Enabled lets API-only replicas leave job processing to others
+	Enabled bool `yaml:"enabled" env:"WORKER_ENABLED"`
+	// Queues lists name:concurrency pairs, e.g. "jobs:default:4,jobs:mail:2"
+	Queues      string `yaml:"queues" env:"WORKER_QUEUES"`
+	MaxAttempts int    `yaml:"max_attempts" env:"WORKER_MAX_ATTEMPTS"`
+}
+
+type Scheduler struct {
+	Enabled  bool   `yaml:"enabled" env:"SCHEDULER_ENABLED"`
+	Timezone string `yaml:"timezone" env:"CRON_TIMEZONE"`
+	// Schedules overrides job schedules, e.g. "streak-reset=5 0 * * *;cleanup=off"
+	Schedules string `yaml:"schedules" env:"CRON_SCHEDULES"`
+}
+
+type Events struct {
+	// Transport is "redis", or "memory" to run subscribers inline
+	Transport string `yaml:"transport" env:"EVENTS_TRANSPORT"`
+}
+
+// insecureSecrets are the placeholders shipped in .env.example and
+// docker-compose.yml, which must never reach production.
+var insecureSecrets = []string{
+	"your-jwt-secret-here",
+	"postgres",
+	"s29-redis-pass",
+}
+
+// minSecretLength is the shortest JWT secret accepted in production.
+const minSecretLength = 32
+
+func Default() *Config {
+	return &Config{
+		App: App{
+			Env:             EnvDevelopment,
+			Port:            8080,
+			DrainDelay:      5 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			ProxyHeader:     "X-Forwarded-For",
+		},
+		Log:      logging.DefaultConfig(),
+		Tracing:  tracing.DefaultConfig(),
+		Metrics:  metrics.DefaultConfig(),
+		Database: *database.DefaultConfig(),
+		Redis:    *cache.DefaultConfig(),
+		Kratos: Kratos{
+			PublicURL: "http://localhost:4433",
+			AdminURL:  "http://localhost:4434",
+		},
+		JWT: JWT{
+			Issuer: "audora-api",
+			TTL:    24 * time.Hour,
+		},
+		Worker:    Worker{Enabled: true},
+		Scheduler: Scheduler{Enabled: true},
+		Events:    Events{Transport: EventsRedis},
+	}
+}
+
+func (c *Config) IsProduction() bool {
+	return c.App.Env == EnvProduction
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains([]string{EnvDevelopment, EnvTest, EnvStaging, EnvProduction}, c.App.Env),
+		"APP_ENV must be one of development, test, staging or production, got %q", c.App.Env)
+	check(c.App.Port > 0 && c.App.Port < 65536, "APP_PORT must be a valid port, got %d", c.App.Port)
+	check(c.App.DrainDelay >= 0, "SHUTDOWN_DRAIN_DELAY must not be negative")
+	check(c.App.ShutdownTimeout > c.App.DrainDelay, "SHUTDOWN_TIMEOUT must be longer than SHUTDOWN_DRAIN_DELAY")
+	for _, proxy := range c.App.TrustedProxyList() {
+		check(isIPOrCIDR(proxy), "TRUSTED_PROXIES must list IPs or CIDRs, got %q", proxy)
+	}
+	check(len(c.App.TrustedProxyList()) == 0 || c.App.ProxyHeader != "",
+		"PROXY_HEADER is required with TRUSTED_PROXIES")
+
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "LOG_LEVEL must be one of debug, info, warn or error, got %q", c.Log.Level)
+	check(slices.Contains([]string{"", logging.FormatJSON, logging.FormatText}, c.Log.Format),
+		"LOG_FORMAT must be json or text, got %q", c.Log.Format)
+
+	check(c.Tracing.Exporter == tracing.ExporterNone || c.Tracing.Exporter == tracing.ExporterOTLP,
+		"OTEL_TRACES_EXPORTER must be none or otlp, got %q", c.Tracing.Exporter)
+	check(c.Tracing.Endpoint == "" || isHTTPURL(c.Tracing.Endpoint),
+		"OTEL_EXPORTER_OTLP_ENDPOINT must be an http(s) URL, got %q", c.Tracing.Endpoint)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"OTEL_TRACES_SAMPLER_ARG must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+
+	if c.Metrics.Enabled {
+		check(c.Metrics.Port > 0 && c.Metrics.Port < 65536, "METRICS_PORT must be a valid port, got %d", c.Metrics.Port)
+		check(c.Metrics.Port != c.App.Port, "METRICS_PORT must differ from APP_PORT")
+	}
+
+	c.checkDatabase(check)
+
+	check(c.Redis.Host != "", "REDIS_HOST is required")
+	check(c.Redis.Port != "", "REDIS_PORT is required")
+
+	check(isHTTPURL(c.Kratos.PublicURL), "KRATOS_PUBLIC_URL must be an http(s) URL, got %q", c.Kratos.PublicURL)
+	check(isHTTPURL(c.Kratos.AdminURL), "KRATOS_ADMIN_URL must be an http(s) URL, got %q", c.Kratos.AdminURL)
+
+	check(c.JWT.Secret != "", "JWT_SECRET is required")
+	check(c.JWT.Issuer != "", "JWT_ISSUER is required")
+	check(c.JWT.TTL > 0, "JWT_TTL must be positive")
+
+	check(c.Worker.MaxAttempts >= 0, "WORKER_MAX_ATTEMPTS must not be negative")
+	check(c.Events.Transport == EventsRedis || c.Events.Transport == EventsMemory,
+		"EVENTS_TRANSPORT must be redis or memory, got %q", c.Events.Transport)
+
+	if c.IsProduction() {
+		check(len(c.JWT.Secret) >= minSecretLength && !slices.Contains(insecureSecrets, c.JWT.Secret),
+			"JWT_SECRET must be a random value of at least %d characters in production", minSecretLength)
+		check(!slices.Contains(insecureSecrets, c.Redis.Password),
+			"REDIS_PASSWORD must not be a default value in production")
+		check(c.Events.Transport != EventsMemory,
+			"EVENTS_TRANSPORT=memory only delivers events within one process and is not allowed in production")
+	}
+
+	return errors.Join(errs...)
+}
+
+// TrustedProxyList splits TrustedProxies.
+func (a App) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(a.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+func isIPOrCIDR(value string) bool {
+	if _, err := netip.ParseAddr(value); err == nil {
+		return true
+	}
+	_, err := netip.ParsePrefix(value)
+	return err == nil
+}
+
+// ValidateDatabase reports every invalid database setting at once, for
+// tools such as migrations that need nothing else.
+func (c *Config) ValidateDatabase() error {
+	var errs []error
+	c.checkDatabase(func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func (c *Config) checkDatabase(check func(ok bool, format string, args ...interface{})) {
+	check(c.Database.Host != "", "DB_HOST is required")
+	check(c.Database.Port != "", "DB_PORT is required")
+	check(c.Database.User != "", "DB_USER is required")
+	check(c.Database.DBName != "", "DB_NAME is required")
+	check(c.Database.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS must be positive")
+	check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	if c.IsProduction() {
+		check(c.Database.Password != "" && !slices.Contains(insecureSecrets, c.Database.Password),
+			"DB_PASSWORD must be set to a non-default value in production")
+	}
+}
+
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"s29-be/pkg/logging"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the configuration and validates it. Sources are applied in
+// increasing precedence: defaults, the YAML file named by CONFIG_FILE, a
+// .env file in the working directory, and the environment. A .env file
+// never overrides variables already set in the environment.
+func Load() (*Config, error) {
+	config, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return config, nil
+}
+
+// LoadDatabase reads the configuration like Load but validates only the
+// database settings, so tools that just talk to Postgres run without auth
+// or Redis settings. The other sections are returned unchecked.
+func LoadDatabase() (*Config, error) {
+	config, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if err := config.ValidateDatabase(); err != nil {
+		return nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return config, nil
+}
+
+func read() (*Config, error) {
+	config := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read .env: %w", err)
+	}
+
+	if err := applyEnv(reflect.ValueOf(config).Elem()); err != nil {
+		return nil, err
+	}
+
+	// Log collectors in production expect JSON unless told otherwise
+	if config.Log.Format == "" && config.IsProduction() {
+		config.Log.Format = logging.FormatJSON
+	}
+	return config, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overwrites every field tagged with env whose variable is set,
+// descending into nested structs.
+func applyEnv(value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		structField := value.Type().Field(i)
+
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := structField.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := setField(field, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		field.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -2,22 +2,38 @@ package context
 
 import (
 	"s29-be/pkg/cache"
+	"s29-be/pkg/config"
+	"s29-be/pkg/events"
+	"s29-be/pkg/lifecycle"
+	"s29-be/pkg/middleware"
+	"s29-be/pkg/scheduler"
+	"s29-be/pkg/worker"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
 type ServiceContext struct {
+	config         *config.Config
 	dbContext      *gorm.DB
 	router         *fiber.App
 	publicRouter   *fiber.Router
 	internalRouter *fiber.Router
 
 	cacheClient *cache.Client
+	cache       *cache.Cache
+
+	authMiddleware *middleware.AuthMiddleware
+	rateLimiter    *middleware.RateLimiter
+	worker         *worker.Runtime
+	scheduler      *scheduler.Scheduler
+	eventBus       *events.Bus
+	lifecycle      *lifecycle.Manager
 }
 
-func NewServiceContext(dbContext *gorm.DB, router *fiber.App, publicRouter *fiber.Router, internalRouter *fiber.Router, cacheClient *cache.Client) *ServiceContext {
+func NewServiceContext(config *config.Config, dbContext *gorm.DB, router *fiber.App, publicRouter *fiber.Router, internalRouter *fiber.Router, cacheClient *cache.Client) *ServiceContext {
 	return &ServiceContext{
+		config:         config,
 		dbContext:      dbContext,
 		router:         router,
 		publicRouter:   publicRouter,
@@ -26,6 +42,10 @@ func NewServiceContext(dbContext *gorm.DB, router *fiber.App, publicRouter *fibe
 	}
 }
 
+func (ctx ServiceContext) GetConfig() *config.Config {
+	return ctx.config
+}
+
 func (ctx ServiceContext) GetDB() *gorm.DB {
 	return ctx.dbContext
 }
@@ -45,3 +65,71 @@ func (ctx ServiceContext) GetInternalRouter() *fiber.Router {
 func (ctx ServiceContext) GetCacheClient() *cache.Client {
 	return ctx.cacheClient
 }
+
+// SetAuthMiddleware shares the auth module's middleware with modules registered after it.
+func (ctx *ServiceContext) SetAuthMiddleware(authMiddleware *middleware.AuthMiddleware) {
+	ctx.authMiddleware = authMiddleware
+}
+
+func (ctx ServiceContext) GetAuthMiddleware() *middleware.AuthMiddleware {
+	return ctx.authMiddleware
+}
+
+// SetRateLimiter shares the distributed rate limiter so modules can limit
+// their route groups.
+func (ctx *ServiceContext) SetRateLimiter(rateLimiter *middleware.RateLimiter) {
+	ctx.rateLimiter = rateLimiter
+}
+
+func (ctx ServiceContext) GetRateLimiter() *middleware.RateLimiter {
+	return ctx.rateLimiter
+}
+
+// SetWorker shares the background job runtime so modules can register
+// handlers from their constructors.
+func (ctx *ServiceContext) SetWorker(worker *worker.Runtime) {
+	ctx.worker = worker
+}
+
+func (ctx ServiceContext) GetWorker() *worker.Runtime {
+	return ctx.worker
+}
+
+// SetScheduler shares the recurring job scheduler so modules can register
+// jobs from their constructors.
+func (ctx *ServiceContext) SetScheduler(scheduler *scheduler.Scheduler) {
+	ctx.scheduler = scheduler
+}
+
+func (ctx ServiceContext) GetScheduler() *scheduler.Scheduler {
+	return ctx.scheduler
+}
+
+// SetEventBus shares the domain event bus so modules can publish events and
+// subscribe to them from their constructors.
+func (ctx *ServiceContext) SetEventBus(eventBus *events.Bus) {
+	ctx.eventBus = eventBus
+}
+
+func (ctx ServiceContext) GetEventBus() *events.Bus {
+	return ctx.eventBus
+}
+
+// SetCache shares the typed cache-aside layer in front of Redis.
+func (ctx *ServiceContext) SetCache(cache *cache.Cache) {
+	ctx.cache = cache
+}
+
+func (ctx ServiceContext) GetCache() *cache.Cache {
+	return ctx.cache
+}
+
+// SetLifecycle shares the lifecycle manager so modules can register
+// background loops that start with the process and stop on shutdown.
+func (ctx *ServiceContext) SetLifecycle(lifecycle *lifecycle.Manager) {
+	ctx.lifecycle = lifecycle
+}
+
+func (ctx ServiceContext) GetLifecycle() *lifecycle.Manager {
+	return ctx.lifecycle
+}
@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"s29-be/pkg/logging"
+	"s29-be/pkg/tracing"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -20,19 +22,37 @@ func New(host, port, user, password, dbname string) (*Database, error) {
 }
 
 func NewWithConfig(host, port, user, password, dbname, sslmode string) (*Database, error) {
+	config := DefaultConfig()
+	config.Host = host
+	config.Port = port
+	config.User = user
+	config.Password = password
+	config.DBName = dbname
+	config.SSLMode = sslmode
+	return NewFromConfig(config)
+}
+
+// NewFromConfig opens the database and sizes its connection pool. Queries
+// are logged through the default slog logger and traced as child spans of
+// their context's span.
+func NewFromConfig(config *Config) (*Database, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host,
-		port,
-		user,
-		password,
-		dbname,
-		sslmode,
+		config.Host,
+		config.Port,
+		config.User,
+		config.Password,
+		config.DBName,
+		config.SSLMode,
 	)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logging.NewGormLogger(slog.Default(), config.SlowQueryThreshold),
+	})
 	if err != nil {
-		log.Printf("Failed to open database: %v", err)
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tracing: %w", err)
+	}
 
 	// Configure connection pool through underlying sql.DB
 	sqlDB, err := db.DB()
@@ -40,10 +60,10 @@ func NewWithConfig(host, port, user, password, dbname, sslmode string) (*Databas
 		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxOpenConns(10)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(time.Hour)
-	sqlDB.SetConnMaxIdleTime(time.Minute)
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
 
 	// Test the connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -61,3 +81,12 @@ func NewWithConfig(host, port, user, password, dbname, sslmode string) (*Databas
 func (db *Database) GetDB() *gorm.DB {
 	return db.DB
 }
+
+// Close closes the connection pool once in-flight queries finish.
+func (db *Database) Close() error {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -88,6 +88,42 @@ func NewForbiddenError(err error, message string) *AppError {
 	}
 }
 
+func NewConflictError(err error, message string) *AppError {
+	if message == "" {
+		message = "Conflict"
+	}
+	return &AppError{
+		Err:        err,
+		StatusCode: http.StatusConflict,
+		Message:    message,
+		Code:       "CONFLICT",
+	}
+}
+
+func NewUnprocessableEntityError(err error, message string) *AppError {
+	if message == "" {
+		message = "Unprocessable Entity"
+	}
+	return &AppError{
+		Err:        err,
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    message,
+		Code:       "UNPROCESSABLE_ENTITY",
+	}
+}
+
+func NewTooManyRequestsError(err error, message string) *AppError {
+	if message == "" {
+		message = "Too Many Requests"
+	}
+	return &AppError{
+		Err:        err,
+		StatusCode: http.StatusTooManyRequests,
+		Message:    message,
+		Code:       "RATE_LIMITED",
+	}
+}
+
 func NewInternalError(err error, message string) *AppError {
 	if message == "" {
 		message = "Internal Server Error"
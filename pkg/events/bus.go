@@ -0,0 +1,205 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"s29-be/pkg/logging"
+	"s29-be/pkg/tracing"
+	"sync"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler handles one event. Transports deliver at least once, so handlers
+// must tolerate seeing the same envelope again; an error asks for a retry.
+type Handler func(ctx context.Context, envelope *Envelope) error
+
+// Transport carries events between replicas.
+type Transport interface {
+	Publish(ctx context.Context, envelope *Envelope) error
+	// Consume hands every event with the given name to handler once per
+	// subscriber across all replicas, until ctx is cancelled.
+	Consume(ctx context.Context, event, subscriber string, handler Handler)
+}
+
+type subscription struct {
+	subscriber string
+	event      string
+	handler    Handler
+}
+
+// Bus routes published events to subscribers. With a transport, events are
+// delivered asynchronously on whichever replica picks them up; without one,
+// Publish runs the handlers before returning, which suits tests and tools.
+type Bus struct {
+	transport Transport
+
+	mu            sync.Mutex
+	subscriptions []subscription
+	started       bool
+
+	consumers sync.WaitGroup
+	// abort cancels the handlers still running when Wait gives up
+	abort context.CancelFunc
+}
+
+func NewBus(transport Transport) *Bus {
+	return &Bus{transport: transport}
+}
+
+// Subscribe registers handler for events of type T. subscriber names the
+// reaction, e.g. "user.refresh-xp", and must be unique; transports track
+// delivery per subscriber. It panics on duplicates or once the bus has
+// started, since both are programming errors.
+func Subscribe[T Event](b *Bus, subscriber string, handler func(ctx context.Context, event T) error) {
+	var zero T
+	event := zero.EventName()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.started {
+		panic("events: Subscribe called after Start")
+	}
+	for _, s := range b.subscriptions {
+		if s.subscriber == subscriber && s.event == event {
+			panic(fmt.Sprintf("events: %s subscribed to %s twice", subscriber, event))
+		}
+	}
+
+	b.subscriptions = append(b.subscriptions, subscription{
+		subscriber: subscriber,
+		event:      event,
+		handler: func(ctx context.Context, envelope *Envelope) error {
+			var payload T
+			if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+				return fmt.Errorf("decode %s: %w", envelope.Name, err)
+			}
+			return handler(ctx, payload)
+		},
+	})
+}
+
+// Publish sends event to its subscribers.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	envelope, err := NewEnvelope(event)
+	if err != nil {
+		return err
+	}
+	return b.PublishEnvelope(ctx, envelope)
+}
+
+// PublishEnvelope sends an already wrapped event, keeping its ID.
+func (b *Bus) PublishEnvelope(ctx context.Context, envelope *Envelope) error {
+	if b.transport != nil {
+		return b.transport.Publish(ctx, envelope)
+	}
+
+	b.mu.Lock()
+	subscriptions := b.subscriptions
+	b.mu.Unlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if s.event != envelope.Name {
+			continue
+		}
+		if err := invoke(ctx, s.handler, envelope); err != nil {
+			errs = append(errs, &HandlerError{Subscriber: s.subscriber, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HandlerError is returned by Publish on a bus without a transport when a
+// subscriber fails. The event was delivered to every subscriber; only the
+// named reaction failed, and nothing retries it.
+type HandlerError struct {
+	Subscriber string
+	Err        error
+}
+
+func (e *HandlerError) Error() string {
+	return e.Subscriber + ": " + e.Err.Error()
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// Start begins consuming events for every subscription until ctx is
+// cancelled. It does nothing without a transport.
+//
+// Handlers run under a context of their own, so cancelling ctx stops the
+// consumers taking new events but lets the ones being handled finish; see
+// Wait.
+func (b *Bus) Start(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.started {
+		return
+	}
+	b.started = true
+
+	if b.transport == nil {
+		return
+	}
+
+	handlerCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	b.abort = abort
+	for _, s := range b.subscriptions {
+		handler := s.handler
+		b.consumers.Add(1)
+		go func() {
+			defer b.consumers.Done()
+			b.transport.Consume(ctx, s.event, s.subscriber, func(_ context.Context, envelope *Envelope) error {
+				return invoke(handlerCtx, handler, envelope)
+			})
+		}()
+	}
+}
+
+// Wait blocks until the consumers started by Start have returned after
+// their context was cancelled, letting the events being handled finish.
+// Once ctx is done the handlers still running are cancelled and ctx's
+// error is returned.
+func (b *Bus) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.consumers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		if b.abort != nil {
+			b.abort()
+		}
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func invoke(ctx context.Context, handler Handler, envelope *Envelope) (err error) {
+	ctx = logging.With(ctx, slog.String("event", envelope.Name), slog.String("event_id", envelope.ID.String()))
+	ctx, span := tracing.Tracer().Start(ctx, "event "+envelope.Name,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(semconv.MessagingMessageID(envelope.ID.String())),
+	)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+	return handler(ctx, envelope)
+}
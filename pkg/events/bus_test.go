@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type lessonCompleted struct {
+	LessonID string `json:"lesson_id"`
+}
+
+func (lessonCompleted) EventName() string {
+	return "lesson.completed"
+}
+
+// onceTransport delivers a single event to each consumer, then waits for
+// its context like a consumer blocked on an empty stream.
+type onceTransport struct{}
+
+func (onceTransport) Publish(context.Context, *Envelope) error {
+	return nil
+}
+
+func (onceTransport) Consume(ctx context.Context, event, subscriber string, handler Handler) {
+	envelope, err := NewEnvelope(lessonCompleted{LessonID: "lesson-1"})
+	if err != nil {
+		panic(err)
+	}
+	_ = handler(ctx, envelope)
+	<-ctx.Done()
+}
+
+// startBlockedBus starts a bus whose only handler blocks until release is
+// closed, and returns once that handler is running.
+func startBlockedBus(t *testing.T) (bus *Bus, stop context.CancelFunc, release chan struct{}, handlerErr chan error) {
+	t.Helper()
+
+	bus = NewBus(onceTransport{})
+	started := make(chan struct{})
+	release = make(chan struct{})
+	handlerErr = make(chan error, 1)
+	Subscribe(bus, "test.block", func(ctx context.Context, event lessonCompleted) error {
+		close(started)
+		select {
+		case <-release:
+			handlerErr <- ctx.Err()
+		case <-ctx.Done():
+			handlerErr <- ctx.Err()
+		}
+		return nil
+	})
+
+	ctx, stop := context.WithCancel(context.Background())
+	bus.Start(ctx)
+	<-started
+	return bus, stop, release, handlerErr
+}
+
+func TestWaitLetsHandlersFinish(t *testing.T) {
+	bus, stop, release, handlerErr := startBlockedBus(t)
+
+	stop()
+	waited := make(chan error, 1)
+	go func() { waited <- bus.Wait(context.Background()) }()
+
+	select {
+	case err := <-waited:
+		t.Fatalf("Wait() = %v while a handler was still running", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-handlerErr; err != nil {
+		t.Errorf("handler context = %v after stopping the consumers, want it still live", err)
+	}
+	if err := <-waited; err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
+
+func TestWaitCancelsHandlersAtDeadline(t *testing.T) {
+	bus, stop, _, handlerErr := startBlockedBus(t)
+
+	stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := bus.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := <-handlerErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("handler context = %v after Wait gave up, want %v", err, context.Canceled)
+	}
+}
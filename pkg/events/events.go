@@ -0,0 +1,92 @@
+// Package events lets modules react to each other's domain events without
+// importing each other.
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a domain event. Name identifies the event type on the wire and
+// must not change once published.
+type Event interface {
+	EventName() string
+}
+
+const (
+	UserRegisteredEvent  = "user.registered"
+	UserRecoveredEvent   = "user.recovered"
+	LessonCompletedEvent = "lesson.completed"
+	XPAwardedEvent       = "xp.awarded"
+)
+
+type UserRegistered struct {
+	UserID           uuid.UUID `json:"user_id"`
+	KratosIdentityID uuid.UUID `json:"kratos_identity_id"`
+	Email            string    `json:"email"`
+	RegisteredAt     time.Time `json:"registered_at"`
+}
+
+func (UserRegistered) EventName() string { return UserRegisteredEvent }
+
+type UserRecovered struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Method      string    `json:"method"`
+	RecoveredAt time.Time `json:"recovered_at"`
+}
+
+func (UserRecovered) EventName() string { return UserRecoveredEvent }
+
+// LessonCompleted is published for every recorded attempt, passed or not.
+type LessonCompleted struct {
+	UserID      uuid.UUID `json:"user_id"`
+	CourseID    uuid.UUID `json:"course_id"`
+	LessonID    uuid.UUID `json:"lesson_id"`
+	Score       int       `json:"score"`
+	Passed      bool      `json:"passed"`
+	CrownLevel  int       `json:"crown_level"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+func (LessonCompleted) EventName() string { return LessonCompletedEvent }
+
+type XPAwarded struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CourseID  uuid.UUID `json:"course_id"`
+	LessonID  uuid.UUID `json:"lesson_id"`
+	Amount    int       `json:"amount"`
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
+func (XPAwarded) EventName() string { return XPAwardedEvent }
+
+// Envelope is an event as it travels between replicas. Handlers can use ID
+// to recognise redeliveries.
+type Envelope struct {
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope wraps event with a fresh ID.
+func NewEnvelope(event Event) (*Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		ID:         id,
+		Name:       event.EventName(),
+		OccurredAt: time.Now().UTC(),
+		Payload:    payload,
+	}, nil
+}
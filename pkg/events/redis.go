@@ -0,0 +1,149 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"s29-be/pkg/cache"
+)
+
+type RedisConfig struct {
+	// StreamPrefix is prepended to the event name to form the stream key
+	StreamPrefix string
+	// MaxLen roughly caps each stream; consumer groups that fall further
+	// behind lose the oldest events
+	MaxLen int64
+	Batch  int64
+	Block  time.Duration
+	// Events a subscriber fails to acknowledge within ClaimIdle are
+	// redelivered, up to MaxDeliveries times in total
+	ClaimIdle     time.Duration
+	MaxDeliveries int64
+}
+
+func DefaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		StreamPrefix:  "events:",
+		MaxLen:        100000,
+		Batch:         10,
+		Block:         2 * time.Second,
+		ClaimIdle:     30 * time.Second,
+		MaxDeliveries: 5,
+	}
+}
+
+// RedisTransport stores each event type in a Redis stream and gives every
+// subscriber its own consumer group, so each subscriber sees an event once
+// however many replicas run it.
+type RedisTransport struct {
+	client   *cache.Client
+	config   RedisConfig
+	consumer string
+}
+
+func NewRedisTransport(client *cache.Client, config RedisConfig) *RedisTransport {
+	return &RedisTransport{
+		client:   client,
+		config:   config,
+		consumer: cache.NewWorkerID(),
+	}
+}
+
+func (t *RedisTransport) Publish(ctx context.Context, envelope *Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	_, err = t.client.AppendStream(ctx, t.config.StreamPrefix+envelope.Name, data, t.config.MaxLen)
+	return err
+}
+
+func (t *RedisTransport) Consume(ctx context.Context, event, subscriber string, handler Handler) {
+	stream := t.config.StreamPrefix + event
+	groupReady := false
+	var claimedAt time.Time
+
+	for ctx.Err() == nil {
+		if !groupReady {
+			if err := t.client.EnsureStreamGroup(ctx, stream, subscriber); err != nil {
+				t.logError(ctx, stream, subscriber, fmt.Errorf("create consumer group: %w", err))
+				t.pause(ctx)
+				continue
+			}
+			groupReady = true
+		}
+
+		var messages []cache.StreamMessage
+		if time.Since(claimedAt) >= t.config.ClaimIdle/2 {
+			claimed, err := t.client.ClaimStaleStream(ctx, stream, subscriber, t.consumer, t.config.ClaimIdle, t.config.Batch)
+			if err != nil {
+				t.logError(ctx, stream, subscriber, fmt.Errorf("claim stale events: %w", err))
+			}
+			messages = claimed
+			claimedAt = time.Now()
+		}
+
+		read, err := t.client.ReadStreamGroup(ctx, stream, subscriber, t.consumer, t.config.Batch, t.config.Block)
+		if err != nil {
+			// The stream or group may have been deleted; recreate it
+			t.logError(ctx, stream, subscriber, err)
+			groupReady = false
+			t.pause(ctx)
+		}
+		messages = append(messages, read...)
+
+		// On shutdown the rest of the batch stays pending and is claimed
+		// by another consumer after ClaimIdle
+		for _, message := range messages {
+			if ctx.Err() != nil {
+				break
+			}
+			t.handle(ctx, stream, subscriber, handler, message)
+		}
+	}
+}
+
+func (t *RedisTransport) handle(ctx context.Context, stream, subscriber string, handler Handler, message cache.StreamMessage) {
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(message.Data), &envelope); err != nil {
+		// Undecodable or trimmed entries can never succeed
+		t.logError(ctx, stream, subscriber, fmt.Errorf("dropping event %s: %w", message.ID, err))
+		t.ack(ctx, stream, subscriber, message.ID)
+		return
+	}
+
+	if err := handler(ctx, &envelope); err != nil {
+		if ctx.Err() != nil {
+			// Failed because of shutdown, which is no reason to drop it
+			return
+		}
+		if message.Deliveries < t.config.MaxDeliveries {
+			t.logError(ctx, stream, subscriber, fmt.Errorf("event %s failed, will retry: %w", envelope.ID, err))
+			return
+		}
+		t.logError(ctx, stream, subscriber, fmt.Errorf("dropping event %s after %d deliveries: %w", envelope.ID, message.Deliveries, err))
+	}
+	t.ack(ctx, stream, subscriber, message.ID)
+}
+
+func (t *RedisTransport) ack(ctx context.Context, stream, subscriber, id string) {
+	if err := t.client.AckStream(context.WithoutCancel(ctx), stream, subscriber, id); err != nil {
+		t.logError(ctx, stream, subscriber, fmt.Errorf("ack %s: %w", id, err))
+	}
+}
+
+func (t *RedisTransport) pause(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(t.config.Block):
+	}
+}
+
+func (t *RedisTransport) logError(ctx context.Context, stream, subscriber string, err error) {
+	if ctx.Err() == nil {
+		slog.ErrorContext(ctx, "Events", slog.String("stream", stream), slog.String("subscriber", subscriber), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"s29-be/pkg/cache"
+
+	"github.com/google/uuid"
+)
+
+// testRedisAddrEnv names a Redis the transport tests may write to, e.g.
+// "localhost:6379".
+const testRedisAddrEnv = "TEST_REDIS_ADDR"
+
+func TestConsumeKeepsEventsFailedByShutdown(t *testing.T) {
+	transport := testTransport(t)
+	envelope, err := NewEnvelope(lessonCompleted{LessonID: "lesson-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := transport.Publish(context.Background(), envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	// The only delivery allowed fails because the replica is shutting down
+	ctx, stop := context.WithCancel(context.Background())
+	transport.Consume(ctx, envelope.Name, "test.shutdown", func(context.Context, *Envelope) error {
+		stop()
+		return errors.New("interrupted by shutdown")
+	})
+
+	redelivered := make(chan *Envelope, 1)
+	ctx, stop = context.WithCancel(context.Background())
+	go transport.Consume(ctx, envelope.Name, "test.shutdown", func(_ context.Context, got *Envelope) error {
+		redelivered <- got
+		stop()
+		return nil
+	})
+	defer stop()
+
+	select {
+	case got := <-redelivered:
+		if got.ID != envelope.ID {
+			t.Errorf("redelivered event %s, want %s", got.ID, envelope.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event failed by shutdown was dropped instead of redelivered")
+	}
+}
+
+// testTransport connects to the Redis named by TEST_REDIS_ADDR, skipping the
+// test when the variable is unset, and returns a transport on streams of
+// its own that allows one delivery and claims pending events at once.
+func testTransport(t *testing.T) *RedisTransport {
+	t.Helper()
+
+	addr := os.Getenv(testRedisAddrEnv)
+	if addr == "" {
+		t.Skipf("%s is not set", testRedisAddrEnv)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := cache.NewClient(&cache.Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultRedisConfig()
+	config.StreamPrefix = "test:" + uuid.NewString() + ":"
+	config.Block = 100 * time.Millisecond
+	config.ClaimIdle = time.Millisecond
+	config.MaxDeliveries = 1
+	t.Cleanup(func() {
+		_ = client.Del(context.Background(), config.StreamPrefix+lessonCompleted{}.EventName())
+		client.Close()
+	})
+	return NewRedisTransport(client, config)
+}
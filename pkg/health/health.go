@@ -0,0 +1,208 @@
+// Package health serves the liveness and readiness probes. Liveness only
+// says the process is serving; readiness runs a checker per dependency and
+// tells the load balancer whether to route traffic here.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"s29-be/pkg/buildinfo"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// Checker reports whether a dependency is usable; nil means it is.
+type Checker func(ctx context.Context) error
+
+type Check struct {
+	Name  string
+	Check Checker
+	// Timeout bounds one run; zero uses Config.Timeout
+	Timeout time.Duration
+	// Critical dependencies make the service not ready when down; others
+	// only degrade it
+	Critical bool
+}
+
+type Config struct {
+	// CacheTTL reuses a report for probes arriving within it, so frequent
+	// probes from several sources do not load the dependencies
+	CacheTTL time.Duration
+	Timeout  time.Duration
+	// ShowErrors includes checker errors in reports; they can name
+	// internal hosts, so production leaves them to the logs
+	ShowErrors bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		CacheTTL: 2 * time.Second,
+		Timeout:  2 * time.Second,
+	}
+}
+
+type Result struct {
+	Status     string  `json:"status"`
+	Critical   bool    `json:"critical"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+type Report struct {
+	Status string `json:"status"`
+	// Draining is set once shutdown has begun
+	Draining  bool              `json:"draining,omitempty"`
+	Checks    map[string]Result `json:"checks,omitempty"`
+	CheckedAt time.Time         `json:"checked_at"`
+	Build     buildinfo.Info    `json:"build"`
+}
+
+type Health struct {
+	config   Config
+	checks   []Check
+	draining atomic.Bool
+
+	mu   sync.Mutex
+	last *Report
+}
+
+func New(config Config) *Health {
+	return &Health{config: config}
+}
+
+// Register adds a dependency check. Checks must be registered before the
+// probes are served.
+func (h *Health) Register(check Check) {
+	h.checks = append(h.checks, check)
+}
+
+// Drain makes readiness fail from now on, so the load balancer stops
+// routing new requests here while in-flight ones finish.
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Check runs every checker concurrently, or returns the previous report
+// while it is fresh. Concurrent callers wait for a single run.
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.last != nil && time.Since(h.last.CheckedAt) < h.config.CacheTTL {
+		return *h.last
+	}
+
+	results := make([]Result, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:    StatusUp,
+		Checks:    make(map[string]Result, len(h.checks)),
+		CheckedAt: time.Now(),
+		Build:     buildinfo.Get(),
+	}
+	for i, check := range h.checks {
+		result := results[i]
+		report.Checks[check.Name] = result
+		switch {
+		case result.Status == StatusUp:
+		case check.Critical:
+			report.Status = StatusDown
+		case report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+
+	h.last = &report
+	return report
+}
+
+func (h *Health) run(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = h.config.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result := Result{
+		Status:     StatusUp,
+		Critical:   check.Critical,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Health check failed", slog.String("check", check.Name), slog.Any("error", err))
+		result.Status = StatusDown
+		if h.config.ShowErrors {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// Livez answers while the process can serve requests. It does not check
+// dependencies, since restarting the process would not bring them back.
+//
+// @Summary      Liveness probe
+// @Description  Answers while the process can serve requests, without checking dependencies
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Router       /livez [get]
+func (h *Health) Livez(c *fiber.Ctx) error {
+	return c.JSON(Report{
+		Status:    StatusUp,
+		CheckedAt: time.Now(),
+		Build:     buildinfo.Get(),
+	})
+}
+
+// Readyz answers 200 while the service is up or degraded and 503 once a
+// critical dependency is down or shutdown has begun.
+//
+// @Summary      Readiness probe
+// @Description  Checks Postgres, Redis and Kratos. 503 once a critical dependency is down or shutdown has begun; /health is an alias
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  health.Report
+// @Failure      503  {object}  health.Report
+// @Router       /readyz [get]
+// @Router       /health [get]
+func (h *Health) Readyz(c *fiber.Ctx) error {
+	if h.draining.Load() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(Report{
+			Status:    StatusDown,
+			Draining:  true,
+			CheckedAt: time.Now(),
+			Build:     buildinfo.Get(),
+		})
+	}
+
+	// Detached from the request so a probe giving up does not leave a
+	// half-finished report cached for the others
+	report := h.Check(context.WithoutCancel(c.UserContext()))
+	status := fiber.StatusOK
+	if report.Status == StatusDown {
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(report)
+}
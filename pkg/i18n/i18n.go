@@ -0,0 +1,165 @@
+// Package i18n resolves the language of a request and translates API
+// messages using catalogues keyed by AppError codes.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SourceLanguage is the language messages are written in throughout the code.
+const SourceLanguage = "en"
+
+// DefaultLanguage is used when a request does not ask for a supported language.
+const DefaultLanguage = SourceLanguage
+
+// LocalsKey is the fiber.Ctx local holding the negotiated language.
+const LocalsKey = "language"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// entry is the catalogue record for one error code: a generic message plus
+// translations of specific messages raised with that code.
+type entry struct {
+	Message  string            `json:"message"`
+	Messages map[string]string `json:"messages"`
+}
+
+var catalogues = mustLoadCatalogues()
+
+func mustLoadCatalogues() map[string]map[string]entry {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: read locales: %v", err))
+	}
+
+	loaded := make(map[string]map[string]entry, len(files))
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: read %s: %v", file.Name(), err))
+		}
+
+		var catalogue map[string]entry
+		if err := json.Unmarshal(data, &catalogue); err != nil {
+			panic(fmt.Sprintf("i18n: parse %s: %v", file.Name(), err))
+		}
+		loaded[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = catalogue
+	}
+	return loaded
+}
+
+// Supported returns the languages that have a message catalogue.
+func Supported() []string {
+	languages := make([]string, 0, len(catalogues))
+	for language := range catalogues {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Normalize maps a language tag such as "vi-VN" to a supported language,
+// returning "" when neither the tag nor its base language is supported.
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if _, ok := catalogues[tag]; ok {
+		return tag
+	}
+	if base, _, found := strings.Cut(tag, "-"); found {
+		if _, ok := catalogues[base]; ok {
+			return base
+		}
+	}
+	return ""
+}
+
+// Negotiate picks the supported language with the highest quality value
+// from an Accept-Language header, falling back to DefaultLanguage.
+func Negotiate(header string) string {
+	type candidate struct {
+		language string
+		quality  float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		if language := Normalize(tag); language != "" {
+			candidates = append(candidates, candidate{language: language, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	// Stable so that equal weights keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].language
+}
+
+// FallbackChain lists the languages to try, in order, when looking up
+// translated content for language.
+func FallbackChain(language string) []string {
+	chain := []string{}
+	for _, candidate := range []string{language, Normalize(language), DefaultLanguage} {
+		if candidate != "" && !slices.Contains(chain, candidate) {
+			chain = append(chain, candidate)
+		}
+	}
+	return chain
+}
+
+// Translate returns message in language. Specific translations registered
+// under code win; otherwise non-source languages get the generic message
+// for code so responses never mix languages.
+func Translate(language, code, message string) string {
+	for _, candidate := range FallbackChain(language) {
+		catalogue, ok := catalogues[candidate]
+		if !ok {
+			continue
+		}
+		entry := catalogue[code]
+		if translated, ok := entry.Messages[message]; ok {
+			return translated
+		}
+		if candidate == SourceLanguage && message != "" {
+			return message
+		}
+		if entry.Message != "" {
+			return entry.Message
+		}
+	}
+	return message
+}
+
+// Language returns the language negotiated for the request.
+func Language(c *fiber.Ctx) string {
+	if language, ok := c.Locals(LocalsKey).(string); ok && language != "" {
+		return language
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,65 @@
+package json_response
+
+import (
+	"net/http"
+	"s29-be/pkg/i18n"
+	"s29-be/pkg/tracing"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const MIMEProblemJSON = "application/problem+json"
+
+// problemTypePrefix identifies problem types by error code. The URNs are
+// identifiers only; clients should branch on them or on Code, never on
+// Title or Detail, which are localized.
+const problemTypePrefix = "urn:s29:problem:"
+
+// Problem is an RFC 9457 (formerly RFC 7807) problem details document,
+// extended with the stable error code, the trace ID and error data such as
+// the invalid fields of a request.
+type Problem struct {
+	Type     string      `json:"type"`
+	Title    string      `json:"title"`
+	Status   int         `json:"status"`
+	Code     string      `json:"code"`
+	Detail   string      `json:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty"`
+	TraceID  string      `json:"trace_id,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+}
+
+// ProblemType returns the type URI of the problems with code, e.g.
+// "urn:s29:problem:not-found" for NOT_FOUND.
+func ProblemType(code string) string {
+	return problemTypePrefix + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// responseProblem renders a problem document. Title is the generic message
+// of code and Detail the specific message, both in the negotiated
+// language; Detail is left out when it would only repeat Title.
+func responseProblem(c *fiber.Ctx, httpCode int, code, message string, data interface{}) {
+	language := i18n.Language(c)
+	c.Set(fiber.HeaderContentLanguage, language)
+
+	problem := Problem{
+		Type:     ProblemType(code),
+		Title:    i18n.Translate(language, code, ""),
+		Status:   httpCode,
+		Code:     code,
+		Instance: c.Path(),
+		TraceID:  tracing.TraceID(c.UserContext()),
+		Data:     data,
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(httpCode)
+	}
+	if message != "" {
+		if detail := i18n.Translate(language, code, message); detail != problem.Title {
+			problem.Detail = detail
+		}
+	}
+
+	c.Status(httpCode).JSON(problem, MIMEProblemJSON)
+}
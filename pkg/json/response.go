@@ -3,6 +3,8 @@ package json_response
 import (
 	"encoding/json"
 	"net/http"
+	app_error "s29-be/pkg/error"
+	"s29-be/pkg/i18n"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -40,58 +42,66 @@ func (r SonicJSON) WriteContentType(w http.ResponseWriter) {
 // }.Froze()
 
 var (
-	successResponse       = mustMarshal(Response{Code: 200, Message: "Success"})
-	createdResponse       = mustMarshal(Response{Code: 201, Message: "Created"})
-	notFoundResponse      = mustMarshal(Response{Code: 404, Message: "Not Found"})
-	unauthorizedResponse  = mustMarshal(Response{Code: 401, Message: "Unauthorized"})
-	badRequestResponse    = mustMarshal(Response{Code: 400, Message: "Bad Request"})
-	forbiddenResponse     = mustMarshal(Response{Code: 403, Message: "Forbidden"})
-	internalErrorResponse = mustMarshal(Response{Code: 500, Message: "Internal Server Error"})
+	successResponse = mustMarshal(Response{Code: 200, Message: "Success"})
+	createdResponse = mustMarshal(Response{Code: 201, Message: "Created"})
 )
 
+// statusCodes maps HTTP statuses to the catalogue code used to localize
+// messages that are not raised through an AppError.
+var statusCodes = map[int]string{
+	200: "SUCCESS",
+	201: "CREATED",
+	400: "BAD_REQUEST",
+	401: "UNAUTHORIZED",
+	403: "FORBIDDEN",
+	404: "NOT_FOUND",
+	405: "METHOD_NOT_ALLOWED",
+	409: "CONFLICT",
+	413: "REQUEST_TOO_LARGE",
+	415: "UNSUPPORTED_MEDIA_TYPE",
+	422: "UNPROCESSABLE_ENTITY",
+	429: "RATE_LIMITED",
+	500: "INTERNAL_ERROR",
+	502: "BAD_GATEWAY",
+	503: "SERVICE_UNAVAILABLE",
+	504: "GATEWAY_TIMEOUT",
+}
+
+// StatusCode returns the error code clients see for an HTTP status that
+// was not raised through an AppError.
+func StatusCode(httpCode int) string {
+	if code, ok := statusCodes[httpCode]; ok {
+		return code
+	}
+	if httpCode >= 500 {
+		return "INTERNAL_ERROR"
+	}
+	return "BAD_REQUEST"
+}
+
 func mustMarshal(v interface{}) []byte {
 	b, _ := json.Marshal(v)
 	return b
 }
 
 func ResponseJSON(c *fiber.Ctx, httpCode int, message string, data interface{}) {
-	if data == nil {
-		switch httpCode {
-		case 200:
-			if message == "Success" {
-				c.Status(httpCode).Send(successResponse)
-				return
-			}
-		case 201:
-			if message == "Created" {
-				c.Status(httpCode).Send(createdResponse)
-				return
-			}
-		case 400:
-			if message == "Bad Request" {
-				c.Status(httpCode).Send(badRequestResponse)
-				return
-			}
-		case 404:
-			if message == "Not Found" {
-				c.Status(httpCode).Send(notFoundResponse)
-				return
-			}
-		case 401:
-			if message == "Unauthorized" {
-				c.Status(httpCode).Send(unauthorizedResponse)
-				return
-			}
-		case 403:
-			if message == "Forbidden" {
-				c.Status(httpCode).Send(forbiddenResponse)
-				return
-			}
-		case 500:
-			if message == "Internal Server Error" {
-				c.Status(httpCode).Send(internalErrorResponse)
-				return
-			}
+	if httpCode >= 400 {
+		responseProblem(c, httpCode, StatusCode(httpCode), message, data)
+		return
+	}
+	language := i18n.Language(c)
+	c.Set(fiber.HeaderContentLanguage, language)
+
+	if language != i18n.SourceLanguage {
+		message = i18n.Translate(language, statusCodes[httpCode], message)
+	} else if data == nil {
+		switch {
+		case httpCode == 200 && message == "Success":
+			c.Status(httpCode).Send(successResponse)
+			return
+		case httpCode == 201 && message == "Created":
+			c.Status(httpCode).Send(createdResponse)
+			return
 		}
 	}
 
@@ -102,33 +112,20 @@ func ResponseJSON(c *fiber.Ctx, httpCode int, message string, data interface{})
 	})
 }
 
-func ResponseOK(c *fiber.Ctx, data interface{}) {
-	ResponseJSON(c, 200, "Success", data)
-}
-
-func ResponseNotFound(c *fiber.Ctx) {
-	ResponseJSON(c, 404, "Not Found", nil)
-}
-
-func ResponseUnauthorized(c *fiber.Ctx) {
-	ResponseJSON(c, 401, "Unauthorized", nil)
-}
-
-func ResponseBadRequest(c *fiber.Ctx, message string) {
-	if message == "" {
-		message = "Bad Request"
+// ResponseAppError renders an AppError as a problem document, localizing
+// its message by error code.
+func ResponseAppError(c *fiber.Ctx, appErr *app_error.AppError) {
+	code := appErr.Code
+	if code == "" {
+		code = StatusCode(appErr.StatusCode)
 	}
-	ResponseJSON(c, 400, message, nil)
+	responseProblem(c, appErr.StatusCode, code, appErr.Message, appErr.Data)
 }
 
-func ResponseForbidden(c *fiber.Ctx) {
-	ResponseJSON(c, 403, "Forbidden", nil)
+func ResponseOK(c *fiber.Ctx, data interface{}) {
+	ResponseJSON(c, 200, "Success", data)
 }
 
 func ResponseCreated(c *fiber.Ctx, data interface{}) {
 	ResponseJSON(c, 201, "Created", data)
 }
-
-func ResponseInternalError(c *fiber.Ctx, err error) {
-	ResponseJSON(c, 500, "Internal Server Error", err.Error())
-}
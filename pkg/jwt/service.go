@@ -16,6 +16,7 @@ type Claims struct {
 	UserType         string    `json:"user_type"`
 	DisplayName      string    `json:"display_name"`
 	IsActive         bool      `json:"is_active"`
+	Language         string    `json:"language,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -33,12 +34,13 @@ func NewJWTService(secretKey, issuer string, tokenLifetime time.Duration) *JWTSe
 	}
 }
 
-func (j *JWTService) GenerateToken(userID uuid.UUID, kratosIdentityID, email string, isActive bool) (string, error) {
+func (j *JWTService) GenerateToken(userID uuid.UUID, kratosIdentityID, email, userType string, isActive bool) (string, error) {
 	now := time.Now()
 	claims := &Claims{
 		UserID:           userID,
 		KratosIdentityID: kratosIdentityID,
 		Email:            email,
+		UserType:         userType,
 		IsActive:         isActive,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    j.issuer,
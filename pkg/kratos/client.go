@@ -1,11 +1,15 @@
 package kratos
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"s29-be/pkg/metrics"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type Session struct {
@@ -72,12 +76,14 @@ func NewClient(publicURL, adminURL string) *Client {
 		adminURL:  adminURL,
 		client: &http.Client{
 			Timeout: 10 * time.Second,
+			// Traces each call and forwards the trace context to Kratos
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 	}
 }
 
 // VerifySession validates a session using Kratos /sessions/whoami endpoint
-func (c *Client) VerifySession(sessionToken string) (*Session, error) {
+func (c *Client) VerifySession(ctx context.Context, sessionToken string) (*Session, error) {
 	if sessionToken == "" {
 		return nil, &KratosError{
 			Code:    401,
@@ -87,7 +93,7 @@ func (c *Client) VerifySession(sessionToken string) (*Session, error) {
 	}
 
 	url := fmt.Sprintf("%s/sessions/whoami", c.publicURL)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -97,7 +103,7 @@ func (c *Client) VerifySession(sessionToken string) (*Session, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req, "whoami")
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -141,6 +147,45 @@ func (c *Client) VerifySession(sessionToken string) (*Session, error) {
 	return &session, nil
 }
 
+// Ping checks that Kratos is ready to serve.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.publicURL+"/health/ready", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req, "health")
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kratos not ready: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// do sends req and records its latency and outcome under operation.
+// Responses Kratos rejected, such as an expired session, are told apart from
+// failures of Kratos itself.
+func (c *Client) do(req *http.Request, operation string) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	metrics.KratosDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+
+	outcome := "ok"
+	switch {
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		outcome = "error"
+	case resp.StatusCode >= http.StatusBadRequest:
+		outcome = "rejected"
+	}
+	metrics.KratosRequests.WithLabelValues(operation, outcome).Inc()
+
+	return resp, err
+}
+
 func (c *Client) GetPublicURL() string {
 	return c.publicURL
 }
@@ -0,0 +1,177 @@
+// Package lifecycle starts the parts of the process in order and stops
+// them in reverse, so everything is stopped before the things it depends
+// on: the HTTP server before the workers, the workers before Redis and the
+// database.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Hook is a part of the process with something to do on start or stop.
+// Either function may be nil.
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	// Stop should return once the part has finished its work or ctx
+	// expires, whichever comes first
+	Stop func(ctx context.Context) error
+}
+
+type Manager struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+}
+
+func New() *Manager {
+	return &Manager{}
+}
+
+// Append adds a hook. Hooks start in the order they were appended and
+// stop in reverse.
+func (m *Manager) Append(hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// Start runs the start hooks in order. If one fails, the hooks already
+// started are stopped again and the error is returned.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := m.hooks[m.started:]
+	m.mu.Unlock()
+
+	for _, hook := range hooks {
+		if hook.Start != nil {
+			if err := hook.Start(ctx); err != nil {
+				startErr := fmt.Errorf("start %s: %w", hook.Name, err)
+				return errors.Join(startErr, m.Stop(context.WithoutCancel(ctx)))
+			}
+		}
+		m.mu.Lock()
+		m.started++
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// Stop runs the stop hooks of the started hooks in reverse order. Every
+// hook is called even after ctx expires, so resources are still released,
+// and all failures are returned together.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := m.hooks[:m.started]
+	m.started = 0
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.Stop == nil {
+			continue
+		}
+		start := time.Now()
+		if err := hook.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+			slog.ErrorContext(ctx, "Lifecycle: stop failed", slog.String("hook", hook.Name), slog.Any("error", err))
+			continue
+		}
+		slog.InfoContext(ctx, "Lifecycle: stopped", slog.String("hook", hook.Name), slog.Duration("took", time.Since(start)))
+	}
+	return errors.Join(errs...)
+}
+
+// Run starts every hook, waits for SIGINT or SIGTERM and stops them again
+// within timeout. A second signal during shutdown exits immediately.
+func (m *Manager) Run(timeout time.Duration) error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	if err := m.Start(context.Background()); err != nil {
+		return err
+	}
+
+	received := <-signals
+	slog.Info("Gracefully shutting down...", slog.String("signal", received.String()), slog.Duration("timeout", timeout))
+
+	go func() {
+		received := <-signals
+		slog.Error("Second signal received, exiting without finishing shutdown", slog.String("signal", received.String()))
+		os.Exit(1)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return m.Stop(ctx)
+}
+
+// Cancellable returns a hook for a part that runs until the context given
+// to start is cancelled, such as a set of consumer goroutines. Stop cancels
+// that context and then calls wait, if set, to let in-flight work finish.
+func Cancellable(name string, start func(ctx context.Context) error, wait func(ctx context.Context) error) Hook {
+	var cancel context.CancelFunc
+	return Hook{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			var runCtx context.Context
+			runCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+			if err := start(runCtx); err != nil {
+				cancel()
+				return err
+			}
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			if wait == nil {
+				return nil
+			}
+			return wait(ctx)
+		},
+	}
+}
+
+// Background returns a hook running fn in its own goroutine from start
+// until stop cancels fn's context. Stop waits for fn to return.
+func Background(name string, fn func(ctx context.Context)) Hook {
+	done := make(chan struct{})
+	return Cancellable(name,
+		func(ctx context.Context) error {
+			go func() {
+				defer close(done)
+				fn(ctx)
+			}()
+			return nil
+		},
+		func(ctx context.Context) error {
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	)
+}
+
+// Closer returns a hook releasing a resource, such as a connection pool,
+// on stop.
+func Closer(name string, close func() error) Hook {
+	return Hook{
+		Name: name,
+		Stop: func(context.Context) error {
+			return close()
+		},
+	}
+}
@@ -0,0 +1,148 @@
+// Package metrics exposes Prometheus metrics for the API on a separate
+// internal port, so they are never reachable through the public router.
+// Collectors for infrastructure are registered once their clients exist;
+// request and business counters are package variables incremented where
+// the work happens.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "s29"
+
+type Config struct {
+	Enabled bool `yaml:"enabled" env:"METRICS_ENABLED"`
+	// Port serves /metrics; keep it off the public load balancer
+	Port int `yaml:"port" env:"METRICS_PORT"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enabled: true,
+		Port:    9090,
+	}
+}
+
+// Registry holds every metric served on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests, by route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	KratosRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kratos_requests_total",
+		Help:      "Calls to Kratos, by operation and outcome (ok, rejected or error).",
+	}, []string{"operation", "outcome"})
+
+	KratosDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "kratos_request_duration_seconds",
+		Help:      "Latency of calls to Kratos, by operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	Registrations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Users created after Kratos registration.",
+	})
+
+	Logins = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Kratos sessions exchanged for an access token.",
+	})
+
+	LessonsCompleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "lessons_completed_total",
+		Help:      "Recorded lesson attempts, by result (passed or failed).",
+	}, []string{"result"})
+
+	XPAwarded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "xp_awarded_total",
+		Help:      "Experience points awarded for lessons.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		KratosRequests,
+		KratosDuration,
+		Registrations,
+		Logins,
+		LessonsCompleted,
+		XPAwarded,
+	)
+}
+
+// RegisterDB exports the connection pool statistics of db.
+func RegisterDB(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// Server serves /metrics from Registry.
+type Server struct {
+	server *http.Server
+}
+
+func NewServer(config Config) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{
+		ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelError),
+	}))
+	return &Server{
+		server: &http.Server{
+			Addr:              fmt.Sprintf(":%d", config.Port),
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Start binds the port and serves in the background until Shutdown.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Metrics server", slog.Any("error", err))
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"s29-be/pkg/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterPromoter exports the backlog of the delayed sets and the state of
+// promoter. Backlogs are read from Redis on each scrape, so every replica
+// reports them whether or not it leads.
+func RegisterPromoter(promoter *cache.Promoter) error {
+	return Registry.Register(&promoterCollector{promoter: promoter})
+}
+
+var (
+	delayedDue = prometheus.NewDesc(namespace+"_queue_delayed_due",
+		"Delayed jobs whose time has come but that are not yet promoted.", []string{"queue"}, nil)
+	delayedLag = prometheus.NewDesc(namespace+"_queue_delayed_lag_seconds",
+		"How long the oldest due delayed job has been waiting for promotion.", []string{"queue"}, nil)
+	promoterLeader = prometheus.NewDesc(namespace+"_delayed_promoter_leader",
+		"1 if this replica's promoter holds the leader lock.", nil, nil)
+	promoterPromoted = prometheus.NewDesc(namespace+"_delayed_promoted_total",
+		"Delayed jobs promoted by this replica.", nil, nil)
+)
+
+type promoterCollector struct {
+	promoter *cache.Promoter
+}
+
+func (c *promoterCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{delayedDue, delayedLag, promoterLeader, promoterPromoted} {
+		ch <- desc
+	}
+}
+
+func (c *promoterCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueStatsTimeout)
+	defer cancel()
+
+	stats, err := c.promoter.Stats(ctx)
+	if err != nil {
+		// A missing series is easier to alert on than a stale value
+		slog.WarnContext(ctx, "Metrics: reading delayed job stats failed", slog.Any("error", err))
+		return
+	}
+
+	leader := 0.0
+	if stats.Leader {
+		leader = 1
+	}
+	ch <- prometheus.MustNewConstMetric(promoterLeader, prometheus.GaugeValue, leader)
+	ch <- prometheus.MustNewConstMetric(promoterPromoted, prometheus.CounterValue, float64(stats.Promoted))
+	for name, backlog := range stats.QueueBacklogs {
+		ch <- prometheus.MustNewConstMetric(delayedDue, prometheus.GaugeValue, float64(backlog.Due), name)
+		ch <- prometheus.MustNewConstMetric(delayedLag, prometheus.GaugeValue, backlog.Lag.Seconds(), name)
+	}
+}
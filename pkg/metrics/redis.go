@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"s29-be/pkg/cache"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queueStatsTimeout bounds the Redis round trips made for one scrape.
+const queueStatsTimeout = 2 * time.Second
+
+// RegisterRedis exports the connection pool statistics of client and the
+// depths of the named queues. Queue sizes are read from Redis on each
+// scrape.
+func RegisterRedis(client *cache.Client, queueNames []string) error {
+	return Registry.Register(&redisCollector{client: client, queueNames: queueNames})
+}
+
+var (
+	redisHits = prometheus.NewDesc(namespace+"_redis_pool_hits_total",
+		"Times a free connection was found in the pool.", nil, nil)
+	redisMisses = prometheus.NewDesc(namespace+"_redis_pool_misses_total",
+		"Times no free connection was found in the pool.", nil, nil)
+	redisTimeouts = prometheus.NewDesc(namespace+"_redis_pool_timeouts_total",
+		"Times waiting for a connection timed out.", nil, nil)
+	redisTotalConns = prometheus.NewDesc(namespace+"_redis_pool_connections",
+		"Connections in the pool.", nil, nil)
+	redisIdleConns = prometheus.NewDesc(namespace+"_redis_pool_idle_connections",
+		"Idle connections in the pool.", nil, nil)
+	redisStaleConns = prometheus.NewDesc(namespace+"_redis_pool_stale_connections_total",
+		"Stale connections removed from the pool.", nil, nil)
+
+	queueJobs = prometheus.NewDesc(namespace+"_queue_jobs",
+		"Jobs in a queue, by state (pending, delayed, processing or dead).", []string{"queue", "state"}, nil)
+	queueWorkers = prometheus.NewDesc(namespace+"_queue_workers",
+		"Workers consuming a queue.", []string{"queue"}, nil)
+	queueOldestAge = prometheus.NewDesc(namespace+"_queue_oldest_job_age_seconds",
+		"How long the next job to be taken has been waiting.", []string{"queue"}, nil)
+)
+
+type redisCollector struct {
+	client     *cache.Client
+	queueNames []string
+}
+
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		redisHits, redisMisses, redisTimeouts, redisTotalConns, redisIdleConns, redisStaleConns,
+		queueJobs, queueWorkers, queueOldestAge,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	pool := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisHits, prometheus.CounterValue, float64(pool.Hits))
+	ch <- prometheus.MustNewConstMetric(redisMisses, prometheus.CounterValue, float64(pool.Misses))
+	ch <- prometheus.MustNewConstMetric(redisTimeouts, prometheus.CounterValue, float64(pool.Timeouts))
+	ch <- prometheus.MustNewConstMetric(redisTotalConns, prometheus.GaugeValue, float64(pool.TotalConns))
+	ch <- prometheus.MustNewConstMetric(redisIdleConns, prometheus.GaugeValue, float64(pool.IdleConns))
+	ch <- prometheus.MustNewConstMetric(redisStaleConns, prometheus.CounterValue, float64(pool.StaleConns))
+
+	ctx, cancel := context.WithTimeout(context.Background(), queueStatsTimeout)
+	defer cancel()
+	for _, name := range c.queueNames {
+		stats, err := c.client.QueueStats(ctx, name)
+		if err != nil {
+			// A missing series is easier to alert on than a stale value
+			slog.WarnContext(ctx, "Metrics: reading queue stats failed", slog.String("queue", name), slog.Any("error", err))
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(queueJobs, prometheus.GaugeValue, float64(stats.Pending), name, "pending")
+		ch <- prometheus.MustNewConstMetric(queueJobs, prometheus.GaugeValue, float64(stats.Delayed), name, "delayed")
+		ch <- prometheus.MustNewConstMetric(queueJobs, prometheus.GaugeValue, float64(stats.Processing), name, "processing")
+		ch <- prometheus.MustNewConstMetric(queueJobs, prometheus.GaugeValue, float64(stats.DeadLetters), name, "dead")
+		ch <- prometheus.MustNewConstMetric(queueWorkers, prometheus.GaugeValue, float64(stats.Workers), name)
+		ch <- prometheus.MustNewConstMetric(queueOldestAge, prometheus.GaugeValue, stats.OldestAge.Seconds(), name)
+	}
+}
@@ -2,8 +2,11 @@
 package middleware
 
 import (
+	"log/slog"
 	"s29-be/internal/auth/application"
-	jsonResponse "s29-be/pkg/json"
+	appError "s29-be/pkg/error"
+	"s29-be/pkg/i18n"
+	"s29-be/pkg/logging"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,22 +26,19 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
-			jsonResponse.ResponseUnauthorized(c)
-			return nil
+			return appError.NewUnauthorizedError(nil, "")
 		}
 
 		// Extract Bearer token
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenString == authHeader {
-			jsonResponse.ResponseBadRequest(c, "Invalid authorization header format")
-			return nil
+			return appError.NewBadRequestError(nil, "Invalid authorization header format")
 		}
 
 		// Validate token
-		claims, err := m.authService.ValidateJWT(tokenString)
+		claims, err := m.authService.ValidateJWT(c.UserContext(), tokenString)
 		if err != nil {
-			jsonResponse.ResponseUnauthorized(c)
-			return nil
+			return appError.NewUnauthorizedError(nil, "")
 		}
 
 		// Store claims in context for use in handlers
@@ -46,7 +46,28 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 		c.Locals("user_id", claims.UserID)
 		c.Locals("kratos_identity_id", claims.KratosIdentityID)
 		c.Locals("user_email", claims.Email)
+		c.Locals("user_type", claims.UserType)
+		c.SetUserContext(logging.With(c.UserContext(), slog.String("user_id", claims.UserID.String())))
+
+		// A saved preference beats Accept-Language, an explicit ?lang= beats both
+		if language := i18n.Normalize(claims.Language); language != "" && c.Query("lang") == "" {
+			c.Locals(i18n.LocalsKey, language)
+		}
 
 		return c.Next()
 	}
 }
+
+// RequireRole must run after RequireAuth and only lets users with one of the given roles through.
+func (m *AuthMiddleware) RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userType, _ := c.Locals("user_type").(string)
+		for _, role := range roles {
+			if userType == role {
+				return c.Next()
+			}
+		}
+
+		return appError.NewForbiddenError(nil, "")
+	}
+}
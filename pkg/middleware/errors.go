@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	appError "s29-be/pkg/error"
+	jsonResponse "s29-be/pkg/json"
+	"s29-be/pkg/kratos"
+	"s29-be/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Postgres error codes mapped to client errors.
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgInvalidTextRepresent = "22P02"
+)
+
+type ErrorHandlerConfig struct {
+	// ShowDetails puts the messages of server errors in responses. They
+	// can name queries and internal hosts, so production leaves them to the
+	// request log
+	ShowDetails bool
+}
+
+// ErrorHandler renders every error returned by a handler or middleware as
+// an application/problem+json document, so handlers can simply return
+// errors. Errors that are not AppErrors are mapped by AppErrorFrom.
+func ErrorHandler(config ErrorHandlerConfig) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		appErr := AppErrorFrom(err)
+		if appErr.StatusCode >= fiber.StatusInternalServerError {
+			appErr = serverError(appErr, config.ShowDetails)
+		}
+		jsonResponse.ResponseAppError(c, appErr)
+		return nil
+	}
+}
+
+// serverError keeps the code and status of a server error but drops its
+// message and data unless details are shown, in which case the cause is
+// added to the message.
+func serverError(appErr *appError.AppError, showDetails bool) *appError.AppError {
+	if !showDetails {
+		return &appError.AppError{
+			Err:        appErr.Err,
+			StatusCode: appErr.StatusCode,
+			Code:       appErr.Code,
+		}
+	}
+	if appErr.Err == nil || appErr.Message == appErr.Err.Error() {
+		return appErr
+	}
+	detailed := *appErr
+	detailed.Message = appErr.Err.Error()
+	if appErr.Message != "" && appErr.Message != http.StatusText(appErr.StatusCode) {
+		detailed.Message = appErr.Message + ": " + appErr.Err.Error()
+	}
+	return &detailed
+}
+
+// AppErrorFrom returns err as an AppError. Errors of the database, Kratos,
+// the validator and Fiber itself get the status and code clients should
+// see; anything else is an internal error.
+func AppErrorFrom(err error) *appError.AppError {
+	if appErr, ok := appError.GetAppError(err); ok {
+		return appErr
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return &appError.AppError{
+			Err:        err,
+			StatusCode: fiberErr.Code,
+			Message:    fiberErr.Message,
+			Code:       jsonResponse.StatusCode(fiberErr.Code),
+		}
+	}
+
+	if appErr, ok := validation.FromError(err); ok {
+		return appErr
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return appError.NewNotFoundError(err, "")
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return appError.NewConflictError(err, "")
+		case pgForeignKeyViolation:
+			return appError.NewConflictError(err, "A referenced resource does not exist")
+		case pgNotNullViolation, pgCheckViolation:
+			return appError.NewUnprocessableEntityError(err, "")
+		case pgInvalidTextRepresent:
+			return appError.NewBadRequestError(err, "")
+		}
+	}
+
+	var kratosErr *kratos.KratosError
+	if errors.As(err, &kratosErr) {
+		switch {
+		case kratosErr.Code == http.StatusUnauthorized || kratosErr.Code == http.StatusForbidden:
+			return appError.NewUnauthorizedError(err, kratosErr.Message)
+		case kratosErr.Code == http.StatusNotFound:
+			return appError.NewNotFoundError(err, "")
+		case kratosErr.Code >= http.StatusBadRequest && kratosErr.Code < http.StatusInternalServerError:
+			return appError.NewBadRequestError(err, kratosErr.Message)
+		default:
+			return statusError(err, http.StatusBadGateway)
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return statusError(err, http.StatusGatewayTimeout)
+	}
+
+	return appError.NewInternalError(err, "")
+}
+
+func statusError(err error, status int) *appError.AppError {
+	return &appError.AppError{
+		Err:        err,
+		StatusCode: status,
+		Code:       jsonResponse.StatusCode(status),
+	}
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"s29-be/pkg/cache"
+	appError "s29-be/pkg/error"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+type IdempotencyConfig struct {
+	// LockTTL bounds how long a duplicate is rejected as in progress when
+	// the replica handling the original dies
+	LockTTL time.Duration
+	// TTL is how long responses are kept for replay
+	TTL time.Duration
+	// FailClosed rejects keyed requests with a 503 while Redis is
+	// unavailable, instead of running them without duplicate detection
+	FailClosed bool
+}
+
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		LockTTL: time.Minute,
+		TTL:     24 * time.Hour,
+	}
+}
+
+// Idempotency makes POST and PATCH requests carrying an Idempotency-Key
+// header safe to retry. The first request with a key runs and its response
+// is stored; retries with the same method, path, query and body get that
+// response again with Idempotent-Replayed set, while a different request
+// under the same key is rejected with a 422. A duplicate arriving while the
+// original still runs gets a 409. Server errors are not stored, so those
+// requests can be retried for real. Once a request has succeeded its key is
+// never freed for another run, even if storing the response fails.
+//
+// Keys are scoped to the authenticated user, or to the client IP, so the
+// middleware belongs after RequireAuth on protected routes. When Redis is
+// unavailable requests run without protection, or get a 503 with
+// FailClosed set.
+func Idempotency(client *cache.Client, config IdempotencyConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodPost && c.Method() != fiber.MethodPatch {
+			return c.Next()
+		}
+		idempotencyKey := c.Get(HeaderIdempotencyKey)
+		if idempotencyKey == "" {
+			return c.Next()
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			return appError.NewBadRequestError(nil, "Idempotency-Key must be at most 255 characters")
+		}
+
+		scope := KeyByUser(c)
+		if scope == "" {
+			scope = KeyByIP(c)
+		}
+		key := "idempotency:" + scope + ":" + idempotencyKey
+		fingerprint := requestFingerprint(c)
+
+		token, existing, err := client.ClaimIdempotencyKey(c.UserContext(), key, fingerprint, config.LockTTL)
+		if err != nil {
+			if config.FailClosed {
+				slog.ErrorContext(c.UserContext(), "Idempotency unavailable, rejecting request", slog.Any("error", err))
+				c.Set(fiber.HeaderRetryAfter, "1")
+				return statusError(err, fiber.StatusServiceUnavailable)
+			}
+			slog.WarnContext(c.UserContext(), "Idempotency unavailable, running request unprotected", slog.Any("error", err))
+			return c.Next()
+		}
+		if existing != nil {
+			return replay(c, existing, fingerprint)
+		}
+
+		// Release the claim if the handler fails or panics, so the client's
+		// retry runs again instead of waiting for LockTTL
+		succeeded := false
+		defer func() {
+			if succeeded {
+				return
+			}
+			if err := client.ReleaseIdempotencyKey(context.Background(), key, token); err != nil {
+				slog.ErrorContext(c.UserContext(), "Failed to release idempotency key", slog.Any("error", err))
+			}
+		}()
+
+		// Client errors are stored like any other response, so a retry gets
+		// the same rejection; server errors go to the error handler
+		if err := c.Next(); err != nil {
+			if AppErrorFrom(err).StatusCode >= fiber.StatusInternalServerError {
+				return err
+			}
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			return nil
+		}
+		succeeded = true
+
+		record := &cache.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		err = client.CompleteIdempotencyKey(context.Background(), key, token, record, config.TTL)
+		if err == nil {
+			return nil
+		}
+		slog.ErrorContext(c.UserContext(), "Failed to store idempotent response", slog.Any("error", err))
+		if errors.Is(err, cache.ErrIdempotencyClaimLost) {
+			return nil
+		}
+
+		// The request must still not run twice, so record that it completed
+		unavailable := &cache.IdempotencyRecord{Fingerprint: fingerprint, ResponseUnavailable: true}
+		if err := client.CompleteIdempotencyKey(context.Background(), key, token, unavailable, config.TTL); err != nil {
+			slog.ErrorContext(c.UserContext(), "Failed to mark idempotency key completed, keeping the claim", slog.Any("error", err))
+		}
+		return nil
+	}
+}
+
+func replay(c *fiber.Ctx, record *cache.IdempotencyRecord, fingerprint string) error {
+	if record.Fingerprint != fingerprint {
+		return appError.NewUnprocessableEntityError(nil, "Idempotency-Key was already used for a different request")
+	}
+	if !record.Completed {
+		c.Set(fiber.HeaderRetryAfter, "1")
+		return appError.NewConflictError(nil, "a request with this Idempotency-Key is still in progress")
+	}
+	if record.ResponseUnavailable {
+		return appError.NewConflictError(nil, "a request with this Idempotency-Key already completed, but its response is not available")
+	}
+
+	c.Set(HeaderIdempotencyReplayed, "true")
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.Status).Send(record.Body)
+}
+
+// requestFingerprint identifies what a request asks for, so a key reused
+// for another request can be told apart from a retry.
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Path()))
+	hash.Write([]byte{0})
+	hash.Write(c.Request().URI().QueryString())
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"s29-be/pkg/cache"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// testRedisAddrEnv names a Redis the idempotency tests may write to, e.g.
+// "localhost:6379".
+const testRedisAddrEnv = "TEST_REDIS_ADDR"
+
+// newIdempotentApp serves a route counting its runs behind Idempotency,
+// for a user of its own, and returns the key requests should send.
+func newIdempotentApp(t *testing.T, client *cache.Client, config IdempotencyConfig, status int) (*fiber.App, *int, string) {
+	t.Helper()
+
+	userID := uuid.New()
+	idempotencyKey := uuid.NewString()
+	t.Cleanup(func() {
+		_ = client.Del(context.Background(), "idempotency:user:"+userID.String()+":"+idempotencyKey)
+	})
+
+	runs := 0
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(ErrorHandlerConfig{})})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", userID)
+		return c.Next()
+	})
+	app.Use(Idempotency(client, config))
+	app.Post("/lessons/:id/complete", func(c *fiber.Ctx) error {
+		runs++
+		return c.Status(status).JSON(fiber.Map{"run": runs})
+	})
+	return app, &runs, idempotencyKey
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	client := testCacheClient(t)
+	app, runs, key := newIdempotentApp(t, client, DefaultIdempotencyConfig(), fiber.StatusOK)
+
+	first := complete(t, app, key, `{"score":90}`)
+	second := complete(t, app, key, `{"score":90}`)
+
+	if *runs != 1 {
+		t.Errorf("handler ran %d times, want 1", *runs)
+	}
+	if second.StatusCode != fiber.StatusOK || second.Header.Get(HeaderIdempotencyReplayed) != "true" {
+		t.Errorf("retry = %d (replayed %q), want a replayed 200", second.StatusCode, second.Header.Get(HeaderIdempotencyReplayed))
+	}
+	if first.Header.Get(HeaderIdempotencyReplayed) != "" {
+		t.Error("first request was marked as replayed")
+	}
+
+	if reused := complete(t, app, key, `{"score":40}`); reused.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Errorf("key reused for another body = %d, want %d", reused.StatusCode, fiber.StatusUnprocessableEntity)
+	}
+}
+
+func TestIdempotencyReleasesKeyAfterServerError(t *testing.T) {
+	client := testCacheClient(t)
+	app, runs, key := newIdempotentApp(t, client, DefaultIdempotencyConfig(), fiber.StatusServiceUnavailable)
+
+	complete(t, app, key, `{"score":90}`)
+	retry := complete(t, app, key, `{"score":90}`)
+
+	if *runs != 2 {
+		t.Errorf("handler ran %d times, want the retry to run again", *runs)
+	}
+	if retry.Header.Get(HeaderIdempotencyReplayed) != "" {
+		t.Error("server error was replayed")
+	}
+}
+
+func TestIdempotencyWithoutRedis(t *testing.T) {
+	client := testCacheClient(t)
+	// Every command fails from here on, as it would with Redis down
+	client.Close()
+
+	closed := DefaultIdempotencyConfig()
+	closed.FailClosed = true
+	app, runs, key := newIdempotentApp(t, client, closed, fiber.StatusOK)
+	resp := complete(t, app, key, `{"score":90}`)
+	if resp.StatusCode != fiber.StatusServiceUnavailable || *runs != 0 {
+		t.Errorf("fail closed = %d after %d runs, want %d without running", resp.StatusCode, *runs, fiber.StatusServiceUnavailable)
+	}
+
+	app, runs, key = newIdempotentApp(t, client, DefaultIdempotencyConfig(), fiber.StatusOK)
+	resp = complete(t, app, key, `{"score":90}`)
+	if resp.StatusCode != fiber.StatusOK || *runs != 1 {
+		t.Errorf("fail open = %d after %d runs, want %d after one run", resp.StatusCode, *runs, fiber.StatusOK)
+	}
+}
+
+func complete(t *testing.T, app *fiber.App, key, body string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodPost, "/lessons/42/complete", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(HeaderIdempotencyKey, key)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// testCacheClient connects to the Redis named by TEST_REDIS_ADDR, skipping
+// the test when the variable is unset.
+func testCacheClient(t *testing.T) *cache.Client {
+	t.Helper()
+
+	addr := os.Getenv(testRedisAddrEnv)
+	if addr == "" {
+		t.Skipf("%s is not set", testRedisAddrEnv)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := cache.NewClient(&cache.Config{Host: host, Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"s29-be/pkg/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Locale negotiates the response language from the "lang" query parameter
+// or the Accept-Language header. RequireAuth later prefers the user's saved
+// language unless the query parameter was given.
+func Locale() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		language := i18n.Normalize(c.Query("lang"))
+		if language == "" {
+			language = i18n.Negotiate(c.Get(fiber.HeaderAcceptLanguage))
+		}
+
+		c.Locals(i18n.LocalsKey, language)
+		c.Vary(fiber.HeaderAcceptLanguage)
+
+		return c.Next()
+	}
+}
@@ -1,93 +1,69 @@
 package middleware
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
+	"log/slog"
+	"s29-be/pkg/logging"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
-type LogEntry struct {
-	TraceID      string            `json:"trace_id"`
-	SpanID       string            `json:"span_id"`
-	Timestamp    time.Time         `json:"timestamp"`
-	Method       string            `json:"method"`
-	Path         string            `json:"path"`
-	Status       int               `json:"status"`
-	Latency      float64           `json:"latency_ms"`
-	ClientIP     string            `json:"client_ip"`
-	UserAgent    string            `json:"user_agent"`
-	RequestBody  string            `json:"request_body,omitempty"`
-	ResponseBody string            `json:"response_body,omitempty"`
-	Headers      map[string]string `json:"headers,omitempty"`
-	ServiceName  string            `json:"service_name"`
-	Environment  string            `json:"environment"`
-	Error        string            `json:"error,omitempty"`
-}
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
 
+// Logger assigns each request an ID, taken from X-Request-ID when the
+// caller or a proxy sent one, and echoes it in the response. The ID is put
+// in the request's user context, so services and queries that log with
+// c.UserContext() are correlated with the request line logged here once
+// the response is ready. Query strings are not logged since they may carry
+// tokens.
 func Logger() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
-		// Process request
+		requestID := c.Get(HeaderRequestID)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		c.Set(HeaderRequestID, requestID)
+		c.Locals("request_id", requestID)
+		c.SetUserContext(logging.WithRequestID(c.UserContext(), requestID))
+
 		err := c.Next()
 
-		// Calculate latency
-		latency := time.Since(start)
+		status := responseStatus(c, err)
 
-		// Create log entry
-		logEntry := LogEntry{
-			TraceID:     c.Get("X-Trace-ID", generateID()),
-			SpanID:      c.Get("X-Span-ID", generateID()),
-			Timestamp:   time.Now(),
-			Method:      c.Method(),
-			Path:        c.Path(),
-			Status:      c.Response().StatusCode(),
-			Latency:     float64(latency.Nanoseconds()) / 1e6, // Convert to milliseconds
-			ClientIP:    c.IP(),
-			UserAgent:   c.Get("User-Agent"),
-			ServiceName: "s29-api",
-			Environment: getEnv("APP_ENV", "development"),
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
 		}
 
-		// Add error if exists
-		if err != nil {
-			logEntry.Error = err.Error()
+		attrs := []slog.Attr{
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.IP()),
+			slog.String("user_agent", c.Get(fiber.HeaderUserAgent)),
 		}
-
-		// Log based on environment
-		if getEnv("APP_ENV", "development") == "production" {
-			// JSON format for production
-			logJSON, _ := json.Marshal(logEntry)
-			fmt.Println(string(logJSON))
-		} else {
-			// Human-readable format for development
-			fmt.Printf("[%s] %d - %s %s | %s | %.2fms | %s\n",
-				logEntry.Timestamp.Format("2006-01-02 15:04:05"),
-				logEntry.Status,
-				logEntry.Method,
-				logEntry.Path,
-				logEntry.ClientIP,
-				logEntry.Latency,
-				logEntry.UserAgent,
-			)
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
 		}
+		slog.LogAttrs(c.UserContext(), level, "request", attrs...)
 
 		return err
 	}
 }
 
-// Simple ID generator for trace/span IDs
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
-}
-
-// Helper function to get environment variables with default
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// responseStatus is the status the client will receive once Fiber's error
+// handler has turned err into a response.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
 	}
-	return defaultValue
+	return AppErrorFrom(err).StatusCode
 }
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"s29-be/pkg/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics counts requests and records their latency by matched route, so
+// IDs in paths do not create a series per resource.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+
+		labels := []string{c.Method(), c.Route().Path, strconv.Itoa(responseStatus(c, err))}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"s29-be/pkg/cache"
+	appError "s29-be/pkg/error"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RateLimitOff disables a named limit on a deployment.
+const RateLimitOff = "off"
+
+// RateLimitKey identifies the caller a request is counted against. An empty
+// key falls back to the client IP.
+type RateLimitKey func(c *fiber.Ctx) string
+
+// KeyByIP counts requests per client IP.
+func KeyByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// KeyByUser counts requests per authenticated user and must run after
+// RequireAuth; anonymous requests are counted per IP.
+func KeyByUser(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("user_id").(uuid.UUID); ok {
+		return "user:" + userID.String()
+	}
+	return ""
+}
+
+// KeyByHeader counts requests per value of an API key header. Values are
+// hashed so keys never appear in Redis.
+func KeyByHeader(header string) RateLimitKey {
+	return func(c *fiber.Ctx) string {
+		value := c.Get(header)
+		if value == "" {
+			return ""
+		}
+		sum := sha256.Sum256([]byte(value))
+		return "key:" + hex.EncodeToString(sum[:16])
+	}
+}
+
+// RateLimiter hands out Redis-backed limits shared by all replicas. Each
+// limit has a name, so deployments can override or disable it through
+// RATE_LIMITS without code changes.
+type RateLimiter struct {
+	client    *cache.Client
+	overrides map[string]string
+}
+
+func NewRateLimiter(client *cache.Client, overrides map[string]string) *RateLimiter {
+	return &RateLimiter{
+		client:    client,
+		overrides: overrides,
+	}
+}
+
+// ParseRateLimits reads RATE_LIMITS, a semicolon separated list of
+// name=requests/window pairs, e.g. "auth=5/1m;api=off".
+func ParseRateLimits(value string) (map[string]string, error) {
+	overrides := map[string]string{}
+	if value == "" {
+		return overrides, nil
+	}
+
+	for _, pair := range strings.Split(value, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(pair, "=")
+		name, spec = strings.TrimSpace(name), strings.TrimSpace(spec)
+		if !ok || name == "" || spec == "" {
+			return nil, fmt.Errorf("RATE_LIMITS: expected name=requests/window, got %q", pair)
+		}
+		if spec != RateLimitOff {
+			if _, err := parseRateLimit(spec); err != nil {
+				return nil, fmt.Errorf("RATE_LIMITS: %s: %w", name, err)
+			}
+		}
+		overrides[name] = spec
+	}
+	return overrides, nil
+}
+
+func parseRateLimit(spec string) (cache.RateLimit, error) {
+	requests, window, ok := strings.Cut(spec, "/")
+	if !ok {
+		return cache.RateLimit{}, fmt.Errorf("expected requests/window, got %q", spec)
+	}
+	limit := cache.RateLimit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests <= 0 {
+		return limit, fmt.Errorf("invalid request count %q", requests)
+	}
+	if limit.Window, err = time.ParseDuration(window); err != nil || limit.Window <= 0 {
+		return limit, fmt.Errorf("invalid window %q", window)
+	}
+	return limit, nil
+}
+
+// Limit returns middleware allowing each caller, as identified by key, the
+// given number of requests. Responses carry RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset; rejected ones get a 429 with
+// Retry-After. When Redis is unavailable requests are let through.
+func (l *RateLimiter) Limit(name string, limit cache.RateLimit, key RateLimitKey) fiber.Handler {
+	if spec, ok := l.overrides[name]; ok {
+		if spec == RateLimitOff {
+			return func(c *fiber.Ctx) error { return c.Next() }
+		}
+		limit, _ = parseRateLimit(spec)
+	}
+
+	return func(c *fiber.Ctx) error {
+		caller := key(c)
+		if caller == "" {
+			caller = KeyByIP(c)
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), time.Second)
+		result, err := l.client.AllowRate(ctx, "ratelimit:"+name+":"+caller, limit)
+		cancel()
+		if err != nil {
+			slog.WarnContext(c.UserContext(), "Rate limit unavailable, allowing request", slog.String("limit", name), slog.Any("error", err))
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return appError.NewTooManyRequestsError(nil, "")
+		}
+		return c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"s29-be/pkg/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newTracedApp records spans in memory and serves a route that queries
+// through a dry-run GORM connection, so callbacks run without a database.
+func newTracedApp(t *testing.T) (*fiber.App, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(tracing.DefaultConfig(), sdktrace.NewSimpleSpanProcessor(exporter))
+	tracing.Install(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(t.Context()) })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=tracing_test"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/courses/:id", func(c *fiber.Ctx) error {
+		var rows []map[string]interface{}
+		err := db.WithContext(c.UserContext()).Table("courses").Where("id = ?", c.Params("id")).Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app, exporter
+}
+
+func TestTracingRecordsServerAndQuerySpans(t *testing.T) {
+	app, exporter := newTracedApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/courses/42", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, fiber.StatusOK)
+	}
+
+	spans := exporter.GetSpans()
+	server := findSpan(t, spans, trace.SpanKindServer)
+	query := findSpan(t, spans, trace.SpanKindClient)
+
+	if server.Name != "GET /courses/:id" {
+		t.Errorf("server span name = %q, want %q", server.Name, "GET /courses/:id")
+	}
+	wantAttributes := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.HTTPRoute("/courses/:id"),
+		semconv.URLPath("/courses/42"),
+		semconv.HTTPResponseStatusCode(fiber.StatusOK),
+	}
+	for _, want := range wantAttributes {
+		if got, ok := attributeValue(server.Attributes, want.Key); !ok || got != want.Value {
+			t.Errorf("server span %s = %v, want %v", want.Key, got.Emit(), want.Value.Emit())
+		}
+	}
+
+	if query.Name != "SELECT courses" {
+		t.Errorf("query span name = %q, want %q", query.Name, "SELECT courses")
+	}
+	if query.SpanContext.TraceID() != server.SpanContext.TraceID() {
+		t.Errorf("query span trace ID = %s, want %s", query.SpanContext.TraceID(), server.SpanContext.TraceID())
+	}
+	if query.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("query span parent = %s, want the server span %s", query.Parent.SpanID(), server.SpanContext.SpanID())
+	}
+	if got, ok := attributeValue(query.Attributes, semconv.DBCollectionNameKey); !ok || got.AsString() != "courses" {
+		t.Errorf("query span %s = %v, want courses", semconv.DBCollectionNameKey, got.Emit())
+	}
+}
+
+func TestTracingContinuesCallerTrace(t *testing.T) {
+	app, exporter := newTracedApp(t)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(fiber.MethodGet, "/courses/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	server := findSpan(t, exporter.GetSpans(), trace.SpanKindServer)
+	if got := server.SpanContext.TraceID().String(); got != traceID {
+		t.Errorf("server span trace ID = %s, want the caller's %s", got, traceID)
+	}
+	if got := server.Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("server span parent = %s, want the caller's span", got)
+	}
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, kind trace.SpanKind) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.SpanKind == kind {
+			return span
+		}
+	}
+	t.Fatalf("no %s span among %d recorded", kind, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func attributeValue(attributes []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
@@ -1,21 +1,19 @@
 package models
 
-// HealthResponse represents the health check response
-type HealthResponse struct {
-	Status    string `json:"status" example:"ok"`
-	Timestamp string `json:"timestamp" example:"2023-08-31T12:00:00Z"`
-	Version   string `json:"version" example:"1.0.0"`
-}
-
 // PingResponse represents the ping response
 type PingResponse struct {
 	Message   string `json:"message" example:"pong"`
 	Timestamp string `json:"timestamp" example:"2023-08-31T12:00:00Z"`
 }
 
-// ErrorResponse represents an error response
+// ErrorResponse represents an application/problem+json error response
 type ErrorResponse struct {
-	Error   string `json:"error" example:"Internal server error"`
-	Code    int    `json:"code" example:"500"`
-	Details string `json:"details,omitempty" example:"Additional error details"`
+	Type     string      `json:"type" example:"urn:s29:problem:not-found"`
+	Title    string      `json:"title" example:"Not Found"`
+	Status   int         `json:"status" example:"404"`
+	Code     string      `json:"code" example:"NOT_FOUND"`
+	Detail   string      `json:"detail,omitempty" example:"course not found"`
+	Instance string      `json:"instance,omitempty" example:"/api/v1/courses/3f1c2a9e-0000-4000-8000-000000000000"`
+	TraceID  string      `json:"trace_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	Data     interface{} `json:"data,omitempty" swaggertype:"object"`
 }
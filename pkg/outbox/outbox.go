@@ -0,0 +1,67 @@
+// Package outbox makes event publishing part of the database transaction
+// that causes the event: publishers store the event in the outbox table and
+// a relay forwards committed rows to the event bus.
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"s29-be/pkg/events"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Message is an event waiting in, or already forwarded from, the outbox.
+// Sequence is assigned by the database on insert and orders publication.
+type Message struct {
+	ID            uuid.UUID       `json:"id" gorm:"primaryKey"`
+	Sequence      int64           `json:"sequence" gorm:"->"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	EventName     string          `json:"event_name"`
+	Payload       json.RawMessage `json:"payload" gorm:"type:jsonb"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	PublishedAt   *time.Time      `json:"published_at"`
+	Attempts      int             `json:"attempts"`
+	LastError     *string         `json:"last_error"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Envelope rebuilds the event as published, keeping the message ID so
+// subscribers can recognise a redelivery.
+func (m *Message) Envelope() *events.Envelope {
+	return &events.Envelope{
+		ID:         m.ID,
+		Name:       m.EventName,
+		OccurredAt: m.OccurredAt,
+		Payload:    m.Payload,
+	}
+}
+
+// Add stores event in the outbox. tx should be the transaction making the
+// change the event describes, so the event is published if and only if the
+// change commits. Events are published in the order they were added; for
+// one aggregate that is also commit order as long as the transactions adding
+// them lock the aggregate's row, as updates to it do.
+func Add(tx *gorm.DB, aggregateType string, aggregateID uuid.UUID, event events.Event) error {
+	envelope, err := events.NewEnvelope(event)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&Message{
+		ID:            envelope.ID,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventName:     envelope.Name,
+		Payload:       envelope.Payload,
+		OccurredAt:    envelope.OccurredAt,
+	}).Error
+}
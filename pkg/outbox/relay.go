@@ -0,0 +1,200 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"s29-be/pkg/events"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// relayLockKey is the Postgres advisory lock serialising relays across
+// replicas ("outbox" in ASCII).
+const relayLockKey int64 = 0x6f7574626f78
+
+type RelayConfig struct {
+	Interval time.Duration
+	// Batch caps the messages published per transaction
+	Batch int
+}
+
+// RelayStats are cumulative figures for the relay in this process.
+type RelayStats struct {
+	Published int64     `json:"published"`
+	LastRunAt time.Time `json:"last_run_at"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Relay forwards committed outbox messages to the event bus. Every replica
+// may run one; a transaction-scoped advisory lock lets only one publish at a
+// time, so messages go out in sequence order. A message the bus rejects holds
+// back the later messages of its aggregate only. A message is marked
+// published only after the bus accepted it, so delivery is at least once: a
+// crash between the two publishes it again with the same envelope ID.
+type Relay struct {
+	db     *gorm.DB
+	bus    *events.Bus
+	config RelayConfig
+
+	mu    sync.Mutex
+	stats RelayStats
+}
+
+func NewRelay(db *gorm.DB, bus *events.Bus, config RelayConfig) *Relay {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.Batch <= 0 {
+		config.Batch = 100
+	}
+	return &Relay{
+		db:     db,
+		bus:    bus,
+		config: config,
+	}
+}
+
+// Run publishes pending messages every interval until ctx is cancelled. A
+// full batch is followed immediately by the next one.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for ctx.Err() == nil {
+			published, err := r.relay(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.recordError(err)
+				}
+				break
+			}
+			if published < r.config.Batch {
+				break
+			}
+		}
+	}
+}
+
+type aggregateKey struct {
+	aggregateType string
+	aggregateID   uuid.UUID
+}
+
+// relay publishes one batch and returns how many messages went out.
+func (r *Relay) relay(ctx context.Context) (int, error) {
+	published := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", relayLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			// Another replica is relaying
+			return nil
+		}
+
+		// Messages queued behind a failed message of the same aggregate wait
+		// for it, without taking up room in the batch
+		var messages []Message
+		err := tx.Where("published_at IS NULL").
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox_messages blocker
+				WHERE blocker.published_at IS NULL
+				  AND blocker.attempts > 0
+				  AND blocker.aggregate_type = outbox_messages.aggregate_type
+				  AND blocker.aggregate_id = outbox_messages.aggregate_id
+				  AND blocker.sequence < outbox_messages.sequence)`).
+			Order("sequence ASC").
+			Limit(r.config.Batch).
+			Find(&messages).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		blocked := make(map[aggregateKey]bool)
+		for _, message := range messages {
+			key := aggregateKey{message.AggregateType, message.AggregateID}
+			if blocked[key] {
+				// Must not overtake the failed message of its aggregate
+				continue
+			}
+
+			updates := map[string]interface{}{
+				"published_at": now,
+				"attempts":     gorm.Expr("attempts + 1"),
+			}
+			var handlerErr *events.HandlerError
+			err := r.bus.PublishEnvelope(ctx, message.Envelope())
+			switch {
+			case err == nil:
+			case errors.As(err, &handlerErr):
+				// Delivered in process; a failing subscriber is not a reason
+				// to deliver again to the ones that succeeded
+				updates["last_error"] = err.Error()
+				r.recordError(fmt.Errorf("handle %s %s: %w", message.EventName, message.ID, err))
+			default:
+				blocked[key] = true
+				updateErr := tx.Model(&Message{}).Where("id = ?", message.ID).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": err.Error(),
+				}).Error
+				if updateErr != nil {
+					return updateErr
+				}
+				r.recordError(fmt.Errorf("publish %s %s: %w", message.EventName, message.ID, err))
+				continue
+			}
+
+			if err := tx.Model(&Message{}).Where("id = ?", message.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	r.stats.Published += int64(published)
+	r.stats.LastRunAt = time.Now()
+	r.mu.Unlock()
+	return published, nil
+}
+
+// Cleanup deletes messages published before cutoff and returns how many
+// were removed.
+func (r *Relay) Cleanup(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", cutoff).
+		Delete(&Message{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *Relay) Stats() RelayStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+func (r *Relay) recordError(err error) {
+	slog.Error("Outbox relay", slog.Any("error", err))
+	r.mu.Lock()
+	r.stats.LastError = err.Error()
+	r.mu.Unlock()
+}
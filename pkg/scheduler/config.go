@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ScheduleOff switches a job off on a deployment while keeping it listed.
+const ScheduleOff = "off"
+
+type Config struct {
+	// Location is the time zone schedules are evaluated in
+	Location *time.Location
+	// Schedules override the default schedule of jobs by name
+	Schedules map[string]string
+	// LockTTL bounds how long a job stays locked after its runner dies;
+	// running jobs renew the lock so only stalled ones hit it
+	LockTTL time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Location:  time.UTC,
+		Schedules: map[string]string{},
+		LockTTL:   30 * time.Second,
+	}
+}
+
+// ParseConfig builds the configuration from CRON_TIMEZONE and
+// CRON_SCHEDULES, a semicolon separated list of name=schedule pairs, e.g.
+// "streak-reset=5 0 * * *;digest=@hourly;cleanup=off". Empty values keep the
+// defaults.
+func ParseConfig(timezone, schedules string) (Config, error) {
+	config := DefaultConfig()
+
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return config, fmt.Errorf("CRON_TIMEZONE: %w", err)
+		}
+		config.Location = location
+	}
+
+	if schedules != "" {
+		for _, pair := range strings.Split(schedules, ";") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			name, spec, ok := strings.Cut(pair, "=")
+			name, spec = strings.TrimSpace(name), strings.TrimSpace(spec)
+			if !ok || name == "" || spec == "" {
+				return config, fmt.Errorf("CRON_SCHEDULES: expected name=schedule, got %q", pair)
+			}
+			if _, err := parseSchedule(spec); err != nil {
+				return config, fmt.Errorf("CRON_SCHEDULES: %s: %w", name, err)
+			}
+			config.Schedules[name] = spec
+		}
+	}
+
+	return config, nil
+}
+
+// parseSchedule accepts standard five-field cron expressions and
+// descriptors such as @daily or @every 10m. It returns nil for ScheduleOff.
+func parseSchedule(spec string) (cron.Schedule, error) {
+	if spec == ScheduleOff {
+		return nil, nil
+	}
+	return cron.ParseStandard(spec)
+}
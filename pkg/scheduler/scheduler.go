@@ -0,0 +1,370 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"s29-be/pkg/logging"
+	"sort"
+	"sync"
+	"time"
+
+	"s29-be/pkg/cache"
+	"s29-be/pkg/tracing"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+
+	lockPrefix = "scheduler:lock:"
+)
+
+var (
+	ErrUnknownJob = errors.New("unknown job")
+	ErrJobRunning = errors.New("job is already running")
+	ErrNotStarted = errors.New("scheduler is not running")
+)
+
+// Func runs one execution of a job. ctx is cancelled when the job's lock is
+// lost, the job times out or the scheduler shuts down.
+type Func func(ctx context.Context, run *Run) error
+
+type Job struct {
+	Name string
+	// Schedule is the default schedule; Config.Schedules may override it
+	Schedule string
+	// Timeout cancels runs that take longer; zero means no limit
+	Timeout time.Duration
+	Run     Func
+}
+
+// Run is one execution of a job.
+type Run struct {
+	ID      uuid.UUID `json:"id"`
+	Job     string    `json:"job"`
+	Trigger string    `json:"trigger"`
+	// ScheduledFor is the tick a scheduled run fires for; nil for manual runs
+	ScheduledFor *time.Time `json:"scheduled_for"`
+	TriggeredBy  *uuid.UUID `json:"triggered_by"`
+	// Fence is the fencing token of the job lock held by the run
+	Fence      int64      `json:"fence"`
+	Instance   string     `json:"instance"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// JobInfo describes a registered job as configured on this replica.
+type JobInfo struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	// NextRunAt is nil for jobs that are switched off
+	NextRunAt *time.Time `json:"next_run_at"`
+}
+
+// Store records job state shared by every replica. StartRun returns false
+// when a run for the same scheduled tick was already recorded, which means
+// another replica fired the tick first. Writes to a job's last-run status
+// must be ignored when the run's fence is lower than the last one recorded.
+type Store interface {
+	SyncJobs(jobs []JobInfo) error
+	IsPaused(name string) (bool, error)
+	StartRun(run *Run) (bool, error)
+	FinishRun(run *Run) error
+}
+
+type entry struct {
+	job      Job
+	spec     string
+	schedule cron.Schedule
+}
+
+// Scheduler fires recurring jobs. Every replica runs one with the same
+// jobs; a Redis lock per job keeps runs from overlapping and the store
+// records each tick once, so only one replica runs a given tick.
+type Scheduler struct {
+	cache    *cache.Client
+	config   Config
+	instance string
+	store    Store
+
+	mu      sync.Mutex
+	jobs    map[string]*entry
+	names   []string
+	ctx     context.Context
+	running sync.WaitGroup
+}
+
+func New(cacheClient *cache.Client, config Config) *Scheduler {
+	defaults := DefaultConfig()
+	if config.Location == nil {
+		config.Location = defaults.Location
+	}
+	if config.LockTTL <= 0 {
+		config.LockTTL = defaults.LockTTL
+	}
+	return &Scheduler{
+		cache:    cacheClient,
+		config:   config,
+		instance: cache.NewWorkerID(),
+		jobs:     make(map[string]*entry),
+	}
+}
+
+// SetStore sets where runs are recorded. It must be called before Start.
+func (s *Scheduler) SetStore(store Store) {
+	s.store = store
+}
+
+// Register adds a job. It panics on an invalid schedule, on a duplicate
+// name or once the scheduler has started, since all are programming errors.
+func (s *Scheduler) Register(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx != nil {
+		panic("scheduler: Register called after Start")
+	}
+	if _, exists := s.jobs[job.Name]; exists {
+		panic(fmt.Sprintf("scheduler: job %q registered twice", job.Name))
+	}
+
+	spec := job.Schedule
+	if override, ok := s.config.Schedules[job.Name]; ok {
+		spec = override
+	}
+	schedule, err := parseSchedule(spec)
+	if err != nil {
+		panic(fmt.Sprintf("scheduler: job %q: %v", job.Name, err))
+	}
+
+	s.jobs[job.Name] = &entry{job: job, spec: spec, schedule: schedule}
+	s.names = append(s.names, job.Name)
+	sort.Strings(s.names)
+}
+
+// Jobs lists the registered jobs by name.
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().In(s.config.Location)
+	jobs := make([]JobInfo, 0, len(s.names))
+	for _, name := range s.names {
+		e := s.jobs[name]
+		info := JobInfo{Name: name, Schedule: e.spec}
+		if e.schedule != nil {
+			next := e.schedule.Next(now)
+			info.NextRunAt = &next
+		}
+		jobs = append(jobs, info)
+	}
+	return jobs
+}
+
+// Start records the registered jobs in the store and fires them on schedule
+// until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if s.store == nil {
+		return errors.New("scheduler: no store set")
+	}
+
+	jobs := s.Jobs()
+	if err := s.store.SyncJobs(jobs); err != nil {
+		return fmt.Errorf("sync scheduled jobs: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return errors.New("scheduler: already started")
+	}
+	s.ctx = ctx
+
+	for name := range s.config.Schedules {
+		if _, ok := s.jobs[name]; !ok {
+			slog.Warn("Scheduler: ignoring schedule for unknown job", slog.String("job", name))
+		}
+	}
+	for _, name := range s.names {
+		if e := s.jobs[name]; e.schedule != nil {
+			s.running.Add(1)
+			go s.loop(ctx, e)
+		}
+	}
+	return nil
+}
+
+// Wait blocks until the schedule loops and in-flight runs have returned
+// after the context given to Start is cancelled, or until ctx is done.
+func (s *Scheduler) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Trigger runs a job now, regardless of its schedule or pause state, and
+// returns without waiting for it to finish. It returns ErrJobRunning when
+// the job is already running anywhere.
+func (s *Scheduler) Trigger(name string, triggeredBy *uuid.UUID) (*Run, error) {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	if ctx == nil {
+		return nil, ErrNotStarted
+	}
+
+	run := &Run{Job: name, Trigger: TriggerManual, TriggeredBy: triggeredBy}
+	lock, started, err := s.begin(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, ErrJobRunning
+	}
+
+	view := *run
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.execute(ctx, e, lock, run)
+	}()
+	return &view, nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	defer s.running.Done()
+
+	for {
+		tick := e.schedule.Next(time.Now().In(s.config.Location))
+		timer := time.NewTimer(time.Until(tick))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.fire(ctx, e, tick)
+	}
+}
+
+// fire runs a scheduled tick unless the job is paused or another replica
+// has it. Runs are synchronous, so a run that outlasts the interval makes
+// this replica skip the ticks it overlaps.
+func (s *Scheduler) fire(ctx context.Context, e *entry, tick time.Time) {
+	paused, err := s.store.IsPaused(e.job.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "Scheduler: check pause state", slog.String("job", e.job.Name), slog.Any("error", err))
+		return
+	}
+	if paused {
+		return
+	}
+
+	run := &Run{Job: e.job.Name, Trigger: TriggerSchedule, ScheduledFor: &tick}
+	lock, started, err := s.begin(ctx, run)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Scheduler: start run", slog.String("job", e.job.Name), slog.Any("error", err))
+		}
+		return
+	}
+	if started {
+		s.execute(ctx, e, lock, run)
+	}
+}
+
+// begin takes the job lock and records the run. It reports false, without
+// an error, when the job is locked or its tick was already run.
+func (s *Scheduler) begin(ctx context.Context, run *Run) (*cache.Lock, bool, error) {
+	lock, err := s.cache.AcquireLock(ctx, lockPrefix+run.Job, s.config.LockTTL)
+	if err != nil {
+		if errors.Is(err, cache.ErrLockNotHeld) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("acquire lock: %w", err)
+	}
+
+	run.ID, _ = uuid.NewV7()
+	run.Fence = lock.Fence()
+	run.Instance = s.instance
+	run.Status = StatusRunning
+	run.StartedAt = time.Now().UTC()
+
+	started, err := s.store.StartRun(run)
+	if err != nil || !started {
+		_ = lock.Release(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, false, fmt.Errorf("record run: %w", err)
+		}
+		return nil, false, nil
+	}
+	return lock, true, nil
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry, lock *cache.Lock, run *Run) {
+	// Everything the job logs is tagged with its run
+	ctx = logging.With(ctx, slog.String("job", run.Job), slog.String("run_id", run.ID.String()))
+	ctx, span := tracing.Tracer().Start(ctx, "scheduled "+run.Job)
+	defer span.End()
+	runCtx, cancel := lock.KeepAlive(ctx)
+	defer cancel()
+	if e.job.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		runCtx, cancelTimeout = context.WithTimeout(runCtx, e.job.Timeout)
+		defer cancelTimeout()
+	}
+
+	err := invoke(runCtx, e.job, run)
+	tracing.RecordError(span, err)
+
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	run.Status = StatusSucceeded
+	if err != nil {
+		run.Status = StatusFailed
+		run.Error = err.Error()
+		slog.ErrorContext(ctx, "Scheduler: run failed", slog.Any("error", err))
+	}
+
+	if err := s.store.FinishRun(run); err != nil {
+		slog.ErrorContext(ctx, "Scheduler: record result", slog.Any("error", err))
+	}
+	if err := lock.Release(context.WithoutCancel(ctx)); errors.Is(err, cache.ErrLockNotHeld) {
+		slog.WarnContext(ctx, "Scheduler: run outlived its lock")
+	}
+}
+
+func invoke(ctx context.Context, job Job, run *Run) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return job.Run(ctx, run)
+}
@@ -0,0 +1,37 @@
+package schemadrift
+
+import (
+	contentDomain "s29-be/internal/content/domain"
+	jobsDomain "s29-be/internal/jobs/domain"
+	progressDomain "s29-be/internal/progress/domain"
+	userDomain "s29-be/internal/user/domain"
+	"s29-be/pkg/outbox"
+)
+
+// Models lists every GORM model backed by a migrated table. Add new models
+// here so CI checks them.
+func Models() []Model {
+	return []Model{
+		{Value: &userDomain.User{}},
+
+		{Value: &contentDomain.Course{}},
+		{Value: &contentDomain.Unit{}},
+		{Value: &contentDomain.Lesson{}},
+		{Value: &contentDomain.Exercise{}},
+		{Value: &contentDomain.Vocabulary{}},
+		{Value: &contentDomain.ContentRevision{}},
+		{Value: &contentDomain.ContentTranslation{}},
+		// Written by the search repository in SQL, from the entry text
+		{Value: &contentDomain.SearchDocument{}, Unmapped: []string{"title_normalized", "document"}},
+
+		{Value: &progressDomain.UserCourseProgress{}},
+		{Value: &progressDomain.UserUnitProgress{}},
+		{Value: &progressDomain.UserLessonProgress{}},
+		{Value: &progressDomain.LessonSession{}},
+
+		{Value: &jobsDomain.ScheduledJob{}},
+		{Value: &jobsDomain.ScheduledJobRun{}},
+
+		{Value: &outbox.Message{}},
+	}
+}
@@ -0,0 +1,405 @@
+// Package schemadrift compares GORM models with the tables the migrations
+// created, so a column added in SQL but never mapped, or a field added to a
+// model without a migration, is caught in CI instead of by a failing query.
+// Run it against a database migrated to the latest version.
+package schemadrift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type Kind string
+
+const (
+	MissingTable Kind = "missing_table"
+	// MissingColumn is a model field without a column
+	MissingColumn Kind = "missing_column"
+	// ExtraColumn is a column without a model field
+	ExtraColumn  Kind = "extra_column"
+	TypeMismatch Kind = "type_mismatch"
+	NullMismatch Kind = "nullability_mismatch"
+	MissingIndex Kind = "missing_index"
+)
+
+// Issue is one difference between a model and its table.
+type Issue struct {
+	Table  string `json:"table"`
+	Column string `json:"column,omitempty"`
+	Kind   Kind   `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+func (i Issue) String() string {
+	name := i.Table
+	if i.Column != "" {
+		name += "." + i.Column
+	}
+	return fmt.Sprintf("%s: %s: %s", name, i.Kind, i.Detail)
+}
+
+// Model is a GORM model to compare with its table. Unmapped lists columns
+// the table has on purpose without a field, such as ones only written in
+// SQL.
+type Model struct {
+	Value    interface{}
+	Unmapped []string
+}
+
+// errNoModels guards against comparing nothing and reporting success.
+var errNoModels = errors.New("no models to compare")
+
+// Error is returned by Check when the schema has drifted.
+type Error struct {
+	Issues []Issue
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, 0, len(e.Issues)+1)
+	lines = append(lines, fmt.Sprintf("schema drift: %d issue(s)", len(e.Issues)))
+	for _, issue := range e.Issues {
+		lines = append(lines, "  "+issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Check compares models with the database and returns an *Error listing
+// every issue, or nil. Tests can call it against a freshly migrated
+// database:
+//
+//	if err := schemadrift.Check(ctx, db, schemadrift.Models()...); err != nil {
+//		t.Fatal(err)
+//	}
+func Check(ctx context.Context, db *gorm.DB, models ...Model) error {
+	issues, err := Compare(ctx, db, models...)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &Error{Issues: issues}
+	}
+	return nil
+}
+
+// Compare returns every difference between models and the tables of the
+// database's current schema, ordered by table and column.
+func Compare(ctx context.Context, db *gorm.DB, models ...Model) ([]Issue, error) {
+	if len(models) == 0 {
+		return nil, errNoModels
+	}
+	db = db.WithContext(ctx)
+
+	var issues []Issue
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model.Value); err != nil {
+			return nil, fmt.Errorf("failed to parse model %T: %w", model.Value, err)
+		}
+		modelIssues, err := compareTable(db, stmt.Schema, model.Unmapped)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s: %w", stmt.Schema.Table, err)
+		}
+		issues = append(issues, modelIssues...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Table != issues[j].Table {
+			return issues[i].Table < issues[j].Table
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues, nil
+}
+
+type column struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	HasDefault bool
+}
+
+type index struct {
+	Name      string
+	IsUnique  bool
+	IsPrimary bool
+	Columns   string
+}
+
+func compareTable(db *gorm.DB, model *schema.Schema, unmapped []string) ([]Issue, error) {
+	var columns []column
+	err := db.Raw(`
+		SELECT a.attname AS name, format_type(a.atttypid, a.atttypmod) AS type,
+			NOT a.attnotnull AS nullable, a.atthasdef AS has_default
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relname = ? AND c.relkind IN ('r', 'p')
+			AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, model.Table).Scan(&columns).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return []Issue{{Table: model.Table, Kind: MissingTable, Detail: fmt.Sprintf("no table for model %s", model.Name)}}, nil
+	}
+
+	var issues []Issue
+	byName := make(map[string]column, len(columns))
+	for _, col := range columns {
+		byName[col.Name] = col
+	}
+
+	mapped := make(map[string]bool, len(model.DBNames))
+	for _, name := range model.DBNames {
+		field := model.FieldsByDBName[name]
+		if field == nil || field.IgnoreMigration {
+			continue
+		}
+		mapped[name] = true
+
+		col, ok := byName[name]
+		if !ok {
+			issues = append(issues, Issue{Table: model.Table, Column: name, Kind: MissingColumn,
+				Detail: fmt.Sprintf("field %s has no column", field.Name)})
+			continue
+		}
+		issues = append(issues, compareColumn(db, model.Table, field, col)...)
+	}
+
+	for _, col := range columns {
+		if !mapped[col.Name] && !slices.Contains(unmapped, col.Name) {
+			issues = append(issues, Issue{Table: model.Table, Column: col.Name, Kind: ExtraColumn,
+				Detail: fmt.Sprintf("column of type %s has no field in %s", col.Type, model.Name)})
+		}
+	}
+
+	indexIssues, err := compareIndexes(db, model)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, indexIssues...), nil
+}
+
+func compareColumn(db *gorm.DB, table string, field *schema.Field, col column) []Issue {
+	var issues []Issue
+
+	expected := expectedType(db, field)
+	if !compatibleType(field, expected, col.Type) {
+		issues = append(issues, Issue{Table: table, Column: col.Name, Kind: TypeMismatch,
+			Detail: fmt.Sprintf("model expects %s, column is %s", expected, col.Type)})
+	}
+
+	required := field.NotNull || field.PrimaryKey
+	switch {
+	case required && col.Nullable:
+		issues = append(issues, Issue{Table: table, Column: col.Name, Kind: NullMismatch,
+			Detail: "model requires a value, column allows NULL"})
+	case !required && !col.Nullable && writesNull(field) && !col.HasDefault && !field.HasDefaultValue:
+		issues = append(issues, Issue{Table: table, Column: col.Name, Kind: NullMismatch,
+			Detail: fmt.Sprintf("field %s can be nil, column is NOT NULL without a default", field.Name)})
+	}
+	return issues
+}
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// expectedType is the column type GORM would create for field. A type tag
+// wins, since GORM gives foreign keys the data type of the key they
+// reference, and uuid.UUID fields without one expect uuid rather than
+// bytea, since they are written as strings.
+func expectedType(db *gorm.DB, field *schema.Field) string {
+	if tagType := field.TagSettings["TYPE"]; tagType != "" {
+		return tagType
+	}
+	if indirect(field.FieldType) == uuidType {
+		return "uuid"
+	}
+	return db.Dialector.DataTypeOf(field)
+}
+
+// typeAliases maps the names GORM and the migrations use to the names
+// format_type reports.
+var typeAliases = map[string]string{
+	"bool":        "boolean",
+	"int2":        "smallint",
+	"int4":        "integer",
+	"int":         "integer",
+	"int8":        "bigint",
+	"smallserial": "smallint",
+	"serial":      "integer",
+	"bigserial":   "bigint",
+	"float4":      "real",
+	"float8":      "double precision",
+	"decimal":     "numeric",
+	"varchar":     "character varying",
+	"char":        "character",
+	"timestamptz": "timestamp with time zone",
+	"timestamp":   "timestamp without time zone",
+}
+
+var typeModifier = regexp.MustCompile(`^([a-z0-9 _]+?)\s*(\(.*\))?(\[\])?$`)
+
+func normalizeType(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	parts := typeModifier.FindStringSubmatch(name)
+	if parts == nil {
+		return name
+	}
+	base, modifier, array := parts[1], strings.ReplaceAll(parts[2], " ", ""), parts[3]
+	if alias, ok := typeAliases[base]; ok {
+		base = alias
+	}
+	// timestamptz(3) is reported as timestamp(3) with time zone
+	if modifier != "" && strings.HasPrefix(base, "timestamp ") {
+		return strings.Replace(base, "timestamp", "timestamp"+modifier, 1) + array
+	}
+	return base + modifier + array
+}
+
+var integerBits = map[string]int{"smallint": 16, "integer": 32, "bigint": 64}
+
+// compatibleType reports whether a column of type actual reads and writes
+// field without loss. Integer columns may be narrower than the field, and
+// strings without a size accept any character type.
+func compatibleType(field *schema.Field, expected, actual string) bool {
+	expected, actual = normalizeType(expected), normalizeType(actual)
+	if expected == actual {
+		return true
+	}
+
+	switch field.DataType {
+	case schema.Int, schema.Uint:
+		bits, ok := integerBits[actual]
+		return ok && bits <= field.Size
+	case schema.Float:
+		return actual == "real" || actual == "double precision" || strings.HasPrefix(actual, "numeric")
+	case schema.String:
+		return field.Size == 0 && (actual == "text" || strings.HasPrefix(actual, "character varying"))
+	case schema.Time:
+		return strings.HasPrefix(actual, "timestamp") && strings.HasSuffix(actual, "with time zone")
+	}
+	return false
+}
+
+// writesNull reports whether field can be saved as NULL: nil pointers,
+// slices and maps are, and so are the sql.Null types when not valid.
+func writesNull(field *schema.Field) bool {
+	switch field.FieldType.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return field.FieldType.PkgPath() == "database/sql"
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// compareIndexes reports primary keys, unique columns and tagged indexes of
+// the model that no index of the table covers. Indexes are matched by
+// their columns, since the migrations name them differently from GORM.
+func compareIndexes(db *gorm.DB, model *schema.Schema) ([]Issue, error) {
+	var indexes []index
+	err := db.Raw(`
+		SELECT i.relname AS name, x.indisunique AS is_unique, x.indisprimary AS is_primary,
+			array_to_string(ARRAY(
+				SELECT a.attname
+				FROM unnest(x.indkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			), ',') AS columns
+		FROM pg_index x
+		JOIN pg_class t ON t.oid = x.indrelid
+		JOIN pg_class i ON i.oid = x.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname = current_schema() AND t.relname = ?`, model.Table).Scan(&indexes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	require := func(columns []string, primary, unique bool, what string) {
+		if covered(indexes, columns, primary, unique) {
+			return
+		}
+		issues = append(issues, Issue{Table: model.Table, Column: strings.Join(columns, ","), Kind: MissingIndex,
+			Detail: fmt.Sprintf("no %s on (%s)", what, strings.Join(columns, ", "))})
+	}
+
+	if len(model.PrimaryFields) > 0 {
+		columns := make([]string, 0, len(model.PrimaryFields))
+		for _, field := range model.PrimaryFields {
+			columns = append(columns, field.DBName)
+		}
+		require(columns, true, true, "primary key")
+	}
+	for _, field := range model.Fields {
+		if field.Unique && field.DBName != "" && !field.IgnoreMigration {
+			require([]string{field.DBName}, false, true, "unique index")
+		}
+	}
+
+	parsed := model.ParseIndexes()
+	names := make([]string, 0, len(parsed))
+	for name := range parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		idx := parsed[name]
+		columns := make([]string, 0, len(idx.Fields))
+		for _, option := range idx.Fields {
+			if option.Field == nil {
+				continue
+			}
+			columns = append(columns, option.DBName)
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		unique := idx.Class == "UNIQUE"
+		what := "index"
+		if unique {
+			what = "unique index"
+		}
+		require(columns, false, unique, what)
+	}
+	return issues, nil
+}
+
+// covered reports whether an index enforces or serves columns. Unique and
+// primary requirements need an index on exactly those columns; a plain
+// index may be the leading columns of a wider one.
+func covered(indexes []index, columns []string, primary, unique bool) bool {
+	for _, idx := range indexes {
+		have := strings.Split(idx.Columns, ",")
+		switch {
+		case primary:
+			if idx.IsPrimary && slices.Equal(have, columns) {
+				return true
+			}
+		case unique:
+			if idx.IsUnique && slices.Equal(have, columns) {
+				return true
+			}
+		default:
+			if len(have) >= len(columns) && slices.Equal(have[:len(columns)], columns) {
+				return true
+			}
+		}
+	}
+	return false
+}
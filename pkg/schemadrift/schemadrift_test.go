@@ -0,0 +1,248 @@
+package schemadrift
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"s29-be/pkg/migrate"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// testDSNEnv names a Postgres the database tests may migrate, e.g.
+// "host=localhost user=postgres password=postgres dbname=s29_test sslmode=disable".
+const testDSNEnv = "TEST_DATABASE_DSN"
+
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"text", "text"},
+		{"  TEXT ", "text"},
+		{"int8", "bigint"},
+		{"int4", "integer"},
+		{"int", "integer"},
+		{"bigserial", "bigint"},
+		{"bool", "boolean"},
+		{"float8", "double precision"},
+		{"decimal(10,2)", "numeric(10,2)"},
+		{"numeric(10, 2)", "numeric(10,2)"},
+		{"varchar(255)", "character varying(255)"},
+		{"character varying(255)", "character varying(255)"},
+		{"timestamptz", "timestamp with time zone"},
+		{"timestamptz(3)", "timestamp(3) with time zone"},
+		{"timestamp(3) with time zone", "timestamp(3) with time zone"},
+		{"timestamp", "timestamp without time zone"},
+		{"text[]", "text[]"},
+		{"varchar(20)[]", "character varying(20)[]"},
+		{"jsonb", "jsonb"},
+		{"uuid", "uuid"},
+	}
+	for _, tt := range tests {
+		if got := normalizeType(tt.in); got != tt.want {
+			t.Errorf("normalizeType(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompatibleType(t *testing.T) {
+	int64Field := &schema.Field{DataType: schema.Int, Size: 64}
+	int32Field := &schema.Field{DataType: schema.Int, Size: 32}
+	floatField := &schema.Field{DataType: schema.Float, Size: 64}
+	stringField := &schema.Field{DataType: schema.String}
+	sizedField := &schema.Field{DataType: schema.String, Size: 100}
+	timeField := &schema.Field{DataType: schema.Time}
+	uuidField := &schema.Field{DataType: "uuid"}
+
+	tests := []struct {
+		name     string
+		field    *schema.Field
+		expected string
+		actual   string
+		want     bool
+	}{
+		{"same type", int64Field, "bigint", "bigint", true},
+		{"alias", int64Field, "int8", "bigint", true},
+		{"narrower integer", int64Field, "bigint", "integer", true},
+		{"wider integer", int32Field, "integer", "bigint", false},
+		{"integer as text", int64Field, "bigint", "text", false},
+		{"float as numeric", floatField, "double precision", "numeric(10,2)", true},
+		{"float as real", floatField, "double precision", "real", true},
+		{"float as integer", floatField, "double precision", "integer", false},
+		{"unsized string as varchar", stringField, "text", "character varying(50)", true},
+		{"unsized string as text", stringField, "text", "text", true},
+		{"sized string of other size", sizedField, "varchar(100)", "character varying(50)", false},
+		{"sized string as text", sizedField, "varchar(100)", "text", false},
+		{"time with precision", timeField, "timestamptz", "timestamp(3) with time zone", true},
+		{"time without zone", timeField, "timestamptz", "timestamp without time zone", false},
+		{"uuid as text", uuidField, "uuid", "text", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compatibleType(tt.field, tt.expected, tt.actual); got != tt.want {
+				t.Errorf("compatibleType(%s, %s) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCovered(t *testing.T) {
+	indexes := []index{
+		{Name: "users_pkey", IsUnique: true, IsPrimary: true, Columns: "id"},
+		{Name: "users_email_key", IsUnique: true, Columns: "email"},
+		{Name: "idx_units_course_position", Columns: "course_id,position"},
+		{Name: "units_course_id_slug_key", IsUnique: true, Columns: "course_id,slug"},
+	}
+
+	tests := []struct {
+		name    string
+		columns []string
+		primary bool
+		unique  bool
+		want    bool
+	}{
+		{"primary key", []string{"id"}, true, true, true},
+		{"primary key on other columns", []string{"email"}, true, true, false},
+		{"unique column", []string{"email"}, false, true, true},
+		{"unique on a plain index", []string{"course_id", "position"}, false, true, false},
+		{"unique on leading columns only", []string{"course_id"}, false, true, false},
+		{"composite unique", []string{"course_id", "slug"}, false, true, true},
+		{"plain index", []string{"course_id", "position"}, false, false, true},
+		{"plain index on leading columns", []string{"course_id"}, false, false, true},
+		{"plain index on trailing column", []string{"position"}, false, false, false},
+		{"plain index served by unique", []string{"email"}, false, false, true},
+		{"columns out of order", []string{"position", "course_id"}, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := covered(indexes, tt.columns, tt.primary, tt.unique); got != tt.want {
+				t.Errorf("covered(%v, primary=%v, unique=%v) = %v, want %v", tt.columns, tt.primary, tt.unique, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareWithoutModels(t *testing.T) {
+	if _, err := Compare(context.Background(), nil); !errors.Is(err, errNoModels) {
+		t.Fatalf("Compare() error = %v, want %v", err, errNoModels)
+	}
+}
+
+// TestModelsMatchMigrations applies the migrations and fails on any drift
+// between them and Models.
+func TestModelsMatchMigrations(t *testing.T) {
+	db := migratedDB(t)
+
+	if err := Check(context.Background(), db, Models()...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type driftProbe struct {
+	ID      uuid.UUID `gorm:"primaryKey"`
+	Name    string    `gorm:"not null;size:50;uniqueIndex"`
+	Score   int64
+	Missing string
+}
+
+func (driftProbe) TableName() string {
+	return "schemadrift_probe"
+}
+
+func TestCompareReportsDrift(t *testing.T) {
+	db := migratedDB(t)
+
+	// Rolled back at the end, so the probe table never outlives the test
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+
+	err := tx.Exec(`CREATE TABLE schemadrift_probe (
+		id UUID PRIMARY KEY,
+		name VARCHAR(50),
+		score TEXT,
+		extra TEXT
+	)`).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := Compare(context.Background(), tx, Model{Value: &driftProbe{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[Issue]bool{
+		{Table: "schemadrift_probe", Column: "extra", Kind: ExtraColumn}:     true,
+		{Table: "schemadrift_probe", Column: "missing", Kind: MissingColumn}: true,
+		{Table: "schemadrift_probe", Column: "name", Kind: MissingIndex}:     true,
+		{Table: "schemadrift_probe", Column: "name", Kind: NullMismatch}:     true,
+		{Table: "schemadrift_probe", Column: "score", Kind: TypeMismatch}:    true,
+	}
+	for _, issue := range issues {
+		key := Issue{Table: issue.Table, Column: issue.Column, Kind: issue.Kind}
+		if !want[key] {
+			t.Errorf("unexpected issue: %s", issue)
+		}
+		delete(want, key)
+	}
+	for issue := range want {
+		t.Errorf("missing issue: %s %s.%s", issue.Kind, issue.Table, issue.Column)
+	}
+}
+
+type tablelessProbe struct {
+	ID uuid.UUID `gorm:"primaryKey"`
+}
+
+func (tablelessProbe) TableName() string {
+	return "schemadrift_tableless_probe"
+}
+
+func TestCompareReportsMissingTable(t *testing.T) {
+	db := migratedDB(t)
+
+	issues, err := Compare(context.Background(), db, Model{Value: &tablelessProbe{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Kind != MissingTable {
+		t.Errorf("Compare() = %v, want one %s issue", issues, MissingTable)
+	}
+}
+
+// migratedDB connects to the database named by TEST_DATABASE_DSN and
+// applies every migration, skipping the test when the variable is unset.
+func migratedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrate.New(sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -0,0 +1,107 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin records a client span for each query run with a traced
+// context, as set by repositories' WithContext. SQL is recorded with its
+// placeholders, never the values.
+type GormPlugin struct{}
+
+type gormSpan struct {
+	span   trace.Span
+	parent context.Context
+}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	type registerer interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+
+	callbacks := db.Callback()
+	hooks := []struct {
+		name          string
+		before, after registerer
+	}{
+		{"create", callbacks.Create().Before("*"), callbacks.Create().After("*")},
+		{"query", callbacks.Query().Before("*"), callbacks.Query().After("*")},
+		{"update", callbacks.Update().Before("*"), callbacks.Update().After("*")},
+		{"delete", callbacks.Delete().Before("*"), callbacks.Delete().After("*")},
+		{"row", callbacks.Row().Before("*"), callbacks.Row().After("*")},
+		{"raw", callbacks.Raw().Before("*"), callbacks.Raw().After("*")},
+	}
+
+	for _, hook := range hooks {
+		if err := hook.before.Register("tracing:before_"+hook.name, startGormSpan); err != nil {
+			return err
+		}
+		if err := hook.after.Register("tracing:after_"+hook.name, endGormSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startGormSpan(db *gorm.DB) {
+	parent := db.Statement.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, span := Tracer().Start(parent, "db", trace.WithSpanKind(trace.SpanKindClient))
+	db.Statement.Context = ctx
+	db.InstanceSet(gormSpanKey, gormSpan{span: span, parent: parent})
+}
+
+func endGormSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	started := value.(gormSpan)
+	db.Statement.Context = started.parent
+
+	span := started.span
+	defer span.End()
+	if !span.IsRecording() {
+		return
+	}
+
+	query := db.Statement.SQL.String()
+	operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	operation = strings.ToUpper(operation)
+	table := db.Statement.Table
+
+	name := operation
+	if table != "" {
+		name += " " + table
+	}
+	if name != "" {
+		span.SetName(name)
+	}
+
+	span.SetAttributes(
+		semconv.DBSystemNamePostgreSQL,
+		semconv.DBQueryText(query),
+		semconv.DBOperationName(operation),
+		semconv.DBCollectionName(table),
+		attribute.Int64("db.response.rows_affected", db.Statement.RowsAffected),
+	)
+
+	if err := db.Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		RecordError(span, err)
+	}
+}
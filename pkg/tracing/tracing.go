@@ -0,0 +1,143 @@
+// Package tracing sets up OpenTelemetry tracing with W3C trace-context
+// propagation. HTTP requests, database queries, Redis commands, Kratos
+// calls and background jobs each get spans, joined into one trace per
+// request by the context passed down from the Fiber handler.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+
+	instrumentationName = "s29-be"
+)
+
+type Config struct {
+	// Exporter is "otlp" to send spans to a collector, or "none". Trace
+	// context is propagated and logged either way.
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+	// Endpoint is the collector's OTLP/HTTP base URL, e.g.
+	// http://otel-collector:4318
+	Endpoint    string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	// SampleRatio is the share of new traces recorded; traces started by a
+	// caller follow the caller's decision
+	SampleRatio float64 `yaml:"sample_ratio" env:"OTEL_TRACES_SAMPLER_ARG"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Exporter:    ExporterNone,
+		ServiceName: "s29-api",
+		SampleRatio: 1,
+	}
+}
+
+// Setup installs the global tracer provider and propagator. The returned
+// function flushes buffered spans and stops the exporter.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.Exporter != ExporterOTLP {
+		// Spans are still created so requests get trace IDs for logs and
+		// error responses, they are just not exported
+		provider := NewProvider(config)
+		Install(provider)
+		return provider.Shutdown, nil
+	}
+
+	var options []otlptracehttp.Option
+	if config.Endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpointURL(config.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	provider := NewProvider(config, sdktrace.NewBatchSpanProcessor(exporter))
+	Install(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider returns a tracer provider handing finished spans to
+// processors. Tests can record spans in memory with
+//
+//	exporter := tracetest.NewInMemoryExporter()
+//	tracing.Install(tracing.NewProvider(config, sdktrace.NewSimpleSpanProcessor(exporter)))
+func NewProvider(config Config, processors ...sdktrace.SpanProcessor) *sdktrace.TracerProvider {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(backgroundSampler{sdktrace.TraceIDRatioBased(config.SampleRatio)})),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(config.ServiceName))),
+	}
+	for _, processor := range processors {
+		options = append(options, sdktrace.WithSpanProcessor(processor))
+	}
+	return sdktrace.NewTracerProvider(options...)
+}
+
+// Install makes provider the global tracer provider and enables W3C
+// trace-context and baggage propagation.
+func Install(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Tracer returns the tracer used for the application's own spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// TraceID returns the ID of the trace ctx belongs to, or "".
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// backgroundSampler drops client spans that start a trace. Queue workers,
+// the outbox relay and the scheduler poll Redis and the database all the
+// time; outside a request or job those calls would each be a trace of
+// their own.
+type backgroundSampler struct {
+	root sdktrace.Sampler
+}
+
+func (s backgroundSampler) ShouldSample(parameters sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if parameters.Kind == trace.SpanKindClient && !trace.SpanContextFromContext(parameters.ParentContext).IsValid() {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.Drop,
+			Tracestate: trace.SpanContextFromContext(parameters.ParentContext).TraceState(),
+		}
+	}
+	return s.root.ShouldSample(parameters)
+}
+
+func (s backgroundSampler) Description() string {
+	return "Background{" + s.root.Description() + "}"
+}
+
+// RecordError marks span as failed with err. It does nothing for a nil err.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
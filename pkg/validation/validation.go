@@ -0,0 +1,218 @@
+// Package validation binds request bodies, query strings and route
+// parameters into DTOs and checks them against their `validate` struct
+// tags. Failures become a 422 AppError listing every invalid field, so
+// services only ever see requests that passed.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	app_error "s29-be/pkg/error"
+	"s29-be/pkg/i18n"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CodeValidationFailed is the AppError code of a request with invalid fields.
+const CodeValidationFailed = "VALIDATION_FAILED"
+
+// usernamePattern mirrors the username trait in kratos/identity.schema.json.
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{3,32}$`)
+
+// FieldError describes one invalid field. Field is the path clients sent,
+// e.g. "identity.traits.email", and Code is the failed rule in upper case,
+// e.g. "REQUIRED" or "MAX".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors is the data of a validation AppError.
+type Errors struct {
+	Errors []FieldError `json:"errors"`
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(fieldName)
+
+	// Replaces the built-in uuid rule, which also accepts braces and other
+	// forms the services' uuid.Parse would then have to deal with
+	mustRegister(v, "uuid", func(fl validator.FieldLevel) bool {
+		value, ok := stringValue(fl)
+		if !ok {
+			return false
+		}
+		_, err := uuid.Parse(value)
+		return err == nil && len(value) == 36
+	})
+	mustRegister(v, "username", func(fl validator.FieldLevel) bool {
+		value, ok := stringValue(fl)
+		return ok && usernamePattern.MatchString(value)
+	})
+	mustRegister(v, "locale", func(fl validator.FieldLevel) bool {
+		value, ok := stringValue(fl)
+		return ok && i18n.Normalize(value) != ""
+	})
+	return v
+}
+
+func mustRegister(v *validator.Validate, tag string, fn validator.Func) {
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		panic(fmt.Sprintf("validation: register %s: %v", tag, err))
+	}
+}
+
+func stringValue(fl validator.FieldLevel) (string, bool) {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return "", false
+	}
+	return field.String(), true
+}
+
+// fieldName reports fields by the name clients use for them.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "params", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Struct validates a DTO, returning a 422 AppError listing every invalid
+// field, or nil.
+func Struct(dto interface{}) error {
+	err := validate.Struct(dto)
+	if err == nil {
+		return nil
+	}
+	if appErr, ok := FromError(err); ok {
+		return appErr
+	}
+	return app_error.NewInternalError(err, "")
+}
+
+// FromError converts the errors of a validator into the 422 AppError
+// listing the invalid fields.
+func FromError(err error) (*app_error.AppError, bool) {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return nil, false
+	}
+
+	fields := make([]FieldError, 0, len(invalid))
+	for _, fieldErr := range invalid {
+		field := FieldError{
+			Field:   fieldPath(fieldErr),
+			Code:    strings.ToUpper(fieldErr.Tag()),
+			Param:   fieldErr.Param(),
+			Message: message(fieldErr),
+		}
+		// The params of the required_* rules are Go field names, which
+		// mean nothing to clients
+		if strings.HasPrefix(fieldErr.Tag(), "required_") {
+			field.Param = ""
+		}
+		fields = append(fields, field)
+	}
+	return NewError(fields...), true
+}
+
+// NewError returns the 422 AppError for fields, for checks that struct
+// tags cannot express.
+func NewError(fields ...FieldError) *app_error.AppError {
+	return &app_error.AppError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Validation failed",
+		Code:       CodeValidationFailed,
+		Data:       Errors{Errors: fields},
+	}
+}
+
+// BindBody parses the JSON body into dto and validates it. An empty body
+// is validated as an empty object, so missing fields are reported by name.
+func BindBody(c *fiber.Ctx, dto interface{}) error {
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(dto); err != nil {
+			return app_error.NewBadRequestError(err, "Invalid request body")
+		}
+	}
+	return Struct(dto)
+}
+
+// BindQuery parses the query string into dto and validates it.
+func BindQuery(c *fiber.Ctx, dto interface{}) error {
+	if err := c.QueryParser(dto); err != nil {
+		return app_error.NewBadRequestError(err, "Invalid query parameters")
+	}
+	return Struct(dto)
+}
+
+// BindParams parses the route parameters into dto and validates it.
+func BindParams(c *fiber.Ctx, dto interface{}) error {
+	if err := c.ParamsParser(dto); err != nil {
+		return app_error.NewBadRequestError(err, "Invalid path parameters")
+	}
+	return Struct(dto)
+}
+
+// fieldPath drops the struct name validator puts first in the namespace.
+func fieldPath(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+func message(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required", "required_without", "required_with":
+		return "is required"
+	case "uuid":
+		return "must be a UUID"
+	case "email":
+		return "must be an e-mail address"
+	case "username":
+		return "must be 3 to 32 letters, digits or underscores"
+	case "locale":
+		return "must be a supported language: " + strings.Join(i18n.Supported(), ", ")
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(param), ", ")
+	case "min", "gte":
+		return "must be at least " + param + lengthUnit(fieldErr.Kind())
+	case "max", "lte":
+		return "must be at most " + param + lengthUnit(fieldErr.Kind())
+	default:
+		return "failed the " + fieldErr.Tag() + " rule"
+	}
+}
+
+// lengthUnit names what min and max count for kinds measured by length.
+func lengthUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultQueue = "jobs:default"
+
+type QueueConfig struct {
+	Name        string
+	Concurrency int
+	// Visibility is how long a job may run before it is presumed lost;
+	// running jobs extend their lease so only stalled ones hit it
+	Visibility  time.Duration
+	MaxAttempts int
+}
+
+type Config struct {
+	Queues []QueueConfig
+
+	// Retries wait BaseBackoff * 2^(attempt-1), capped at MaxBackoff, with jitter
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+
+	PollWait          time.Duration
+	HeartbeatInterval time.Duration
+	DeadWorkerAfter   time.Duration
+	ReapInterval      time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Queues:            []QueueConfig{{Name: DefaultQueue, Concurrency: 4}},
+		BaseBackoff:       time.Second,
+		MaxBackoff:        10 * time.Minute,
+		PollWait:          2 * time.Second,
+		HeartbeatInterval: 5 * time.Second,
+		DeadWorkerAfter:   30 * time.Second,
+		ReapInterval:      10 * time.Second,
+	}
+}
+
+// ParseConfig builds the configuration from WORKER_QUEUES, a comma
+// separated list of name:concurrency pairs, e.g. "jobs:default:4,jobs:mail:2",
+// and WORKER_MAX_ATTEMPTS. Empty values keep the defaults.
+func ParseConfig(queues string, maxAttempts int) (Config, error) {
+	config := DefaultConfig()
+
+	if queues != "" {
+		config.Queues = nil
+		for _, spec := range strings.Split(queues, ",") {
+			spec = strings.TrimSpace(spec)
+			if spec == "" {
+				continue
+			}
+			name, concurrency := spec, 1
+			if i := strings.LastIndex(spec, ":"); i > 0 {
+				if n, err := strconv.Atoi(spec[i+1:]); err == nil {
+					name, concurrency = spec[:i], n
+				}
+			}
+			if concurrency < 1 {
+				return config, fmt.Errorf("WORKER_QUEUES: concurrency of %s must be positive", name)
+			}
+			config.Queues = append(config.Queues, QueueConfig{Name: name, Concurrency: concurrency})
+		}
+	}
+
+	if maxAttempts < 0 {
+		return config, fmt.Errorf("WORKER_MAX_ATTEMPTS must be a positive integer")
+	}
+	if maxAttempts > 0 {
+		for i := range config.Queues {
+			config.Queues[i].MaxAttempts = maxAttempts
+		}
+	}
+
+	return config, nil
+}
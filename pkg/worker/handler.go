@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"s29-be/pkg/cache"
+)
+
+// Handler processes one job. Returning an error retries the job with
+// backoff until it runs out of attempts; wrap the error with Permanent to
+// dead-letter it straight away.
+type Handler func(ctx context.Context, job *cache.Delivery) error
+
+// Handle adapts a function taking a decoded payload into a Handler.
+// Payloads that do not decode into T are dead-lettered.
+func Handle[T any](fn func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, job *cache.Delivery) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", job.Type, err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
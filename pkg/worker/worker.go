@@ -0,0 +1,297 @@
+// Package worker runs job handlers against the reliable queues in
+// pkg/cache: a pool of goroutines per queue, retries with exponential
+// backoff, dead-lettering of jobs that keep failing and draining on
+// shutdown.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"s29-be/pkg/cache"
+	"s29-be/pkg/logging"
+	"s29-be/pkg/tracing"
+	"sync"
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultConcurrency = 1
+	defaultVisibility  = time.Minute
+	defaultMaxAttempts = 5
+)
+
+type Runtime struct {
+	client   *cache.Client
+	config   Config
+	handlers map[string]Handler
+
+	mu        sync.Mutex
+	started   bool
+	consumers []*cache.Consumer
+	stop      context.CancelFunc // stops receiving and background loops
+	abort     context.CancelFunc // cancels running handlers
+	wg        sync.WaitGroup     // job loops
+	loops     sync.WaitGroup     // heartbeats and reaper
+}
+
+func New(client *cache.Client, config Config) *Runtime {
+	for i := range config.Queues {
+		queue := &config.Queues[i]
+		if queue.Concurrency < 1 {
+			queue.Concurrency = defaultConcurrency
+		}
+		if queue.Visibility <= 0 {
+			queue.Visibility = defaultVisibility
+		}
+		if queue.MaxAttempts < 1 {
+			queue.MaxAttempts = defaultMaxAttempts
+		}
+	}
+
+	return &Runtime{
+		client:   client,
+		config:   config,
+		handlers: map[string]Handler{},
+	}
+}
+
+// Register adds the handler for a job type. Modules call it from their
+// constructors, before Start.
+func (r *Runtime) Register(jobType string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		panic(fmt.Sprintf("worker: Register(%q) called after Start", jobType))
+	}
+	if _, exists := r.handlers[jobType]; exists {
+		panic(fmt.Sprintf("worker: handler for %q registered twice", jobType))
+	}
+	r.handlers[jobType] = handler
+}
+
+// Start launches the worker pools and the loop that reaps stalled jobs.
+// Retries wait in the delayed set, so a cache.Promoter must be running
+// somewhere for them to come back.
+func (r *Runtime) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return
+	}
+	r.started = true
+
+	runCtx, stop := context.WithCancel(context.Background())
+	jobCtx, abort := context.WithCancel(context.Background())
+	r.stop, r.abort = stop, abort
+
+	queueNames := make([]string, 0, len(r.config.Queues))
+	for _, queue := range r.config.Queues {
+		queueNames = append(queueNames, queue.Name)
+
+		consumer := r.client.NewConsumer(queue.Name, "", queue.Visibility)
+		r.consumers = append(r.consumers, consumer)
+
+		r.loops.Add(1)
+		go func() {
+			defer r.loops.Done()
+			consumer.RunHeartbeat(runCtx, r.config.HeartbeatInterval)
+		}()
+
+		for range queue.Concurrency {
+			r.wg.Add(1)
+			go func() {
+				defer r.wg.Done()
+				r.run(runCtx, jobCtx, consumer, queue)
+			}()
+		}
+	}
+
+	r.loops.Add(1)
+	go func() {
+		defer r.loops.Done()
+		r.client.RunReaper(runCtx, queueNames, r.config.ReapInterval, r.config.DeadWorkerAfter)
+	}()
+}
+
+// Shutdown stops taking new jobs and waits for running ones until ctx is
+// done. Jobs still running then are cancelled and handed back to their
+// queue for another worker.
+func (r *Runtime) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.started {
+		r.mu.Unlock()
+		return nil
+	}
+	r.mu.Unlock()
+
+	r.stop()
+
+	drained := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = fmt.Errorf("worker: jobs still running at shutdown deadline: %w", ctx.Err())
+		r.abort()
+		<-drained
+	}
+	r.abort()
+	r.loops.Wait()
+
+	// Fresh context: ctx may already be done, and releasing is what keeps
+	// cancelled jobs from waiting for the reaper
+	releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, consumer := range r.consumers {
+		released, releaseErr := consumer.Close(releaseCtx)
+		if releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		} else if released > 0 {
+			slog.Info("Worker: returned unfinished jobs", slog.String("queue", consumer.Queue()), slog.Int("count", released))
+		}
+	}
+	return err
+}
+
+func (r *Runtime) run(runCtx, jobCtx context.Context, consumer *cache.Consumer, queue QueueConfig) {
+	for runCtx.Err() == nil {
+		delivery, err := consumer.Receive(runCtx, r.config.PollWait)
+		if err != nil {
+			if runCtx.Err() != nil {
+				return
+			}
+			slog.Error("Worker: receive failed", slog.String("queue", queue.Name), slog.Any("error", err))
+			sleep(runCtx, r.config.PollWait)
+			continue
+		}
+		if delivery == nil {
+			continue
+		}
+
+		r.process(jobCtx, consumer, queue, delivery)
+	}
+}
+
+func (r *Runtime) process(ctx context.Context, consumer *cache.Consumer, queue QueueConfig, delivery *cache.Delivery) {
+	// Everything the handler logs is tagged with the job
+	ctx = logging.With(ctx,
+		slog.String("queue", queue.Name),
+		slog.String("job_type", delivery.Type),
+		slog.String("job_id", delivery.ID),
+		slog.Int("attempt", delivery.Attempt),
+	)
+	ctx, span := tracing.Tracer().Start(ctx, "job "+delivery.Type,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingDestinationName(queue.Name),
+			semconv.MessagingMessageID(delivery.ID),
+		),
+	)
+	defer span.End()
+
+	handler, ok := r.handlers[delivery.Type]
+	if !ok {
+		r.deadLetter(ctx, consumer, delivery, fmt.Errorf("no handler registered for job type %q", delivery.Type))
+		return
+	}
+
+	err := r.invoke(ctx, consumer, queue, handler, delivery)
+	tracing.RecordError(span, err)
+	// The handler may have been cancelled by shutdown; settle the job anyway
+	settleCtx := context.WithoutCancel(ctx)
+
+	switch {
+	case err == nil:
+		if ackErr := consumer.Ack(settleCtx, delivery); ackErr != nil {
+			slog.ErrorContext(ctx, "Worker: ack failed", slog.Any("error", ackErr))
+		}
+	case ctx.Err() != nil:
+		// Cancelled by shutdown; Close hands it back without counting a failure
+	case IsPermanent(err) || delivery.Attempt >= queue.MaxAttempts:
+		r.deadLetter(settleCtx, consumer, delivery, err)
+	default:
+		delay := r.backoff(delivery.Attempt)
+		slog.WarnContext(ctx, "Worker: job failed, retrying",
+			slog.Int("max_attempts", queue.MaxAttempts), slog.Duration("retry_in", delay), slog.Any("error", err))
+		if nackErr := consumer.Nack(settleCtx, delivery, delay); nackErr != nil {
+			slog.ErrorContext(ctx, "Worker: nack failed", slog.Any("error", nackErr))
+		}
+	}
+}
+
+// invoke runs the handler, extending the job's lease while it works and
+// turning panics into errors.
+func (r *Runtime) invoke(ctx context.Context, consumer *cache.Consumer, queue QueueConfig, handler Handler, delivery *cache.Delivery) (err error) {
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(queue.Visibility / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := consumer.Extend(ctx, delivery); err != nil && ctx.Err() == nil {
+					slog.WarnContext(ctx, "Worker: extending lease failed", slog.Any("error", err))
+				}
+			}
+		}
+	}()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v\n%s", recovered, debug.Stack())
+		}
+	}()
+
+	return handler(ctx, delivery)
+}
+
+func (r *Runtime) deadLetter(ctx context.Context, consumer *cache.Consumer, delivery *cache.Delivery, reason error) {
+	slog.ErrorContext(ctx, "Worker: dead-lettering job", slog.Any("error", reason))
+	if err := consumer.DeadLetter(ctx, delivery, reason); err != nil {
+		slog.ErrorContext(ctx, "Worker: dead-lettering failed", slog.Any("error", err))
+	}
+}
+
+// backoff doubles the delay per attempt and adds up to 20% jitter so jobs
+// that failed together do not retry together.
+func (r *Runtime) backoff(attempt int) time.Duration {
+	delay := r.config.MaxBackoff
+	if attempt < 32 {
+		delay = min(r.config.BaseBackoff<<(attempt-1), r.config.MaxBackoff)
+	}
+	return delay + time.Duration(rand.Int64N(int64(delay)/5+1))
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+// QueueNames lists the queues the runtime consumes.
+func (r *Runtime) QueueNames() []string {
+	names := make([]string, 0, len(r.config.Queues))
+	for _, queue := range r.config.Queues {
+		names = append(names, queue.Name)
+	}
+	return names
+}